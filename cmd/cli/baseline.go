@@ -0,0 +1,79 @@
+// cmd/cli/baseline.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/zenithax-cc/diting/internal/config"
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// loadBaseline 读取基线文件,基线为之前 -j 或 -o 输出的JSON
+func loadBaseline(path string) (*model.HardwareInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取基线文件失败: %w", err)
+	}
+	baseline, err := model.Unmarshal[model.HardwareInfo](data)
+	if err != nil {
+		return nil, fmt.Errorf("基线文件 %s 不是有效的JSON: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// baselineIgnoreFields 返回比对时忽略的字段:每次采集都会变化的 model.VolatileFields、extra
+// 和配置文件中的 baseline.ignore_fields,configFile 为空时不读取配置文件。
+// 采集时间、-j 输出中的 errors 和 warnings 始终不参与比较
+func baselineIgnoreFields(configFile string, extra []string) ([]string, error) {
+	ignore := slices.Concat(model.VolatileFields, extra)
+	if configFile == "" {
+		return ignore, nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(ignore, cfg.Baseline.IgnoreFields), nil
+}
+
+// compareBaseline 将本次输出与基线比较,本次输出按 filter 处理,与生成基线时的 -include/-exclude 保持一致
+func compareBaseline(baseline *model.HardwareInfo, out *cliOutput, filter sectionFilter, ignore []string) (*model.HardwareDiff, error) {
+	data, err := marshalJSON(out.HardwareInfo, filter)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := model.Unmarshal[model.HardwareInfo](data)
+	if err != nil {
+		return nil, err
+	}
+	return baseline.Diff(cur, ignore...), nil
+}
+
+// renderBaselineDiff 按输出格式写入比对结果
+func renderBaselineDiff(w io.Writer, diff *model.HardwareDiff, baselineFile, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	case "yaml":
+		data, err := marshalYAML(diff, sectionFilter{})
+		if err != nil {
+			return fmt.Errorf("YAML编码失败: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	if diff.Empty() {
+		_, err := fmt.Fprintf(w, "硬件与基线 %s 一致\n", baselineFile)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "硬件与基线 %s 相比发生变化:\n%s", baselineFile, diff)
+	return err
+}
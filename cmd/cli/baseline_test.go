@@ -0,0 +1,153 @@
+// cmd/cli/baseline_test.go
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// writeBaseline 用回放数据生成基线文件,modify 不为 nil 时修改后再写入,模拟维护前后的硬件变化
+func writeBaseline(t *testing.T, host string, modify func(baseline map[string]any)) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if _, stderr, code := runCLI(t, "-q", "-replay", host, "-m", "system,memory", "-o", path); code != exitOK {
+		t.Fatalf("generate baseline: exit %d, stderr %q", code, stderr)
+	}
+	if modify == nil {
+		return path
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baseline map[string]any
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		t.Fatal(err)
+	}
+	modify(baseline)
+	if data, err = json.Marshal(baseline); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBaseline(t *testing.T) {
+	const host = "../../internal/collector/testdata/host"
+	args := func(extra ...string) []string {
+		return append([]string{"-replay", host, "-m", "system,memory"}, extra...)
+	}
+
+	// 硬件未变化,采集时间不同不算变化;回放数据中的 /proc/stat 固定了启动时间,两次采集结果一致
+	unchanged := writeBaseline(t, host, nil)
+	stdout, stderr, code := runCLI(t, args("-baseline", unchanged)...)
+	if code != exitOK || !strings.Contains(stdout, "一致") {
+		t.Errorf("unchanged: exit %d, stdout %q, stderr %q, want exit 0", code, stdout, stderr)
+	}
+
+	// 运行时间、负载和内存用量默认忽略,不需要配置
+	volatile := writeBaseline(t, host, func(baseline map[string]any) {
+		system := baseline["system"].(map[string]any)
+		system["uptime"] = map[string]any{"seconds": "1.00", "boot_time": "2020-01-01T00:00:00Z"}
+		system["load_average"] = map[string]any{"load1": "9.99"}
+		baseline["memory"].(map[string]any)["used"] = 1
+	})
+	if stdout, stderr, code = runCLI(t, args("-baseline", volatile)...); code != exitOK {
+		t.Errorf("volatile: exit %d, stdout %q, stderr %q, want exit 0", code, stdout, stderr)
+	}
+
+	// 基线中的内核版本与本次采集不同
+	changed := writeBaseline(t, host, func(baseline map[string]any) {
+		system := baseline["system"].(map[string]any)
+		system["kernel"].(map[string]any)["release"] = "6.5.0-21-generic"
+	})
+	stdout, _, code = runCLI(t, args("-baseline", changed)...)
+	if code != exitChanged {
+		t.Errorf("changed: exit %d, want %d", code, exitChanged)
+	}
+	for _, want := range []string{"相比发生变化", "system: changed\n", "6.5.0-21-generic -> 6.8.0-45-generic\n"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("changed: stdout missing %q:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "memory") {
+		t.Errorf("changed: unchanged memory reported:\n%s", stdout)
+	}
+
+	// JSON 格式输出结构化的差异
+	stdout, _, code = runCLI(t, args("-baseline", changed, "-format", "json")...)
+	var diff model.HardwareDiff
+	if err := json.Unmarshal([]byte(stdout), &diff); err != nil {
+		t.Fatalf("-format json: %v\n%s", err, stdout)
+	}
+	if code != exitChanged || len(diff.Sections) != 1 || diff.Sections[0].Section != "system" {
+		t.Errorf("-format json: exit %d, diff %+v", code, diff)
+	}
+
+	// -q 只通过退出码报告
+	if stdout, _, code = runCLI(t, args("-q", "-baseline", changed)...); code != exitChanged || stdout != "" {
+		t.Errorf("-q: exit %d, stdout %q, want exit %d and no output", code, stdout, exitChanged)
+	}
+}
+
+func TestBaselineIgnoreFields(t *testing.T) {
+	const host = "../../internal/collector/testdata/host"
+	changed := writeBaseline(t, host, func(baseline map[string]any) {
+		baseline["system"].(map[string]any)["kernel"].(map[string]any)["release"] = "6.5.0-21-generic"
+	})
+
+	// 命令行忽略变化的字段
+	stdout, _, code := runCLI(t, "-replay", host, "-m", "system,memory", "-baseline", changed, "-baseline-ignore", "system.boot_id,system.kernel.release")
+	if code != exitOK {
+		t.Errorf("-baseline-ignore: exit %d, stdout %q, want exit 0", code, stdout)
+	}
+
+	// 配置文件中的 baseline.ignore_fields
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("baseline:\n  ignore_fields: [system.kernel]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _, code = runCLI(t, "-replay", host, "-m", "system,memory", "-baseline", changed, "-c", configFile)
+	if code != exitOK {
+		t.Errorf("-c: exit %d, stdout %q, want exit 0", code, stdout)
+	}
+
+	// 配置和命令行在默认忽略的易变字段之上追加
+	got, err := baselineIgnoreFields(configFile, []string{"system.boot_id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := slices.Concat(model.VolatileFields, []string{"system.boot_id", "system.kernel"}); !slices.Equal(got, want) {
+		t.Errorf("baselineIgnoreFields() = %q, want %q", got, want)
+	}
+	if got, err := baselineIgnoreFields("", nil); err != nil || !slices.Equal(got, model.VolatileFields) {
+		t.Errorf("baselineIgnoreFields() without config = %q, %v, want the volatile fields", got, err)
+	}
+}
+
+func TestLoadBaselineInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := loadBaseline(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("loadBaseline() of a missing file succeeded")
+	}
+
+	path := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(path, []byte(`{"system": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBaseline(path); err == nil || !strings.Contains(err.Error(), "不是有效的JSON") {
+		t.Errorf("loadBaseline() = %v, want an invalid JSON error", err)
+	}
+	if _, stderr, code := runCLI(t, "-baseline", path); code != exitFailed || !strings.Contains(stderr, "不是有效的JSON") {
+		t.Errorf("exit %d, stderr %q, want exit %d", code, stderr, exitFailed)
+	}
+}
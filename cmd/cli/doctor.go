@@ -0,0 +1,130 @@
+// cmd/cli/doctor.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// doctorTool 采集依赖的外部工具
+type doctorTool struct {
+	name    string
+	purpose string
+	hint    string
+}
+
+var doctorTools = []doctorTool{
+	{name: "ethtool", purpose: "网卡驱动、链路和环形缓冲区信息", hint: "安装 ethtool"},
+	{name: "lspci", purpose: "PCI设备厂商和型号名称", hint: "安装 pciutils"},
+	{name: "dmidecode", purpose: "内存条、主板和BIOS详细信息", hint: "安装 dmidecode"},
+	{name: "smartctl", purpose: "磁盘SMART健康信息", hint: "安装 smartmontools"},
+	{name: "nvme", purpose: "NVMe盘健康信息", hint: "安装 nvme-cli"},
+	{name: "nvidia-smi", purpose: "NVIDIA GPU信息", hint: "安装 NVIDIA 驱动(无GPU的主机可忽略)"},
+	{name: "lldpctl", purpose: "上联交换机LLDP信息", hint: "安装并启动 lldpd"},
+}
+
+// doctorPath 需要检查读取权限的sysfs/procfs路径
+type doctorPath struct {
+	path    string
+	purpose string
+}
+
+var doctorPaths = []doctorPath{
+	{path: "/sys/class/net", purpose: "网络接口"},
+	{path: "/sys/bus/pci/devices", purpose: "PCI设备"},
+	{path: "/sys/block", purpose: "块设备"},
+	{path: "/sys/class/hwmon", purpose: "温度和风扇传感器"},
+	{path: "/sys/class/dmi/id/product_serial", purpose: "产品序列号(需要root)"},
+	{path: "/proc/mdstat", purpose: "MD软RAID"},
+}
+
+// lookPath 查找外部工具的路径
+var lookPath = executor.LookPath
+
+// runDoctor 检查采集环境并将就绪报告写入 w,存在问题时返回 false
+func runDoctor(w io.Writer) bool {
+	ok := true
+
+	fmt.Fprintln(w, "权限:")
+	if os.Geteuid() == 0 {
+		fmt.Fprintln(w, "  [OK]      以root用户运行")
+	} else {
+		fmt.Fprintln(w, "  [WARN]    非root用户运行,序列号、dmidecode、SMART等信息将无法采集,建议使用root运行")
+		ok = false
+	}
+
+	fmt.Fprintln(w, "外部工具:")
+	for _, tool := range doctorTools {
+		path, err := lookPath(tool.name)
+		if err != nil {
+			fmt.Fprintf(w, "  [MISSING] %-11s %s不可用,%s\n", tool.name, tool.purpose, tool.hint)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(w, "  [OK]      %-11s %s\n", tool.name, path)
+	}
+
+	fmt.Fprintln(w, "sysfs/procfs:")
+	for _, p := range doctorPaths {
+		path := utils.HostPath(p.path)
+		status, detail := checkReadable(path)
+		// 路径不存在通常只是没有该类设备,不视为问题
+		if status != "OK" && status != "MISSING" {
+			ok = false
+		}
+		fmt.Fprintf(w, "  [%s]%*s%-33s %s%s\n", status, 8-len(status), "", p.path, p.purpose, detail)
+	}
+
+	// 模块可用性只作提示:缺少工具时模块仍会采集,只是相应字段为空,不影响返回值
+	fmt.Fprintln(w, "采集模块:")
+	for _, m := range collector.BuiltinModules() {
+		var missing []string
+		for _, tool := range m.Tools {
+			if _, err := lookPath(tool); err != nil {
+				missing = append(missing, tool)
+			}
+		}
+
+		status, detail := "OK", ""
+		switch {
+		case len(missing) > 0:
+			status, detail = "PARTIAL", ",缺少 "+strings.Join(missing, ", ")
+		case m.RequiresRoot && os.Geteuid() != 0:
+			status, detail = "PARTIAL", ",需要root"
+		}
+		fmt.Fprintf(w, "  [%s]%*s%-11s %s%s\n", status, 8-len(status), "", m.Name, m.Description, detail)
+	}
+
+	return ok
+}
+
+// checkReadable 检查路径是否存在且可读
+func checkReadable(path string) (string, string) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "MISSING", ",路径不存在(该类设备可能不存在)"
+		}
+		return "DENIED", ": " + err.Error()
+	}
+
+	if fi.IsDir() {
+		_, err = os.ReadDir(path)
+	} else {
+		_, err = utils.ReadSysfsFile(path)
+	}
+	if err != nil {
+		if utils.IsPermissionError(err, nil) {
+			return "DENIED", ",权限不足"
+		}
+		return "ERROR", ": " + err.Error()
+	}
+
+	return "OK", ""
+}
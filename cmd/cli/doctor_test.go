@@ -0,0 +1,43 @@
+// cmd/cli/doctor_test.go
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestRunDoctorReportsMissingTool(t *testing.T) {
+	saved := lookPath
+	lookPath = func(name string) (string, error) {
+		if name == "smartctl" {
+			return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+		}
+		return "/usr/sbin/" + name, nil
+	}
+	t.Cleanup(func() { lookPath = saved })
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/class/net/eth0/address": "52:54:00:12:34:56\n",
+		"/proc/mdstat":                "Personalities :\n",
+	})
+
+	var buf strings.Builder
+	if runDoctor(&buf) {
+		t.Error("runDoctor() = true with smartctl missing")
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"[MISSING] smartctl    磁盘SMART健康信息不可用,安装 smartmontools\n",
+		"[OK]      ethtool     /usr/sbin/ethtool\n",
+		"[OK]      /sys/class/net",
+		"[MISSING] /sys/block",
+		"[PARTIAL] disk        块设备、分区和磁盘健康信息,缺少 smartctl\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// cmd/cli/field.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// lookupField 按以 "." 连接的JSON字段路径取出 out 中的值,路径中的数字选取列表的第几个元素(从0开始),
+// 如 system.kernel.release、gpu.0.name
+func lookupField(out *cliOutput, path string) (json.RawMessage, error) {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("JSON编码失败: %w", err)
+	}
+
+	cur := json.RawMessage(data)
+	for seg := range strings.SplitSeq(path, ".") {
+		switch bytes.TrimSpace(cur)[0] {
+		case '{':
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal(cur, &obj); err != nil {
+				return nil, err
+			}
+			v, ok := obj[seg]
+			if !ok {
+				return nil, fmt.Errorf("字段 %s 不存在", path)
+			}
+			cur = v
+		case '[':
+			var list []json.RawMessage
+			if err := json.Unmarshal(cur, &list); err != nil {
+				return nil, err
+			}
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(list) {
+				return nil, fmt.Errorf("字段 %s 中 %s 不是有效的列表下标(共 %d 个元素)", path, seg, len(list))
+			}
+			cur = list[i]
+		default:
+			return nil, fmt.Errorf("字段 %s 不存在", path)
+		}
+	}
+	return cur, nil
+}
+
+// printField 输出字段的值:字符串输出其内容,其他值输出紧凑的JSON
+func printField(w io.Writer, value json.RawMessage) error {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		_, err := fmt.Fprintln(w, s)
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, buf.String())
+	return err
+}
@@ -0,0 +1,48 @@
+// cmd/cli/field_test.go
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestLookupField(t *testing.T) {
+	out := &cliOutput{HardwareInfo: &model.HardwareInfo{
+		Hostname: "node-1",
+		System:   &model.System{Kernel: model.Kernel{Release: "6.8.0"}},
+		Memory:   &model.Memory{Total: 64 << 30},
+		GPU:      []model.GPU{{}, {PCI: model.PCI{PCIAddr: "0000:3b:00.0"}}},
+	}}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"hostname", "node-1\n"},
+		{"system.kernel.release", "6.8.0\n"},
+		{"memory.total", "68719476736\n"},
+		{"gpu.1.pci", `{"pci_address":"0000:3b:00.0"}` + "\n"},
+	}
+	for _, tt := range tests {
+		value, err := lookupField(out, tt.path)
+		if err != nil {
+			t.Errorf("lookupField(%s) error = %v", tt.path, err)
+			continue
+		}
+		var buf strings.Builder
+		if err := printField(&buf, value); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("field %s = %q, want %q", tt.path, buf.String(), tt.want)
+		}
+	}
+
+	for _, path := range []string{"nosuch", "system.kernel.release.major", "gpu.2", "gpu.name", "disk"} {
+		if _, err := lookupField(out, path); err == nil {
+			t.Errorf("lookupField(%s) succeeded", path)
+		}
+	}
+}
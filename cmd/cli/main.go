@@ -4,78 +4,458 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
-	"hardware-collector/internal/collector"
-	"hardware-collector/pkg/models"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/internal/collector/network"
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/redact"
+	"github.com/zenithax-cc/diting/pkg/replay"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// 退出码
+const (
+	exitOK      = 0
+	exitFailed  = 1
+	exitChanged = 2 // -baseline 模式下硬件与基线不一致
+	exitTimeout = 3 // 采集超时,输出的是部分结果
 )
 
 func main() {
-	modules := flag.String("m", "", "采集模块(system,memory,disk,network,gpu),逗号分隔")
+	modules := flag.String("m", "", "采集模块(product,system,memory,disk,network,gpu,sensor,power,infiniband,pci,service),逗号分隔,service 只在显式指定时采集")
 	detailed := flag.Bool("d", false, "显示详细信息")
-	jsonOutput := flag.Bool("j", false, "JSON格式输出")
+	jsonOutput := flag.Bool("j", false, "JSON格式输出(等同于 -format json)")
+	format := flag.String("format", "text", "输出格式(text,json,yaml)")
+	units := flag.String("units", "gib", "文本输出中容量的单位(bytes,mib,gib,human),不影响JSON/YAML中的字节数")
 	debug := flag.Bool("D", false, "调试模式")
+	timeout := flag.Duration("timeout", 60*time.Second, "采集超时时间")
+	replayDir := flag.String("replay", "", "离线回放模式,从采集包目录(或其 .tar/.tar.gz/.tgz 打包文件)读取工具输出和sysfs文件,而不是读取本机")
+	captureDir := flag.String("capture", os.Getenv(replay.CaptureEnv), "采集时将工具输出和sysfs文件记录到该目录,供 -replay 回放(也可通过 "+replay.CaptureEnv+" 环境变量设置)")
+	redactFields := flag.String("redact", "", "输出前脱敏的字段(JSON字段名),逗号分隔,如 product_serial,mac_address,替换为以 -redact-key 为密钥的 HMAC-SHA256 摘要")
+	redactKey := flag.String("redact-key", os.Getenv(redact.KeyEnv), "脱敏摘要的密钥(也可通过 "+redact.KeyEnv+" 环境变量设置),使用 -redact 时必填")
+	anonymize := flag.Bool("anonymize", false, "输出前将主机名替换为加盐的 HMAC-SHA256 摘要,盐值由 -anonymize-salt 指定")
+	anonymizeSalt := flag.String("anonymize-salt", os.Getenv(redact.HostnameSaltEnv), "主机名匿名化的盐值(也可通过 "+redact.HostnameSaltEnv+" 环境变量设置)")
+	excludeDevices := flag.String("exclude-devices", "", "跳过不采集的设备(设备名或PCI地址,支持通配符),逗号分隔,如 sdb,eth2,0000:3b:00.0")
+	doctor := flag.Bool("doctor", false, "检查采集环境(外部工具、权限),打印就绪报告后退出")
+	noColor := flag.Bool("no-color", false, "禁用彩色输出(等同于设置 NO_COLOR 环境变量)")
+	outputFile := flag.String("o", "", "同时将结果写入该文件(格式由 -format/-j 指定,text 时写入JSON),终端仍输出文本")
+	profileName := flag.String("profile", "full", "采集配置(full,minimal),minimal 只采集内存、负载、链路状态等开销小的指标")
+	includeSections := flag.String("include", "", "JSON/YAML输出中即使为空也保留的顶层字段,逗号分隔,如 gpu,warnings")
+	excludeSections := flag.String("exclude", "", "JSON/YAML输出中删除的顶层字段,逗号分隔,如 network,disk")
+	sysfsOnly := flag.Bool("sysfs-only", false, "网络模块只读取 /sys、/proc,不执行 ethtool、lldpctl、ip 等外部命令")
+	quiet := flag.Bool("q", false, "静默模式:不输出采集结果和警告(错误仍输出到标准错误),通过退出码判断是否成功,指定 -o 时仍写入文件,指定 -field 时只输出该字段的值")
+	field := flag.String("field", "", "终端只输出该字段的值(以 \".\" 连接的JSON字段路径,数字为列表下标),如 system.kernel.release,gpu.0.name")
+	serveAddr := flag.String("serve", "", "调试模式:在该地址(如 127.0.0.1:8080)启动HTTP服务,\"/\" 返回最近一次采集结果,\"/collect\" 重新采集")
+	baselineFile := flag.String("baseline", "", "采集后与该基线文件(之前 -j 或 -o 输出的JSON)比较,输出差异,硬件发生变化时以退出码 2 退出")
+	baselineIgnore := flag.String("baseline-ignore", "", "比对基线时额外忽略的字段(以 \".\" 连接的JSON字段路径),逗号分隔,如 system.boot_id,network.neighbors;运行时间、负载、内存用量等易变字段默认忽略")
+	configFile := flag.String("c", "", "配置文件路径,用于读取 baseline.ignore_fields")
 	flag.Parse()
 
-	coll, err := collector.NewCollector("/tmp/hardware-collector")
+	if *noColor {
+		_ = os.Setenv("NO_COLOR", "1")
+	}
+
+	if *doctor {
+		if !runDoctor(os.Stdout) {
+			os.Exit(exitFailed)
+		}
+		return
+	}
+
+	if *jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "text", "json", "yaml":
+	default:
+		fmt.Fprintf(os.Stderr, "不支持的输出格式: %s\n", *format)
+		os.Exit(exitFailed)
+	}
+	switch sizeUnit(*units) {
+	case unitBytes, unitMiB, unitGiB, unitHuman:
+	default:
+		fmt.Fprintf(os.Stderr, "不支持的容量单位: %s\n", *units)
+		os.Exit(exitFailed)
+	}
+
+	var filter sectionFilter
+	if *includeSections != "" {
+		filter.include = strings.Split(*includeSections, ",")
+	}
+	if *excludeSections != "" {
+		filter.exclude = strings.Split(*excludeSections, ",")
+	}
+	if err := filter.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "输出字段配置错误: %v\n", err)
+		os.Exit(exitFailed)
+	}
+
+	if *excludeDevices != "" {
+		utils.SetExcludedDevices(strings.Split(*excludeDevices, ","))
+	}
+
+	if *replayDir != "" {
+		if err := replay.Enable(*replayDir); err != nil {
+			fmt.Fprintf(os.Stderr, "启用回放模式失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		defer replay.Disable()
+	}
+
+	if *captureDir != "" {
+		if err := replay.EnableCapture(*captureDir); err != nil {
+			fmt.Fprintf(os.Stderr, "启用采集记录失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+	}
+
+	coll, err := collector.NewCollector("/tmp/hardware-collector", false)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitFailed)
 	}
 
+	profile, err := utils.ParseProfile(*profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "采集配置错误: %v\n", err)
+		os.Exit(exitFailed)
+	}
+	coll.SetProfile(profile)
+	network.SetSysfsOnly(*sysfsOnly)
+
 	var moduleList []string
 	if *modules != "" {
 		moduleList = strings.Split(*modules, ",")
 	}
+	if err := collector.ValidateModules(moduleList); err != nil {
+		fmt.Fprintf(os.Stderr, "采集模块配置错误: %v\n", err)
+		os.Exit(exitFailed)
+	}
+
+	var redactor *redact.Redactor
+	if *redactFields != "" {
+		if *redactKey == "" {
+			fmt.Fprintf(os.Stderr, "-redact 需要通过 -redact-key 或 %s 环境变量指定密钥\n", redact.KeyEnv)
+			os.Exit(exitFailed)
+		}
+		if redactor, err = redact.New(strings.Split(*redactFields, ","), redact.ModeHash, *redactKey); err != nil {
+			fmt.Fprintf(os.Stderr, "脱敏失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+	}
+
+	if *anonymize && *anonymizeSalt == "" {
+		fmt.Fprintf(os.Stderr, "-anonymize 需要通过 -anonymize-salt 或 %s 环境变量指定盐值\n", redact.HostnameSaltEnv)
+		os.Exit(exitFailed)
+	}
+
+	var (
+		baseline     *model.HardwareInfo
+		ignoreFields []string
+	)
+	if *baselineFile != "" {
+		if baseline, err = loadBaseline(*baselineFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(exitFailed)
+		}
+		var extra []string
+		if *baselineIgnore != "" {
+			extra = strings.Split(*baselineIgnore, ",")
+		}
+		if ignoreFields, err = baselineIgnoreFields(*configFile, extra); err != nil {
+			fmt.Fprintf(os.Stderr, "加载配置失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+	}
+
+	if *field != "" && (*baselineFile != "" || *serveAddr != "") {
+		fmt.Fprintf(os.Stderr, "-field 不能与 -baseline 或 -serve 同时使用\n")
+		os.Exit(exitFailed)
+	}
 
-	ctx := context.Background()
+	if *serveAddr != "" {
+		if err := runServe(*serveAddr, coll, moduleList, *timeout, redactor); err != nil {
+			fmt.Fprintf(os.Stderr, "调试服务退出: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	exitCode := exitOK
 	info, err := coll.Collect(ctx, moduleList)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "采集失败: %v\n", err)
-		os.Exit(1)
+		if info == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			fmt.Fprintf(os.Stderr, "采集失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		fmt.Fprintf(os.Stderr, "采集超时(%s),以下为部分结果: %v\n", *timeout, err)
+		exitCode = exitTimeout
 	}
 
-	if *jsonOutput {
-		data, _ := json.MarshalIndent(info, "", "  ")
-		fmt.Println(string(data))
-	} else if *detailed {
-		printDetailed(info)
-	} else {
-		printSimple(info)
+	if !*quiet {
+		if warnings := coll.PermissionWarnings(); len(warnings) > 0 {
+			fmt.Fprintf(os.Stderr, "以下内容因权限不足未能采集,请以root用户运行: %s\n", strings.Join(warnings, ", "))
+		}
+		for _, w := range coll.Warnings() {
+			fmt.Fprintf(os.Stderr, "警告: %s\n", w)
+		}
+	}
+
+	if redactor != nil {
+		if info, err = redact.Apply(redactor, info); err != nil {
+			fmt.Fprintf(os.Stderr, "脱敏失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+	}
+	if *anonymize {
+		anonymized := *info
+		anonymized.Hostname = redact.HashHostname(info.Hostname, *anonymizeSalt)
+		info = &anonymized
+	}
+
+	var file io.Writer
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "创建输出文件失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		defer f.Close()
+		file = f
+	}
+	sinks := outputSinks(os.Stdout, file, *outputFile, *format)
+	if *quiet || baseline != nil || *field != "" {
+		// 静默模式下不输出到终端,基线模式下终端只输出差异,指定 -field 时终端只输出该字段的值
+		sinks = slices.DeleteFunc(sinks, func(s outputSink) bool { return s.w == os.Stdout })
+	}
+
+	// JSON/YAML 中附带各模块的问题,区分"模块未采集"和"模块采集失败"
+	out := &cliOutput{
+		HardwareInfo: info,
+		Errors:       coll.LastStats().Errors,
+		Warnings:     coll.Warnings(),
+	}
+	for _, sink := range sinks {
+		if err := render(sink.w, out, sink.format, *detailed, sizeUnit(*units), filter); err != nil {
+			fmt.Fprintf(os.Stderr, "输出到 %s 失败: %v\n", sink.name, err)
+			os.Exit(exitFailed)
+		}
+	}
+
+	if *field != "" {
+		value, err := lookupField(out, *field)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(exitFailed)
+		}
+		if err := printField(os.Stdout, value); err != nil {
+			fmt.Fprintf(os.Stderr, "输出字段失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+	}
+
+	if baseline != nil {
+		diff, err := compareBaseline(baseline, out, filter, ignoreFields)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "与基线比较失败: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		if !*quiet {
+			if err := renderBaselineDiff(os.Stdout, diff, *baselineFile, *format); err != nil {
+				fmt.Fprintf(os.Stderr, "输出差异失败: %v\n", err)
+				os.Exit(exitFailed)
+			}
+		}
+		// 部分结果与基线的差异不可信,超时优先以超时退出
+		if !diff.Empty() && exitCode == exitOK {
+			exitCode = exitChanged
+		}
 	}
 
-	if *debug {
+	if *debug && !*quiet {
 		fmt.Printf("\n[DEBUG] 采集时间: %s\n", info.Timestamp)
 	}
+
+	if exitCode != exitOK {
+		cancel()
+		os.Exit(exitCode)
+	}
+}
+
+// cliOutput CLI 的输出内容,JSON/YAML 中 errors 和 warnings 没有问题时省略
+type cliOutput struct {
+	*model.HardwareInfo
+	Errors   []utils.Warning `json:"errors,omitempty"`   // 采集失败的模块
+	Warnings []utils.Warning `json:"warnings,omitempty"` // 不影响结果的问题
+}
+
+// outputSink 一个输出目标及其格式
+type outputSink struct {
+	name   string
+	w      io.Writer
+	format string // text, json, yaml
 }
 
-func printSimple(info *models.HardwareInfo) {
-	fmt.Printf("主机名: %s\n", info.Hostname)
+// outputSinks 返回输出目标:file 为 nil(未指定 -o)时按 format 输出到 stdout;
+// 否则 stdout 输出文本,文件按 format 输出,format 为 text 时文件输出 JSON
+func outputSinks(stdout, file io.Writer, fileName, format string) []outputSink {
+	if file == nil {
+		return []outputSink{{name: "stdout", w: stdout, format: format}}
+	}
+
+	fileFormat := format
+	if fileFormat == "text" {
+		fileFormat = "json"
+	}
+	return []outputSink{
+		{name: "stdout", w: stdout, format: "text"},
+		{name: fileName, w: file, format: fileFormat},
+	}
+}
+
+// render 按格式将采集结果写入 w,text 格式下 detailed 为 true 时输出详细信息,
+// JSON/YAML 格式下按 filter 增删顶层字段
+func render(w io.Writer, out *cliOutput, format string, detailed bool, unit sizeUnit, filter sectionFilter) error {
+	switch {
+	case format == "json":
+		return streamJSON(w, out, filter)
+	case format == "yaml":
+		data, err := marshalYAML(out, filter)
+		if err != nil {
+			return fmt.Errorf("YAML编码失败: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case detailed:
+		return printDetailed(w, out.HardwareInfo)
+	default:
+		printSimple(w, out.HardwareInfo, unit)
+		return nil
+	}
+}
+
+func printSimple(w io.Writer, info *model.HardwareInfo, unit sizeUnit) {
+	fmt.Fprintf(w, "主机名: %s\n", info.Hostname)
 	if info.System != nil {
-		fmt.Printf("系统: %s %s\n", info.System.OS, info.System.PlatformVersion)
-		fmt.Printf("CPU: %s (%d核/%d线程)\n", info.System.CPUModel, info.System.CPUCores, info.System.CPUThreads)
+		fmt.Fprintf(w, "内核: %s\n", info.System.Kernel.Release)
+		if load := info.System.LoadAverage; load.Load1 != "" {
+			fmt.Fprintf(w, "负载: %s %s %s\n", load.Load1, load.Load5, load.Load15)
+		}
 	}
 	if info.Memory != nil {
-		fmt.Printf("内存: %.2fGB / %.2fGB (%.1f%%)\n",
-			float64(info.Memory.Used)/1024/1024/1024,
-			float64(info.Memory.Total)/1024/1024/1024,
+		fmt.Fprintf(w, "内存: %s / %s (%.1f%%)\n",
+			formatSize(info.Memory.Used, unit),
+			formatSize(info.Memory.Total, unit),
 			info.Memory.UsedPercent)
 	}
-	if len(info.Disk) > 0 {
-		fmt.Printf("磁盘: %d个分区\n", len(info.Disk))
+	if info.Disk != nil && len(info.Disk.BlockDevices) > 0 {
+		fmt.Fprintf(w, "磁盘: %d个块设备\n", len(info.Disk.BlockDevices))
 	}
-	if len(info.Network) > 0 {
-		fmt.Printf("网络: %d个接口\n", len(info.Network))
+	if info.Network != nil && len(info.Network.NetInterfaces) > 0 {
+		fmt.Fprintf(w, "网络: %d个接口\n", len(info.Network.NetInterfaces))
 	}
 	if len(info.GPU) > 0 {
-		fmt.Printf("GPU: %d个\n", len(info.GPU))
+		fmt.Fprintf(w, "GPU: %d个\n", len(info.GPU))
+	}
+	if info.Service != nil && info.Service.FailedCount > 0 {
+		fmt.Fprintf(w, "失败的服务: %s\n", strings.Join(info.Service.FailedUnits, ", "))
 	}
 }
 
-func printDetailed(info *models.HardwareInfo) {
-	data, _ := json.MarshalIndent(info, "", "  ")
-	fmt.Println(string(data))
+func printDetailed(w io.Writer, info *model.HardwareInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// sizeUnit 文本输出中容量的显示单位
+type sizeUnit string
+
+const (
+	unitBytes sizeUnit = "bytes"
+	unitMiB   sizeUnit = "mib"
+	unitGiB   sizeUnit = "gib"
+	unitHuman sizeUnit = "human" // 自动选择合适的单位
+)
+
+// formatSize 按指定单位格式化字节数
+func formatSize(bytes uint64, unit sizeUnit) string {
+	const (
+		kib = 1 << 10
+		mib = 1 << 20
+		gib = 1 << 30
+		tib = 1 << 40
+	)
+
+	switch unit {
+	case unitBytes:
+		return fmt.Sprintf("%dB", bytes)
+	case unitMiB:
+		return fmt.Sprintf("%.2fMiB", float64(bytes)/mib)
+	case unitHuman:
+		switch {
+		case bytes >= tib:
+			return fmt.Sprintf("%.2fTiB", float64(bytes)/tib)
+		case bytes >= gib:
+			return fmt.Sprintf("%.2fGiB", float64(bytes)/gib)
+		case bytes >= mib:
+			return fmt.Sprintf("%.2fMiB", float64(bytes)/mib)
+		case bytes >= kib:
+			return fmt.Sprintf("%.2fKiB", float64(bytes)/kib)
+		default:
+			return fmt.Sprintf("%dB", bytes)
+		}
+	default:
+		return fmt.Sprintf("%.2fGiB", float64(bytes)/gib)
+	}
+}
+
+// marshalJSON 将 v 编码为紧凑的 JSON,并按 filter 增删顶层字段
+func marshalJSON(v any, filter sectionFilter) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return filter.apply(data)
+}
+
+// marshalYAML 将 v 编码为 YAML。先经过 JSON 编码再转换为 yaml.Node,
+// 使字段名和顺序与 JSON 输出保持一致(模型只定义了 json 标签)。
+func marshalYAML(v any, filter sectionFilter) ([]byte, error) {
+	data, err := marshalJSON(v, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+
+	return yaml.Marshal(&node)
+}
+
+// resetYAMLStyle 清除从 JSON 解析得到的 flow/引号风格,输出为块风格的 YAML
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
 }
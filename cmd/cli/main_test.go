@@ -0,0 +1,219 @@
+// cmd/cli/main_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/redact"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	info := &model.HardwareInfo{
+		Hostname: "node-1",
+		System: &model.System{
+			Kernel:      model.Kernel{Release: "6.8.0"},
+			LoadAverage: model.LoadAverage{Load1: "0.50"},
+		},
+	}
+
+	data, err := marshalYAML(info, sectionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	// 字段名与 JSON 一致,块风格输出
+	for _, want := range []string{"hostname: node-1\n", "system:\n", "release: 6.8.0\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("YAML output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "{") {
+		t.Errorf("YAML output uses flow style:\n%s", out)
+	}
+	if strings.Index(out, "timestamp:") > strings.Index(out, "hostname:") {
+		t.Errorf("field order differs from JSON:\n%s", out)
+	}
+
+	// 形如数字的字符串仍解析为字符串
+	var decoded struct {
+		System struct {
+			LoadAverage struct {
+				Load1 any `yaml:"load1"`
+			} `yaml:"load_average"`
+		} `yaml:"system"`
+	}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := decoded.System.LoadAverage.Load1.(string); !ok || got != "0.50" {
+		t.Errorf("load1 = %#v, want string \"0.50\"", decoded.System.LoadAverage.Load1)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	const gib = 1 << 30
+	tests := []struct {
+		bytes uint64
+		unit  sizeUnit
+		want  string
+	}{
+		{64 * gib, unitBytes, "68719476736B"},
+		{64 * gib, unitMiB, "65536.00MiB"},
+		{64 * gib, unitGiB, "64.00GiB"},
+		{3 << 39, unitHuman, "1.50TiB"},
+		{64 * gib, unitHuman, "64.00GiB"},
+		{1536 << 10, unitHuman, "1.50MiB"},
+		{2048, unitHuman, "2.00KiB"},
+		{512, unitHuman, "512B"},
+		{gib / 2, unitGiB, "0.50GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.bytes, tt.unit); got != tt.want {
+			t.Errorf("formatSize(%d, %s) = %q, want %q", tt.bytes, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestPrintSimpleUnits(t *testing.T) {
+	info := &model.HardwareInfo{
+		Hostname: "node-1",
+		Memory:   &model.Memory{Total: 64 << 30, Used: 16 << 30, UsedPercent: 25},
+	}
+
+	var buf strings.Builder
+	printSimple(&buf, info, unitMiB)
+	if !strings.Contains(buf.String(), "内存: 16384.00MiB / 65536.00MiB (25.0%)\n") {
+		t.Errorf("printSimple() =\n%s", buf.String())
+	}
+
+	// JSON 输出始终为字节数
+	data, err := marshalJSON(info, sectionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"total":68719476736`) {
+		t.Errorf("JSON output = %s, want raw byte counts", data)
+	}
+}
+
+func TestRenderErrorsSection(t *testing.T) {
+	out := &cliOutput{
+		HardwareInfo: &model.HardwareInfo{Hostname: "node-1"},
+		Errors:       []utils.Warning{{Module: "disk", Message: "lsblk failed"}},
+		Warnings:     []utils.Warning{{Module: "gpu", Message: "nvidia-smi not found"}},
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, out, "json", false, unitGiB, sectionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Hostname string          `json:"hostname"`
+		Errors   []utils.Warning `json:"errors"`
+		Warnings []utils.Warning `json:"warnings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.Hostname != "node-1" || !slices.Equal(decoded.Errors, out.Errors) || !slices.Equal(decoded.Warnings, out.Warnings) {
+		t.Errorf("JSON output = %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := render(&buf, out, "yaml", false, unitGiB, sectionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "errors:\n    - module: disk\n      message: lsblk failed\n") {
+		t.Errorf("YAML output missing the errors section:\n%s", buf.String())
+	}
+
+	// 没有问题时省略
+	out.Errors, out.Warnings = nil, nil
+	for _, format := range []string{"json", "yaml"} {
+		buf.Reset()
+		if err := render(&buf, out, format, false, unitGiB, sectionFilter{}); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "errors") || strings.Contains(buf.String(), "warnings") {
+			t.Errorf("%s output with no problems = %s", format, buf.String())
+		}
+	}
+}
+
+func TestAnonymizeHostname(t *testing.T) {
+	const host = "../../internal/collector/testdata/host"
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 默认显示真实主机名
+	stdout, _, code := runCLI(t, "-replay", host, "-m", "system", "-field", "hostname")
+	if code != exitOK || stdout != hostname+"\n" {
+		t.Errorf("hostname = %q (exit %d), want %q", stdout, code, hostname)
+	}
+
+	stdout, _, code = runCLI(t, "-replay", host, "-m", "system", "-field", "hostname", "-anonymize", "-anonymize-salt", "s3cret")
+	if want := redact.HashHostname(hostname, "s3cret") + "\n"; code != exitOK || stdout != want {
+		t.Errorf("anonymized hostname = %q (exit %d), want %q", stdout, code, want)
+	}
+}
+
+// TestJSONOutputUnmarshal -j 的输出附带 errors、warnings,消费端用 model.Unmarshal 读取时不应失败
+func TestJSONOutputUnmarshal(t *testing.T) {
+	// 缺少 nvidia-smi 的采集结果,gpu 模块失败产生警告
+	host := t.TempDir()
+	if err := os.CopyFS(host, os.DirFS("../../internal/collector/testdata/host")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(host, "commands", "nvidia-smi-query-gpu=index,name,uuid,pci.bus_id-format=csv,noheader.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, code := runCLI(t, "-replay", host, "-m", "system,memory,gpu", "-j")
+	if code != exitOK {
+		t.Fatalf("exit %d, stderr %q", code, stderr)
+	}
+	var output map[string]any
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output["warnings"] == nil {
+		t.Fatalf("output has no warnings:\n%s", stdout)
+	}
+
+	info, err := model.Unmarshal[model.HardwareInfo]([]byte(stdout))
+	if err != nil {
+		t.Fatalf("Unmarshal() of -j output: %v", err)
+	}
+	if info.System == nil || info.Memory == nil {
+		t.Fatalf("Unmarshal() = %+v, want system and memory", info)
+	}
+
+	// 重新编码后与输出中的硬件信息一致
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	delete(output, "errors")
+	delete(output, "warnings")
+	if !reflect.DeepEqual(got, output) {
+		t.Errorf("round trip = %v, want %v", got, output)
+	}
+}
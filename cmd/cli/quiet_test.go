@@ -0,0 +1,73 @@
+// cmd/cli/quiet_test.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// cliEnv 设置时测试二进制直接作为 CLI 运行,用于检查输出和退出码
+const cliEnv = "DITING_TEST_RUN_CLI"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(cliEnv) == "1" {
+		main()
+		os.Exit(exitOK)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI 以 args 运行 CLI,返回标准输出、标准错误和退出码
+func runCLI(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), cliEnv+"=1")
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	default:
+		t.Fatal(err)
+	}
+	return out.String(), errOut.String(), code
+}
+
+func TestQuiet(t *testing.T) {
+	const host = "../../internal/collector/testdata/host"
+
+	stdout, stderr, code := runCLI(t, "-q", "-replay", host, "-m", "system,memory")
+	if code != exitOK || stdout != "" || stderr != "" {
+		t.Errorf("-q: exit %d, stdout %q, stderr %q, want exit 0 and no output", code, stdout, stderr)
+	}
+
+	// 与 -field 同时使用时只输出字段的值
+	stdout, _, code = runCLI(t, "-q", "-replay", host, "-m", "system", "-field", "system.kernel.release")
+	if code != exitOK || stdout != "6.8.0-45-generic\n" {
+		t.Errorf("-q -field: exit %d, stdout %q, want the kernel release", code, stdout)
+	}
+
+	// 错误仍输出到标准错误
+	stdout, stderr, code = runCLI(t, "-q", "-replay", host, "-m", "system", "-field", "system.nosuch")
+	if code != exitFailed || stdout != "" || !strings.Contains(stderr, "system.nosuch") {
+		t.Errorf("-q with a missing field: exit %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+
+	// 指定 -o 时仍写入文件
+	path := t.TempDir() + "/out.json"
+	stdout, _, code = runCLI(t, "-q", "-replay", host, "-m", "system", "-o", path)
+	if code != exitOK || stdout != "" {
+		t.Errorf("-q -o: exit %d, stdout %q", code, stdout)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"release": "6.8.0-45-generic"`) {
+		t.Errorf("output file = %s, %v", data, err)
+	}
+}
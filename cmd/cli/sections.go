@@ -0,0 +1,152 @@
+// cmd/cli/sections.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// sectionFilter 控制JSON/YAML输出中包含哪些顶层字段:
+// exclude 中的字段被删除,include 中的字段即使为空也输出(空列表为 [],空对象为 {})
+type sectionFilter struct {
+	include []string
+	exclude []string
+}
+
+// empty 是否未指定任何字段
+func (f sectionFilter) empty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// validate 检查字段名是否为输出中的顶层字段
+func (f sectionFilter) validate() error {
+	valid := sectionNames()
+	for _, name := range slices.Concat(f.include, f.exclude) {
+		if _, ok := valid[name]; !ok {
+			return fmt.Errorf("未知的输出字段 %q", name)
+		}
+	}
+	for _, name := range f.include {
+		if slices.Contains(f.exclude, name) {
+			return fmt.Errorf("字段 %q 不能同时被包含和排除", name)
+		}
+	}
+	return nil
+}
+
+// apply 按过滤条件处理编码后的JSON对象,保持字段原有顺序,强制包含的空字段追加在末尾
+func (f sectionFilter) apply(data []byte) ([]byte, error) {
+	if f.empty() {
+		return data, nil
+	}
+
+	keys, values, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	types := sectionNames()
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	write := func(key string, value json.RawMessage) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	for i, key := range keys {
+		if slices.Contains(f.exclude, key) {
+			continue
+		}
+		write(key, values[i])
+	}
+	for _, key := range f.include {
+		if slices.Contains(keys, key) {
+			continue
+		}
+		write(key, emptyJSON(types[key]))
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// decodeObject 按原有顺序解析JSON对象的顶层字段
+func decodeObject(data []byte) ([]string, []json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("输出不是JSON对象")
+	}
+
+	var keys []string
+	var values []json.RawMessage
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, tok.(string))
+		values = append(values, value)
+	}
+
+	return keys, values, nil
+}
+
+// sectionNames 返回 cliOutput 的全部顶层JSON字段名及其类型,嵌入结构体的字段展开到顶层
+func sectionNames() map[string]reflect.Type {
+	names := make(map[string]reflect.Type)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			switch {
+			case name == "-":
+			case field.Anonymous && name == "":
+				walk(field.Type)
+			case field.IsExported():
+				if name == "" {
+					name = field.Name
+				}
+				names[name] = field.Type
+			}
+		}
+	}
+	walk(reflect.TypeOf(cliOutput{}))
+	return names
+}
+
+// emptyJSON 返回类型 t 的空值的JSON编码:列表为 [],映射和结构体为 {},其余为零值
+func emptyJSON(t reflect.Type) json.RawMessage {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return json.RawMessage("[]")
+	case reflect.Map, reflect.Struct:
+		return json.RawMessage("{}")
+	}
+
+	data, err := json.Marshal(reflect.Zero(t).Interface())
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
@@ -0,0 +1,81 @@
+// cmd/cli/sections_test.go
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestSectionFilter(t *testing.T) {
+	out := &cliOutput{HardwareInfo: &model.HardwareInfo{
+		Hostname: "node-1",
+		Memory:   &model.Memory{Total: 1 << 30},
+		PCI:      []model.PCI{{}},
+	}}
+
+	tests := []struct {
+		name   string
+		filter sectionFilter
+		want   string
+	}{
+		{"no filter", sectionFilter{}, `"memory":{`},
+		{"exclude", sectionFilter{exclude: []string{"pci", "timestamp"}}, `{"hostname":"node-1","memory":{`},
+		{"include empty list", sectionFilter{include: []string{"gpu"}}, `,"gpu":[]}`},
+		{"include empty object", sectionFilter{include: []string{"service"}}, `,"service":{}}`},
+		{"include embedded field", sectionFilter{include: []string{"errors"}}, `,"errors":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := marshalJSON(out, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := string(data)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("marshalJSON() = %s, want it to contain %s", got, tt.want)
+			}
+			for _, name := range tt.filter.exclude {
+				if strings.Contains(got, `"`+name+`":`) {
+					t.Errorf("marshalJSON() = %s, excluded %q still present", got, name)
+				}
+			}
+		})
+	}
+
+	// 已存在的字段保持原值,不会被空值覆盖
+	data, err := marshalJSON(out, sectionFilter{include: []string{"memory"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), `"memory":`) != 1 || strings.Contains(string(data), `"memory":{}`) {
+		t.Errorf("marshalJSON() = %s, want memory kept once with its value", data)
+	}
+
+	// YAML 输出同样生效
+	yamlData, err := marshalYAML(out, sectionFilter{include: []string{"gpu"}, exclude: []string{"pci"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(yamlData), "gpu: []\n") || strings.Contains(string(yamlData), "pci:") {
+		t.Errorf("marshalYAML() =\n%s", yamlData)
+	}
+}
+
+func TestSectionFilterValidate(t *testing.T) {
+	tests := []struct {
+		filter  sectionFilter
+		wantErr bool
+	}{
+		{sectionFilter{include: []string{"gpu", "errors"}, exclude: []string{"pci"}}, false},
+		{sectionFilter{exclude: []string{"nosuch"}}, true},
+		{sectionFilter{include: []string{"HardwareInfo"}}, true},
+		{sectionFilter{include: []string{"gpu"}, exclude: []string{"gpu"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.filter.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("validate(%+v) error = %v, wantErr %v", tt.filter, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// cmd/cli/serve.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/pkg/redact"
+)
+
+// debugServer 调试用的HTTP服务,"/" 返回最近一次采集结果,"/collect" 触发重新采集
+type debugServer struct {
+	coll     *collector.Collector
+	modules  []string
+	timeout  time.Duration
+	redactor *redact.Redactor // 为空时不脱敏
+
+	mu     sync.Mutex
+	latest *cliOutput
+}
+
+// runServe 在 addr 上启动调试HTTP服务,阻塞直到服务退出
+func runServe(addr string, coll *collector.Collector, modules []string, timeout time.Duration, redactor *redact.Redactor) error {
+	s := &debugServer{coll: coll, modules: modules, timeout: timeout, redactor: redactor}
+
+	// 启动时先采集一次,"/" 不需要等待第一次请求触发采集
+	if _, err := s.collect(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "首次采集失败: %v\n", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "调试服务已启动: http://%s/ (重新采集: /collect)\n", addr)
+	srv := &http.Server{Addr: addr, Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
+
+func (s *debugServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleLatest)
+	mux.HandleFunc("/collect", s.handleCollect)
+	return mux
+}
+
+// collect 重新采集并更新最近一次结果,并发的请求由 Collector 的 singleflight 合并
+func (s *debugServer) collect(ctx context.Context) (*cliOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	info, err := s.coll.Collect(ctx, s.modules)
+	if info == nil {
+		if err == nil {
+			err = errors.New("采集结果为空")
+		}
+		return nil, err
+	}
+	if s.redactor != nil {
+		if info, err = redact.Apply(s.redactor, info); err != nil {
+			return nil, fmt.Errorf("脱敏失败: %w", err)
+		}
+	}
+
+	out := &cliOutput{
+		HardwareInfo: info,
+		Errors:       s.coll.LastStats().Errors,
+		Warnings:     s.coll.Warnings(),
+	}
+
+	s.mu.Lock()
+	s.latest = out
+	s.mu.Unlock()
+	return out, nil
+}
+
+func (s *debugServer) handleLatest(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	out := s.latest
+	s.mu.Unlock()
+
+	if out == nil {
+		var err error
+		if out, err = s.collect(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, out)
+}
+
+func (s *debugServer) handleCollect(w http.ResponseWriter, r *http.Request) {
+	out, err := s.collect(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, out)
+}
+
+// writeJSON 以缩进的JSON写出响应
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(data, '\n'))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/pkg/replay"
+)
+
+// newTestServer 返回回放 internal/collector/testdata/host 采集目录的调试服务
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	if err := replay.Enable("../../internal/collector/testdata/host"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(replay.Disable)
+
+	coll, err := collector.NewCollector(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &debugServer{coll: coll, modules: []string{"system", "memory"}, timeout: 10 * time.Second}
+	srv := httptest.NewServer(s.handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// getOutput 请求 url 并解码返回的JSON
+func getOutput(t *testing.T, url string) map[string]any {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("GET %s = %s, %s", url, resp.Status, resp.Header.Get("Content-Type"))
+	}
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("GET %s returned invalid JSON: %v", url, err)
+	}
+	return out
+}
+
+func TestServeLatest(t *testing.T) {
+	srv := newTestServer(t)
+
+	// 还没有采集结果时由第一次请求触发采集
+	out := getOutput(t, srv.URL+"/")
+	system, _ := out["system"].(map[string]any)
+	if system == nil || out["memory"] == nil {
+		t.Fatalf("GET / = %v, want the system and memory modules", out)
+	}
+	if out["disk"] != nil {
+		t.Errorf("GET / includes the disk module that was not requested")
+	}
+	first := out["timestamp"]
+
+	// 之后返回同一次采集结果,/collect 重新采集
+	if again := getOutput(t, srv.URL+"/"); again["timestamp"] != first {
+		t.Errorf("GET / timestamp = %v, want the cached %v", again["timestamp"], first)
+	}
+	time.Sleep(10 * time.Millisecond)
+	collected := getOutput(t, srv.URL+"/collect")
+	if collected["timestamp"] == first {
+		t.Error("GET /collect returned the cached result")
+	}
+	if latest := getOutput(t, srv.URL+"/"); latest["timestamp"] != collected["timestamp"] {
+		t.Error("GET / does not return the result of the last /collect")
+	}
+}
+
+func TestServeNotFound(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /metrics = %s, want 404", resp.Status)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestOutputSinksTee(t *testing.T) {
+	out := &cliOutput{HardwareInfo: &model.HardwareInfo{
+		Hostname: "node-1",
+		Memory:   &model.Memory{Total: 64 << 30, Used: 16 << 30, UsedPercent: 25},
+	}}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	sinks := outputSinks(&stdout, f, path, "text")
+	for _, sink := range sinks {
+		if err := render(sink.w, out, sink.format, false, unitGiB, sectionFilter{}); err != nil {
+			t.Fatalf("render to %s: %v", sink.name, err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(stdout.String(), "主机名: node-1\n") || !strings.Contains(stdout.String(), "内存: 16.00GiB / 64.00GiB") {
+		t.Errorf("stdout =\n%s\nwant the text summary", stdout.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info model.HardwareInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("file is not valid JSON: %v\n%s", err, data)
+	}
+	if info.Hostname != "node-1" || info.Memory == nil || info.Memory.Total != 64<<30 {
+		t.Errorf("file = %s", data)
+	}
+}
+
+func TestOutputSinksFormats(t *testing.T) {
+	var stdout, file bytes.Buffer
+	tests := []struct {
+		name         string
+		file         *bytes.Buffer
+		format       string
+		wantStdout   string
+		wantFile     string
+		wantFileSink bool
+	}{
+		{name: "stdout only", format: "yaml", wantStdout: "yaml"},
+		{name: "text tee", file: &file, format: "text", wantStdout: "text", wantFile: "json", wantFileSink: true},
+		{name: "yaml tee", file: &file, format: "yaml", wantStdout: "text", wantFile: "yaml", wantFileSink: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sinks []outputSink
+			if tt.file != nil {
+				sinks = outputSinks(&stdout, tt.file, "out.yaml", tt.format)
+			} else {
+				sinks = outputSinks(&stdout, nil, "", tt.format)
+			}
+			if sinks[0].w != &stdout || sinks[0].format != tt.wantStdout {
+				t.Errorf("stdout sink = %+v, want format %s", sinks[0], tt.wantStdout)
+			}
+			if got := len(sinks) == 2; got != tt.wantFileSink {
+				t.Fatalf("got %d sinks", len(sinks))
+			}
+			if tt.wantFileSink && (sinks[1].format != tt.wantFile || sinks[1].name != "out.yaml") {
+				t.Errorf("file sink = %+v, want format %s", sinks[1], tt.wantFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// cmd/cli/stream.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// streamJSON 按顶层字段逐个编码并写入 w,同一时刻只在内存中保留一个模块的编码结果,
+// 输出与 marshalJSON 加缩进的结果一致:字段保持原有顺序,强制包含的空字段追加在末尾
+func streamJSON(w io.Writer, v any, filter sectionFilter) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("  ", "  ")
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+
+	var written []string
+	write := func(name string, value any) error {
+		buf.Reset()
+		if err := enc.Encode(value); err != nil {
+			return fmt.Errorf("编码字段 %s 失败: %w", name, err)
+		}
+		key, _ := json.Marshal(name)
+
+		sep := "\n  "
+		if len(written) > 0 {
+			sep = ",\n  "
+		}
+		written = append(written, name)
+		_, err := fmt.Fprintf(w, "%s%s: %s", sep, key, bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		return err
+	}
+
+	for _, f := range topLevelFields(reflect.ValueOf(v)) {
+		if slices.Contains(filter.exclude, f.name) || f.omit {
+			continue
+		}
+		if err := write(f.name, f.value.Interface()); err != nil {
+			return err
+		}
+	}
+
+	types := sectionNames()
+	for _, name := range filter.include {
+		if slices.Contains(written, name) {
+			continue
+		}
+		if err := write(name, emptyJSON(types[name])); err != nil {
+			return err
+		}
+	}
+
+	end := "}\n"
+	if len(written) > 0 {
+		end = "\n}\n"
+	}
+	_, err := io.WriteString(w, end)
+	return err
+}
+
+// topField 顶层字段的名称和值,omit 表示按 omitempty/omitzero 规则该字段不输出
+type topField struct {
+	name  string
+	value reflect.Value
+	omit  bool
+}
+
+// topLevelFields 按 encoding/json 的规则列出结构体的顶层字段,嵌入结构体的字段展开到顶层,
+// 为 nil 的嵌入指针被跳过
+func topLevelFields(v reflect.Value) []topField {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []topField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-":
+		case field.Anonymous && name == "":
+			fields = append(fields, topLevelFields(v.Field(i))...)
+		case field.IsExported():
+			if name == "" {
+				name = field.Name
+			}
+			fv := v.Field(i)
+			fields = append(fields, topField{name: name, value: fv, omit: omitField(fv, opts)})
+		}
+	}
+	return fields
+}
+
+// omitField 判断字段值在给定的 json 标签选项下是否被省略
+func omitField(v reflect.Value, opts string) bool {
+	for opt := range strings.SplitSeq(opts, ",") {
+		switch opt {
+		case "omitempty":
+			if isEmptyValue(v) {
+				return true
+			}
+		case "omitzero":
+			if v.IsZero() {
+				return true
+			}
+			if z, ok := v.Interface().(interface{ IsZero() bool }); ok && z.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 判断一致
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// cmd/cli/stream_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// goldenInfo 读取 model 包中所有字段都有值的 HardwareInfo
+func goldenInfo(t *testing.T) *model.HardwareInfo {
+	t.Helper()
+	data, err := os.ReadFile("../../internal/model/testdata/hardware.golden.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info model.HardwareInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	return &info
+}
+
+func TestStreamJSON(t *testing.T) {
+	info := goldenInfo(t)
+	tests := []struct {
+		name   string
+		out    *cliOutput
+		filter sectionFilter
+	}{
+		{"full", &cliOutput{HardwareInfo: info}, sectionFilter{}},
+		{"problems", &cliOutput{
+			HardwareInfo: info,
+			Errors:       []utils.Warning{{Module: "disk", Message: "lsblk <failed> & exited"}},
+			Warnings:     []utils.Warning{{Module: "gpu", Message: "nvidia-smi not found"}},
+		}, sectionFilter{}},
+		{"exclude", &cliOutput{HardwareInfo: info}, sectionFilter{exclude: []string{"timestamp", "disk", "pci"}}},
+		{"include", &cliOutput{HardwareInfo: &model.HardwareInfo{Hostname: "node-1"}}, sectionFilter{include: []string{"gpu", "service", "errors", "hostname"}}},
+		{"empty", &cliOutput{}, sectionFilter{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := streamJSON(&buf, tt.out, tt.filter); err != nil {
+				t.Fatal(err)
+			}
+
+			// 与整体编码后缩进的结果逐字节一致
+			data, err := marshalJSON(tt.out, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want bytes.Buffer
+			if err := json.Indent(&want, data, "", "  "); err != nil {
+				t.Fatal(err)
+			}
+			want.WriteByte('\n')
+			if buf.String() != want.String() {
+				t.Errorf("streamJSON() =\n%s\nwant\n%s", buf.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestStreamJSONRoundTrip(t *testing.T) {
+	out := &cliOutput{
+		HardwareInfo: goldenInfo(t),
+		Errors:       []utils.Warning{{Module: "disk", Message: "lsblk failed"}},
+	}
+
+	var buf bytes.Buffer
+	if err := streamJSON(&buf, out, sectionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded cliOutput
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("streamed output is not JSON: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(decoded.HardwareInfo, out.HardwareInfo) || !reflect.DeepEqual(decoded.Errors, out.Errors) {
+		t.Error("streamed output does not decode back to the same structure")
+	}
+}
+
+// failingWriter 写入 n 次后返回错误
+type failingWriter struct{ n int }
+
+var errWrite = errors.New("disk full")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errWrite
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestStreamJSONWriteError(t *testing.T) {
+	out := &cliOutput{HardwareInfo: goldenInfo(t)}
+	for n := range 3 {
+		if err := streamJSON(&failingWriter{n: n}, out, sectionFilter{}); !errors.Is(err, errWrite) {
+			t.Errorf("write %d failing: err = %v, want %v", n, err, errWrite)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/publisher"
+	"github.com/zenithax-cc/diting/pkg/replay"
+)
+
+func TestCycleRunnerSkipsOverlappingCycles(t *testing.T) {
+	var (
+		buf     bytes.Buffer
+		mu      sync.Mutex
+		started []publishOptions
+	)
+	release := make(chan struct{})
+	r := &cycleRunner{
+		log: slog.New(slog.NewTextHandler(&buf, nil)),
+		cycle: func(opts publishOptions) {
+			mu.Lock()
+			started = append(started, opts)
+			mu.Unlock()
+			<-release
+		},
+	}
+
+	if !r.run() {
+		t.Fatal("first cycle was skipped")
+	}
+	// 上一周期仍在进行,之后的触发都被跳过
+	for range 3 {
+		if r.run() {
+			t.Error("overlapping cycle was started")
+		}
+	}
+	close(release)
+	r.wait()
+
+	if len(started) != 1 {
+		t.Errorf("ran %d cycles, want 1", len(started))
+	}
+	if got := strings.Count(buf.String(), "跳过本次采集"); got != 3 {
+		t.Errorf("logged %d skip warnings, want 3:\n%s", got, buf.String())
+	}
+
+	// 上一周期结束后可以再次执行
+	if !r.run() {
+		t.Error("cycle after the previous one finished was skipped")
+	}
+	r.wait()
+}
+
+func TestCycleRunnerForcesFullSnapshots(t *testing.T) {
+	var forced []bool
+	r := &cycleRunner{
+		log:         slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)),
+		onlyChanged: true,
+		fullEvery:   3,
+		cycle: func(opts publishOptions) {
+			forced = append(forced, opts.forceFull)
+		},
+	}
+	for range 7 {
+		r.run()
+		r.wait()
+	}
+
+	want := []bool{true, false, false, true, false, false, true}
+	if len(forced) != len(want) {
+		t.Fatalf("ran %d cycles, want %d", len(forced), len(want))
+	}
+	for i := range want {
+		if forced[i] != want[i] {
+			t.Errorf("cycle %d forceFull = %v, want %v", i, forced[i], want[i])
+		}
+	}
+}
+
+// snapshotRecorder 记录收到的增量信封
+type snapshotRecorder struct {
+	mu        sync.Mutex
+	snapshots []*publisher.Snapshot
+}
+
+func (p *snapshotRecorder) Publish(context.Context, *model.HardwareInfo) error { return nil }
+
+func (p *snapshotRecorder) PublishSnapshot(_ context.Context, s *publisher.Snapshot) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.snapshots = append(p.snapshots, s)
+	return nil
+}
+
+func (p *snapshotRecorder) Close() error { return nil }
+
+func TestFullSnapshotPublishedWhenUnchanged(t *testing.T) {
+	if err := replay.Enable("../../internal/collector/testdata/host"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(replay.Disable)
+	coll, err := collector.NewCollector(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	rec := &snapshotRecorder{}
+	pub := publisher.NewDeltaPublisher(rec, 0)
+	r := &cycleRunner{
+		log:         log,
+		onlyChanged: true,
+		fullEvery:   2,
+		cycle: func(opts publishOptions) {
+			collectAndPublish(context.Background(), coll, []string{"system", "memory"}, pub, log, opts)
+		},
+	}
+	for range 5 {
+		r.run()
+		r.wait()
+	}
+
+	// 采集结果从不变化:第 1、3、5 个周期强制推送全量快照,其余周期跳过
+	if len(rec.snapshots) != 3 {
+		t.Fatalf("published %d snapshots, want 3:\n%s", len(rec.snapshots), buf.String())
+	}
+	for i, s := range rec.snapshots {
+		if !s.Full || s.Sections["memory"] == nil {
+			t.Errorf("snapshot %d = %+v, want a full snapshot", i, s)
+		}
+	}
+	if got := strings.Count(buf.String(), "强制推送全量快照"); got != 3 {
+		t.Errorf("logged %d forced snapshots, want 3:\n%s", got, buf.String())
+	}
+	if got := strings.Count(buf.String(), "跳过推送"); got != 2 {
+		t.Errorf("logged %d skipped publishes, want 2:\n%s", got, buf.String())
+	}
+}
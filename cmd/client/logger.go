@@ -0,0 +1,55 @@
+// cmd/client/logger.go
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/config"
+	"github.com/zenithax-cc/diting/pkg/logger"
+)
+
+// logConfig 将 logger 配置转换为日志模块的配置。日志文件按天切分,
+// log_file 的目录和去掉 .log 后缀的文件名作为日志目录和文件名前缀,max_backups 为保留天数
+func logConfig(cfg *config.Config) (*logger.LogConfig, error) {
+	var level slog.Level
+	if cfg.Logger.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Logger.Level)); err != nil {
+			return nil, fmt.Errorf("logger.level: %w", err)
+		}
+	}
+
+	format := logger.LogFormat(cfg.Logger.Format)
+	switch format {
+	case "", logger.LogFormatText, logger.LogFormatJSON, logger.LogFormatLogfmt:
+	default:
+		return nil, fmt.Errorf("logger.format: unsupported format %q, supported: text, json, logfmt", cfg.Logger.Format)
+	}
+
+	lc := &logger.LogConfig{
+		Output:          logger.OutputFile,
+		Format:          format,
+		Level:           level,
+		NoColor:         cfg.Logger.NoColor,
+		RetainDays:      cfg.Logger.MaxBackups,
+		RateLimitWindow: cfg.Logger.RateLimitWindow,
+		SampleRate:      cfg.Logger.SampleRate,
+		SampleBurst:     cfg.Logger.SampleBurst,
+	}
+	if cfg.Logger.LogFile != "" {
+		lc.Dir = filepath.Dir(cfg.Logger.LogFile)
+		lc.FilenamePrefix = strings.TrimSuffix(filepath.Base(cfg.Logger.LogFile), ".log")
+	}
+	lc.BufferSize, lc.FlushInterval = cfg.LogBuffer()
+	return lc, nil
+}
+
+// fatal 记录错误日志,刷新日志文件后退出
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg, "error", err)
+	_ = logger.Close()
+	os.Exit(1)
+}
@@ -0,0 +1,134 @@
+// cmd/client/logger_test.go
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/config"
+	"github.com/zenithax-cc/diting/pkg/logger"
+)
+
+// newConfigFileHandler 按 logger 配置创建日志文件处理器,返回处理器和当天的日志文件路径
+func newConfigFileHandler(t *testing.T, cfg *config.Config) (*logger.DailyFileHandler, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg.Logger.LogFile = filepath.Join(dir, "client.log")
+
+	lc, err := logConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := logger.NewFileHandler(lc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	return h, filepath.Join(dir, "client-"+time.Now().Format("2006-01-02")+".log")
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestLogConfigUnbuffered(t *testing.T) {
+	h, path := newConfigFileHandler(t, &config.Config{})
+
+	// 未配置缓冲时每条日志直接写入
+	slog.New(h).Info("direct")
+	if got := readFile(t, path); !strings.Contains(got, "direct") {
+		t.Errorf("log = %q, want the record written immediately", got)
+	}
+}
+
+func TestLogConfigFlushInterval(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Logger.BufferSizeKB = 64
+	cfg.Logger.FlushInterval = 200 * time.Millisecond
+	h, path := newConfigFileHandler(t, cfg)
+
+	// 远未达到缓冲大小,由定时刷新写入文件
+	slog.New(h).Info("buffered")
+	if got := readFile(t, path); strings.Contains(got, "buffered") {
+		t.Fatalf("log = %q, want the record buffered", got)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(readFile(t, path), "buffered") {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered record not flushed by the flush interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLogConfigRateLimit(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Logger.RateLimitWindow = time.Hour
+	lc, err := logConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.RateLimitWindow != time.Hour {
+		t.Fatalf("RateLimitWindow = %v, want 1h", lc.RateLimitWindow)
+	}
+
+	// 每个周期重复出现的采集警告在窗口内只输出一次
+	h, path := newConfigFileHandler(t, cfg)
+	log := slog.New(logger.NewRateLimitHandlerContext(t.Context(), h, lc.RateLimitWindow))
+	for range 3 {
+		log.Warn("采集警告: gpu: nvidia-smi not found")
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(readFile(t, path), "nvidia-smi not found"); got != 1 {
+		t.Errorf("repeated warning logged %d times, want once", got)
+	}
+}
+
+func TestLogConfigSampling(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Logger.SampleRate = 10
+	cfg.Logger.SampleBurst = 100
+	lc, err := logConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.SampleRate != 10 || lc.SampleBurst != 100 {
+		t.Errorf("SampleRate = %d, SampleBurst = %d, want 10, 100", lc.SampleRate, lc.SampleBurst)
+	}
+}
+
+func TestLogConfigFormat(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Logger.Format = "logfmt"
+	cfg.Logger.NoColor = true
+	lc, err := logConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.Format != logger.LogFormatLogfmt || !lc.NoColor {
+		t.Errorf("Format = %q, NoColor = %v, want logfmt, true", lc.Format, lc.NoColor)
+	}
+
+	// 日志文件按 logfmt 写入
+	h, path := newConfigFileHandler(t, cfg)
+	slog.New(h).Info("collected", "module", "gpu", "error", "nvidia-smi not found")
+	if got := readFile(t, path); !strings.Contains(got, `msg=collected module=gpu error="nvidia-smi not found"`) {
+		t.Errorf("log = %q, want logfmt", got)
+	}
+
+	cfg.Logger.Format = "xml"
+	if _, err := logConfig(cfg); err == nil {
+		t.Error("logConfig() with an unknown format succeeded")
+	}
+}
@@ -2,19 +2,31 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"hardware-collector/internal/collector"
-	"hardware-collector/internal/config"
-	"hardware-collector/internal/logger"
-	"hardware-collector/internal/publisher"
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/internal/collector/network"
+	"github.com/zenithax-cc/diting/internal/config"
+	"github.com/zenithax-cc/diting/internal/publisher"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/identity"
+	"github.com/zenithax-cc/diting/pkg/logger"
+	"github.com/zenithax-cc/diting/pkg/metrics"
+	"github.com/zenithax-cc/diting/pkg/redact"
+	"github.com/zenithax-cc/diting/pkg/retry"
+	"github.com/zenithax-cc/diting/pkg/utils"
 )
 
 func main() {
@@ -28,31 +40,168 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 根 ctx,收到停止信号时取消,采集任务和日志的后台清理、限流汇总任务随之停止
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// 初始化日志
-	log, err := logger.NewLogger(cfg.Logger.LogFile, cfg.Logger.Level, cfg.Logger.MaxSize, cfg.Logger.MaxBackups)
+	logCfg, err := logConfig(cfg)
+	if err == nil {
+		_, err = logger.InitLoggerContext(ctx, logCfg)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "初始化日志失败: %v\n", err)
 		os.Exit(1)
 	}
-	defer log.Close()
+	defer logger.Close()
+	log := logger.GetLogger()
 
 	// 限制资源使用
 	runtime.GOMAXPROCS(cfg.Resource.CPUCores)
 
+	// 外部命令的默认超时,需在开始采集前设置
+	if cfg.Client.CommandTimeout > 0 {
+		executor.DefaultTimeout = cfg.Client.CommandTimeout
+	}
+
+	// 加固部署时只允许执行白名单中的外部命令
+	if len(cfg.Client.AllowedCommands) > 0 {
+		executor.SetAllowedCommands(cfg.Client.AllowedCommands)
+	}
+
+	// 审计日志,记录每次执行的外部命令及耗时和结果
+	if cfg.Client.AuditCommands {
+		executor.SetRedactedFlags(cfg.Client.AuditRedactFlags...)
+		executor.OnExec = execLogger(log)
+	}
+
+	// 跳过已知探测时会卡住的设备
+	utils.SetExcludedDevices(cfg.Client.ExcludeDevices)
+
 	// 初始化采集器
-	coll, err := collector.NewCollector(cfg.Client.CacheDir)
+	if err := collector.ValidateModules(cfg.Client.Modules); err != nil {
+		fatal(log, "采集模块配置错误", err)
+	}
+
+	coll, err := collector.NewCollector(cfg.Client.CacheDir, cfg.Client.RequireCache)
+	if err != nil {
+		fatal(log, "初始化采集器失败", err)
+	}
+
+	// 容器中 os.Hostname 返回的是 Pod 名称,可配置为稳定的主机标识
+	resolver, err := identity.New(identity.Source(cfg.Client.Identity.Source), cfg.Client.Identity.Value)
+	if err != nil {
+		fatal(log, "主机标识配置错误", err)
+	}
+	coll.SetIdentity(resolver)
+
+	// 采集和推送指标
+	var sink metrics.Metrics
+	if cfg.Metrics.ListenAddr != "" {
+		var shutdown func()
+		sink, shutdown = serveMetrics(cfg.Metrics.ListenAddr, log)
+		defer shutdown()
+		coll.SetMetrics(sink)
+	}
+
+	moduleTimeouts, err := cfg.ModuleTimeouts()
+	if err != nil {
+		fatal(log, "模块超时配置错误", err)
+	}
+	if err := coll.SetModuleTimeouts(moduleTimeouts); err != nil {
+		fatal(log, "模块超时配置错误", err)
+	}
+
+	profile, err := utils.ParseProfile(cfg.Client.Profile)
 	if err != nil {
-		log.Fatalf("初始化采集器失败: %v", err)
+		fatal(log, "采集配置错误", err)
+	}
+	coll.SetProfile(profile)
+	network.SetSysfsOnly(cfg.Collector.NetworkSysfsOnly)
+	network.SetHostNamespace(cfg.Collector.NetworkHostNamespace, cfg.Collector.NetworkHostSysfs)
+	if err := network.SetIgnoredInterfaces(cfg.Collector.NetworkIgnoreInterfaces); err != nil {
+		fatal(log, "网络接口过滤配置错误", err)
+	}
+
+	// 初始化推送器
+	serializer, err := publisher.NewSerializer(cfg.Publisher.Serializer)
+	if err != nil {
+		fatal(log, "初始化编码方式失败", err)
+	}
+
+	var pub publisher.Publisher
+	switch cfg.Publisher.Type {
+	case "", "kafka":
+		newKafka := func(topic string) *publisher.KafkaPublisher {
+			kafka := publisher.NewKafkaPublisher(cfg.Kafka.Brokers, topic)
+			kafka.Serializer = serializer
+			return kafka
+		}
+		if len(cfg.Kafka.TopicRouting) > 0 {
+			pub = publisher.NewRoutingPublisher(cfg.Kafka.Topic, cfg.Kafka.TopicRouting, func(topic string) publisher.Publisher {
+				return newKafka(topic)
+			})
+		} else {
+			pub = newKafka(cfg.Kafka.Topic)
+		}
+	case "influx":
+		influx, err := publisher.NewInfluxPublisher(cfg.Influx.Addr, cfg.Influx.Database)
+		if err != nil {
+			fatal(log, "初始化推送器失败", err)
+		}
+		pub = influx
+	case "stdout":
+		stdout := publisher.NewStdoutPublisher(os.Stdout)
+		stdout.Serializer = serializer
+		pub = stdout
+	default:
+		fatal(log, "初始化推送器失败", fmt.Errorf("不支持的推送类型: %s", cfg.Publisher.Type))
+	}
+
+	if cfg.Publisher.Delta {
+		sp, ok := pub.(publisher.SnapshotPublisher)
+		if !ok {
+			fatal(log, "初始化推送器失败", fmt.Errorf("推送类型 %s 不支持增量推送", cfg.Publisher.Type))
+		}
+		// only_changed 时按采集周期强制全量,增量推送器不再按推送次数计数
+		deltaEvery := cfg.Publisher.FullSnapshotEvery
+		if cfg.Publisher.OnlyChanged {
+			deltaEvery = 0
+		}
+		pub = publisher.NewDeltaPublisher(sp, deltaEvery)
 	}
 
-	// 初始化 Kafka 推送器
-	pub := publisher.NewKafkaPublisher(cfg.Kafka.Brokers, cfg.Kafka.Topic)
+	if cfg.Publisher.Retry.MaxAttempts > 1 {
+		pub = publisher.NewRetryingPublisher(pub, retry.Policy{
+			MaxAttempts: cfg.Publisher.Retry.MaxAttempts,
+			BaseBackoff: cfg.Publisher.Retry.BaseBackoff,
+			MaxBackoff:  cfg.Publisher.Retry.MaxBackoff,
+			Jitter:      cfg.Publisher.Retry.Jitter,
+		})
+	}
+
+	pub = withBreaker(pub, cfg)
+
+	if len(cfg.Redact.Fields) > 0 {
+		redactor, err := redact.New(cfg.Redact.Fields, redact.Mode(cfg.Redact.Mode), cfg.Redact.HashKey)
+		if err != nil {
+			fatal(log, "初始化脱敏配置失败", err)
+		}
+		pub = publisher.NewRedactingPublisher(pub, redactor)
+	}
+	if cfg.Redact.AnonymizeHostname {
+		pub = publisher.NewAnonymizingPublisher(pub, cfg.Redact.HostnameSalt)
+	}
+	if len(cfg.Publisher.Labels) > 0 {
+		pub = publisher.NewLabelingPublisher(pub, cfg.Publisher.Labels)
+	}
+	if sink != nil {
+		pubType := cmp.Or(cfg.Publisher.Type, "kafka")
+		pub = publisher.NewInstrumentedPublisher(pub, pubType, sink)
+	}
 	defer pub.Close()
 
 	// 启动采集任务
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	ticker := time.NewTicker(cfg.Client.Interval)
 	defer ticker.Stop()
 
@@ -62,31 +211,160 @@ func main() {
 
 	log.Info("硬件采集客户端已启动")
 
+	cycles := &cycleRunner{
+		log:         log,
+		onlyChanged: cfg.Publisher.OnlyChanged,
+		fullEvery:   cfg.Publisher.FullSnapshotEvery,
+		cycle: func(opts publishOptions) {
+			collectAndPublish(ctx, coll, cfg.Client.Modules, pub, log, opts)
+		},
+	}
 	// 立即执行一次采集
-	collectAndPublish(ctx, coll, pub, log)
+	cycles.run()
 
 	for {
 		select {
 		case <-ticker.C:
-			collectAndPublish(ctx, coll, pub, log)
+			cycles.run()
 		case <-sigChan:
 			log.Info("收到停止信号,正在退出...")
+			cancel()
+			cycles.wait()
 			return
 		}
 	}
 }
 
-func collectAndPublish(ctx context.Context, coll *collector.Collector, pub *publisher.KafkaPublisher, log *logger.Logger) {
-	info, err := coll.Collect(ctx, nil)
+// cycleRunner 在独立的 goroutine 中执行采集周期,上一周期未结束时跳过本次,避免慢主机上周期重叠堆积
+type cycleRunner struct {
+	log         *slog.Logger
+	onlyChanged bool                      // 采集结果没有变化时不推送
+	fullEvery   int                       // 变化推送模式下每 N 个周期(含首次)强制推送全量快照
+	cycle       func(opts publishOptions) // 执行一个采集周期
+
+	running atomic.Bool
+	wg      sync.WaitGroup
+	cycles  int // 已执行的采集周期数,只在调用 run 的 goroutine 中读写
+}
+
+// run 启动一个采集周期,上一周期仍在进行时跳过并返回 false
+func (r *cycleRunner) run() bool {
+	if !r.running.CompareAndSwap(false, true) {
+		r.log.Warn("上一次采集仍在进行,跳过本次采集")
+		return false
+	}
+
+	opts := publishOptions{
+		onlyChanged: r.onlyChanged,
+		forceFull:   r.onlyChanged && r.fullEvery > 0 && r.cycles%r.fullEvery == 0,
+	}
+	r.cycles++
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.running.Store(false)
+		r.cycle(opts)
+	}()
+	return true
+}
+
+// wait 等待进行中的采集周期结束
+func (r *cycleRunner) wait() {
+	r.wg.Wait()
+}
+
+// publishOptions 一个采集周期的推送方式
+type publishOptions struct {
+	onlyChanged bool // 采集结果没有变化时不推送
+	forceFull   bool // 无论是否变化都推送,增量推送时推送全量快照
+}
+
+// withBreaker 为网络推送器(kafka、influx)加上熔断,服务端故障期间快速失败,避免每个周期都重连和刷错误日志。
+// stdout 写本地输出,失败不是远端故障,熔断只会丢掉本可写出的数据,因此不包装
+func withBreaker(pub publisher.Publisher, cfg *config.Config) publisher.Publisher {
+	if cfg.Publisher.Breaker.Threshold <= 0 || cfg.Publisher.Type == "stdout" {
+		return pub
+	}
+	return publisher.NewCircuitBreaker(pub, cfg.Publisher.Breaker.Threshold, cfg.Publisher.Breaker.Cooldown)
+}
+
+func collectAndPublish(ctx context.Context, coll *collector.Collector, modules []string, pub publisher.Publisher, log *slog.Logger, opts publishOptions) {
+	start := time.Now()
+	info, err := coll.Collect(ctx, modules)
 	if err != nil {
-		log.Errorf("采集失败: %v", err)
+		log.Error("采集失败", "error", err)
 		return
 	}
 
-	if err := pub.Publish(ctx, info); err != nil {
-		log.Errorf("推送失败: %v", err)
+	// 相同的警告每个周期都会出现,由 logger.rate_limit_window 限流,每个窗口只输出一次
+	if warnings := coll.PermissionWarnings(); len(warnings) > 0 {
+		log.Warn("以下内容因权限不足未能采集: " + strings.Join(warnings, ", "))
+	}
+	for _, w := range coll.Warnings() {
+		log.Warn("采集警告: " + w.String())
+	}
+
+	stats := coll.LastStats()
+	switch {
+	case opts.forceFull:
+		log.Info("强制推送全量快照")
+		ctx = publisher.WithFullSnapshot(ctx)
+	case opts.onlyChanged && !stats.Changed:
+		log.Info("采集结果没有变化,跳过推送")
+		logCycleSummary(log, stats, nil, time.Since(start))
 		return
 	}
 
-	log.Infof("成功采集并推送硬件信息")
+	publishErr := pub.Publish(ctx, info)
+	if publishErr != nil {
+		log.Error("推送失败", "error", publishErr)
+	}
+
+	logCycleSummary(log, stats, publishErr, time.Since(start))
+}
+
+// execLogger 返回以结构化日志记录每次外部命令执行的审计钩子
+func execLogger(log *slog.Logger) func(name string, args []string, dur time.Duration, err error) {
+	return func(name string, args []string, dur time.Duration, err error) {
+		attrs := []any{
+			slog.String("command", name),
+			slog.Any("args", args),
+			slog.Duration("duration", dur),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		log.Info("执行外部命令", attrs...)
+	}
+}
+
+// logCycleSummary 每个采集周期输出一条结构化的汇总日志,JSON 格式下可按字段检索
+func logCycleSummary(log *slog.Logger, stats collector.CollectStats, publishErr error, elapsed time.Duration) {
+	durations := make([]any, 0, len(stats.Durations))
+	for _, m := range stats.Modules {
+		if d, ok := stats.Durations[m]; ok {
+			durations = append(durations, slog.Duration(m, d))
+		}
+	}
+
+	publishResult := "success"
+	if publishErr != nil {
+		publishResult = "failure"
+	}
+
+	attrs := []any{
+		slog.String("modules", strings.Join(stats.Modules, ",")),
+		slog.Group("module_durations", durations...),
+		slog.Bool("changed", stats.Changed),
+		slog.String("publish", publishResult),
+		slog.Duration("collect_duration", stats.Duration),
+		slog.Duration("cycle_duration", elapsed),
+	}
+	if publishErr != nil {
+		attrs = append(attrs, slog.String("publish_error", publishErr.Error()))
+	}
+
+	log.Info("采集周期完成", attrs...)
 }
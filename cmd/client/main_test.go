@@ -0,0 +1,111 @@
+// cmd/client/main_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/collector"
+	"github.com/zenithax-cc/diting/internal/config"
+	"github.com/zenithax-cc/diting/internal/publisher"
+)
+
+func TestLogCycleSummary(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	stats := collector.CollectStats{
+		Modules:   []string{"system", "disk"},
+		Durations: map[string]time.Duration{"system": 2 * time.Millisecond, "disk": 3 * time.Second},
+		Changed:   true,
+		Duration:  3 * time.Second,
+	}
+	logCycleSummary(log, stats, errors.New("broker down"), 4*time.Second)
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("summary is not a single JSON line: %v\n%s", err, buf.String())
+	}
+
+	want := map[string]any{
+		"modules":          "system,disk",
+		"changed":          true,
+		"publish":          "failure",
+		"publish_error":    "broker down",
+		"collect_duration": float64(3 * time.Second),
+		"cycle_duration":   float64(4 * time.Second),
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("%s = %v, want %v", key, line[key], value)
+		}
+	}
+	durations, _ := line["module_durations"].(map[string]any)
+	if durations["system"] != float64(2*time.Millisecond) || durations["disk"] != float64(3*time.Second) {
+		t.Errorf("module_durations = %v", line["module_durations"])
+	}
+}
+
+func TestLogCycleSummarySuccess(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	logCycleSummary(log, collector.CollectStats{Modules: []string{"system"}}, nil, time.Second)
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["publish"] != "success" || line["changed"] != false {
+		t.Errorf("summary = %v", line)
+	}
+	if _, ok := line["publish_error"]; ok {
+		t.Errorf("publish_error present on success: %v", line)
+	}
+}
+
+func TestExecLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	execLogger(log)("ipmitool", []string{"-P", "***"}, 2*time.Second, errors.New("exit status 1"))
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := line["args"].([]any)
+	if line["command"] != "ipmitool" || len(args) != 2 || args[1] != "***" ||
+		line["duration"] != float64(2*time.Second) || line["error"] != "exit status 1" {
+		t.Errorf("audit log = %v", line)
+	}
+}
+
+func TestWithBreaker(t *testing.T) {
+	tests := []struct {
+		pubType   string
+		threshold int
+		want      bool
+	}{
+		{"", 3, true},
+		{"kafka", 3, true},
+		{"influx", 3, true},
+		{"stdout", 3, false}, // 本地输出不熔断
+		{"kafka", 0, false},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{}
+		cfg.Publisher.Type = tt.pubType
+		cfg.Publisher.Breaker.Threshold = tt.threshold
+
+		pub := publisher.NewStdoutPublisher(&bytes.Buffer{})
+		_, got := withBreaker(pub, cfg).(*publisher.CircuitBreaker)
+		if got != tt.want {
+			t.Errorf("withBreaker(type=%q, threshold=%d) wrapped = %v, want %v", tt.pubType, tt.threshold, got, tt.want)
+		}
+	}
+}
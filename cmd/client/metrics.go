@@ -0,0 +1,38 @@
+// cmd/client/metrics.go
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/zenithax-cc/diting/pkg/metrics"
+)
+
+// metricsNamespace 客户端上报的 Prometheus 指标的命名空间
+const metricsNamespace = "hardware_collector"
+
+// serveMetrics 在 addr 上暴露 /metrics,返回注册到默认 Registerer 的指标和关闭服务的函数
+func serveMetrics(addr string, log *slog.Logger) (metrics.Metrics, func()) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("指标服务异常退出", "addr", addr, "error", err)
+		}
+	}()
+	log.Info("指标服务已启动", "addr", addr)
+
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}
+	return metrics.NewPrometheus(metricsNamespace, nil), shutdown
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// SchemaVersion 缓存数据的结构版本,HardwareInfo 的 JSON 结构发生不兼容变化时需递增,
+// 旧版本写入的缓存在加载时会被丢弃,避免反序列化失败或误判数据发生变化
+const SchemaVersion = 1
+
+const cacheFileName = "hardware-info.json"
+
+// cacheEnvelope 缓存文件格式
+type cacheEnvelope struct {
+	SchemaVersion int                 `json:"schema_version"`
+	SavedAt       time.Time           `json:"saved_at"`
+	Data          *model.HardwareInfo `json:"data"`
+}
+
+// Cache 将最近一次采集结果持久化到本地文件,用于进程重启后的变化检测。
+// nil *Cache 表示不持久化,Load 总是返回 nil,Save 不做任何操作
+type Cache struct {
+	path  string
+	clock utils.Clock // 写入时间 SavedAt 的来源
+}
+
+// NewCache 创建缓存,目录不存在时创建,目录不可写时返回错误
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create cache directory failed: %w", err)
+	}
+
+	// 目录已存在时 MkdirAll 不检查权限,写一个探测文件确认可写
+	probe, err := os.CreateTemp(dir, ".probe-*")
+	if err != nil {
+		return nil, fmt.Errorf("cache directory not writable: %w", err)
+	}
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+
+	return &Cache{path: filepath.Join(dir, cacheFileName), clock: utils.RealClock}, nil
+}
+
+// Load 读取缓存,缓存不存在或结构版本不一致时返回 nil
+func (c *Cache) Load() (*model.HardwareInfo, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read cache file failed: %w", err)
+	}
+
+	// 先只解析版本号,旧版本的数据结构可能无法按当前结构反序列化
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil || header.SchemaVersion != SchemaVersion {
+		_ = os.Remove(c.path)
+		return nil, nil
+	}
+
+	var envelope cacheEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		_ = os.Remove(c.path)
+		return nil, nil
+	}
+
+	return envelope.Data, nil
+}
+
+// Save 写入缓存,先写临时文件再重命名,避免进程中断留下不完整的缓存文件
+func (c *Cache) Save(info *model.HardwareInfo) error {
+	if c == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheEnvelope{
+		SchemaVersion: SchemaVersion,
+		SavedAt:       c.clock.Now(),
+		Data:          info,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal cache failed: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write cache file failed: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename cache file failed: %w", err)
+	}
+
+	return nil
+}
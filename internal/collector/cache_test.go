@@ -0,0 +1,185 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	c, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &model.HardwareInfo{Hostname: "node-1", System: &model.System{BootID: "boot-1"}}
+	if err := c.Save(info); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Hostname != "node-1" || got.System.BootID != "boot-1" {
+		t.Errorf("Load() = %+v, want the saved data", got)
+	}
+}
+
+func TestCacheDiscardsIncompatibleData(t *testing.T) {
+	tests := map[string]string{
+		// 旧版本写入的缓存，字段类型已变化
+		"old schema": `{"schema_version":0,"saved_at":"2024-01-01T00:00:00Z","data":{"hostname":"node-1","memory":{"total":"64 GiB"}}}`,
+		// 引入版本号之前的缓存
+		"unversioned": `{"hostname":"node-1"}`,
+		"corrupt":     `{"schema_version":`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			c, err := NewCache(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, cacheFileName)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.Load()
+			if err != nil || got != nil {
+				t.Fatalf("Load() = %+v, %v, want an empty cache", got, err)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("incompatible cache file kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	if err := c.Save(&model.HardwareInfo{}); err != nil {
+		t.Errorf("Save() = %v", err)
+	}
+	if got, err := c.Load(); got != nil || err != nil {
+		t.Errorf("Load() = %v, %v, want nil", got, err)
+	}
+}
+
+// unwritableDir 返回无法作为缓存目录的路径:其上级是普通文件,以 root 运行时也无法创建
+func unwritableDir(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(file, "cache")
+}
+
+func TestNewCollectorUnwritableCache(t *testing.T) {
+	dir := unwritableDir(t)
+
+	if _, err := NewCollector(dir, true); err == nil {
+		t.Fatal("NewCollector() with a required unwritable cache succeeded")
+	}
+
+	setModules(t, systemModule("system", "boot-1"))
+	c, err := NewCollector(dir, false)
+	if err != nil {
+		t.Fatalf("NewCollector() with a best-effort cache = %v", err)
+	}
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !c.LastStats().Changed {
+		t.Error("first collection not reported as changed")
+	}
+
+	// 变化检测在进程内仍然有效
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.LastStats().Changed {
+		t.Error("unchanged collection reported as changed without a cache")
+	}
+}
+
+// stepClock 每次读取时间后前进 step 的时钟
+type stepClock struct {
+	mu   sync.Mutex
+	t    time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.t
+	c.t = c.t.Add(c.step)
+	return now
+}
+
+func TestCollectUsesClock(t *testing.T) {
+	setModules(t, systemModule("system", "boot-1"))
+	dir := t.TempDir()
+	c, err := NewCollector(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	c.SetClock(&stepClock{t: start, step: time.Second})
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Timestamp.Equal(start) {
+		t.Errorf("Timestamp = %v, want %v", info.Timestamp, start)
+	}
+	stats := c.LastStats()
+	if stats.Duration <= 0 || stats.Duration%time.Second != 0 || stats.Durations["system"] != time.Second {
+		t.Errorf("Duration = %v, module durations = %v, want whole clock steps", stats.Duration, stats.Durations)
+	}
+
+	// 缓存的写入时间同样来自该时钟
+	data, err := os.ReadFile(filepath.Join(dir, cacheFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope cacheEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.SavedAt.Before(start) || envelope.SavedAt.After(start.Add(time.Minute)) {
+		t.Errorf("SavedAt = %v, want a time from the fake clock", envelope.SavedAt)
+	}
+}
+
+func TestCollectPassesClockToModules(t *testing.T) {
+	var got time.Time
+	setModules(t, moduleCollector{
+		name: "system",
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			got = utils.ClockFrom(ctx).Now()
+			return func(*model.HardwareInfo) {}, nil
+		},
+	})
+	c := newTestCollector(t)
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	c.SetClock(&stepClock{t: start, step: time.Second})
+
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	// 模块读到的是采集器的时钟，而非 time.Now
+	if got.Before(start) || got.After(start.Add(time.Minute)) {
+		t.Errorf("module clock = %v, want a time from the collector clock", got)
+	}
+}
@@ -2,153 +2,519 @@ package collector
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
-	"hardware-collector/pkg/models"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/zenithax-cc/diting/internal/collector/disk"
+	"github.com/zenithax-cc/diting/internal/collector/gpu"
+	"github.com/zenithax-cc/diting/internal/collector/infiniband"
+	"github.com/zenithax-cc/diting/internal/collector/memory"
+	"github.com/zenithax-cc/diting/internal/collector/network"
+	"github.com/zenithax-cc/diting/internal/collector/pci"
+	"github.com/zenithax-cc/diting/internal/collector/power"
+	"github.com/zenithax-cc/diting/internal/collector/product"
+	"github.com/zenithax-cc/diting/internal/collector/sensor"
+	"github.com/zenithax-cc/diting/internal/collector/service"
+	"github.com/zenithax-cc/diting/internal/collector/system"
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/identity"
+	"github.com/zenithax-cc/diting/pkg/metrics"
+	"github.com/zenithax-cc/diting/pkg/utils"
 )
 
+// Collector 采集硬件信息,可被多个 goroutine 共享使用。
+// 并发的相同模块集合的 Collect 调用会合并为一次采集,不同模块集合的采集排队依次执行
 type Collector struct {
 	cache    *Cache
 	mu       sync.RWMutex
-	lastData *models.HardwareInfo
+	lastData *model.HardwareInfo // 各模块最近一次成功采集的结果,用于变化检测
+	metrics  metrics.Metrics
+	inflight singleflight.Group // 按模块集合合并进行中的采集
+	running  chan struct{}      // 容量为1的信号量,权限记录、回放/记录状态等是进程级共享的,同一时刻只允许一次采集
+	identity identity.Resolver  // 主机标识,为空时使用 os.Hostname
+	clock    utils.Clock        // 采集时间戳和耗时的时间来源
+
+	moduleTimeouts map[string]time.Duration // 各模块的采集超时,未配置的模块只受调用方 ctx 限制
+	profile        utils.Profile            // 采集配置,决定各模块探测的深度
+
+	permissionWarnings []string        // 最近一次采集因权限不足未能读取的文件或命令
+	warnings           []utils.Warning // 最近一次采集中不影响结果的问题
+	lastStats          CollectStats    // 最近一次采集的统计信息
+}
+
+// CollectStats 一次采集的统计信息
+type CollectStats struct {
+	Modules   []string                 // 采集的模块
+	Durations map[string]time.Duration // 各模块的采集耗时
+	Errors    []utils.Warning          // 采集失败的模块及原因
+	Changed   bool                     // 采集结果相对上次缓存是否有变化
+	Duration  time.Duration            // 采集总耗时
 }
 
-func NewCollector(cacheDir string) (*Collector, error) {
+// NewCollector 创建采集器。缓存只用于变化检测,requireCache 为 false 时缓存目录不可用
+// 不视为错误,只记录警告并仅在内存中保留上次结果(变化检测在进程内仍然有效)
+func NewCollector(cacheDir string, requireCache bool) (*Collector, error) {
 	cache, err := NewCache(cacheDir)
 	if err != nil {
-		return nil, err
+		if requireCache {
+			return nil, err
+		}
+		slog.Warn("cache unavailable, change detection is kept in memory only", "dir", cacheDir, "error", err)
+		cache = nil
+	}
+
+	c := &Collector{
+		cache:   cache,
+		metrics: metrics.Nop,
+		clock:   utils.RealClock,
+		running: make(chan struct{}, 1),
 	}
 
-	return &Collector{
-		cache: cache,
-	}, nil
+	// 加载上次的采集结果用于变化检测,缓存不可用时视为首次采集
+	if lastData, err := cache.Load(); err == nil {
+		c.lastData = lastData
+	}
+
+	return c, nil
+}
+
+// SetMetrics 设置采集指标上报,传入 nil 时关闭上报
+func (c *Collector) SetMetrics(m metrics.Metrics) {
+	c.metrics = metrics.OrNop(m)
 }
 
-func (c *Collector) Collect(ctx context.Context, modules []string) (*models.HardwareInfo, error) {
-	info := &models.HardwareInfo{
-		Timestamp: time.Now(),
+// SetModuleTimeouts 设置各模块的采集超时,需在开始采集前调用
+func (c *Collector) SetModuleTimeouts(timeouts map[string]time.Duration) error {
+	modules := make([]string, 0, len(timeouts))
+	for m := range timeouts {
+		modules = append(modules, m)
+	}
+	if err := ValidateModules(modules); err != nil {
+		return err
+	}
+
+	c.moduleTimeouts = timeouts
+	return nil
+}
+
+// SetProfile 设置采集配置,需在开始采集前调用。minimal 配置下未指定模块时只采集
+// system、memory 和 network,并由各模块跳过 ethtool、SMART 等开销大的探测
+func (c *Collector) SetProfile(p utils.Profile) {
+	c.profile = p
+}
+
+// SetIdentity 设置采集结果中主机标识的获取方式,传入 nil 时使用 os.Hostname
+func (c *Collector) SetIdentity(r identity.Resolver) {
+	c.identity = r
+}
+
+// SetClock 设置采集时间戳、耗时和缓存写入时间的时间来源,传入 nil 时使用系统时间。
+// 需在开始采集前调用
+func (c *Collector) SetClock(clock utils.Clock) {
+	c.clock = utils.ClockOrReal(clock)
+	if c.cache != nil {
+		c.cache.clock = c.clock
+	}
+}
+
+// hostname 获取主机标识,配置的方式失败时回退到 os.Hostname 并记录警告
+func (c *Collector) hostname(warnings *utils.Warnings) string {
+	if c.identity != nil {
+		id, err := c.identity()
+		if err == nil {
+			return id
+		}
+		warnings.Addf("identity", "resolve host identity failed, falling back to hostname: %v", err)
 	}
 
 	hostname, _ := os.Hostname()
-	info.Hostname = hostname
+	return hostname
+}
 
-	// 根据指定模块采集信息
-	moduleSet := make(map[string]bool)
+// observe 记录模块的采集耗时和成功/失败次数,返回采集耗时
+func (c *Collector) observe(module string, start time.Time, err error) time.Duration {
+	elapsed := c.clock.Now().Sub(start)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	c.metrics.ObserveDuration("collector_module_duration_seconds", elapsed, map[string]string{"module": module})
+	c.metrics.IncCounter("collector_module_total", map[string]string{"module": module, "result": result})
+	return elapsed
+}
+
+// Collect 采集指定模块的硬件信息,modules 为空时采集全部模块。
+// 相同模块集合已有采集在进行时直接等待并共享其结果(使用发起者的 ctx),
+// 因此返回的 HardwareInfo 可能被多个调用方共享,调用方不应修改。
+// 采集本身遵守发起者的 ctx,超时时返回部分结果和错误,因此等待方在自己的 ctx 到期后
+// 仍等待共享的结果,而不是丢弃它
+func (c *Collector) Collect(ctx context.Context, modules []string) (*model.HardwareInfo, error) {
+	v, err, _ := c.inflight.Do(moduleKey(modules), func() (any, error) {
+		return c.collect(ctx, modules, nil)
+	})
+	info, _ := v.(*model.HardwareInfo)
+	return info, err
+}
+
+// CollectInto 只重新采集 modules 指定的模块并写入 dst,其他模块保持不变,modules 为空时刷新全部模块。
+// 用于不同频率轮询不同模块(如内存频繁刷新、磁盘偶尔刷新)。采集失败的模块保留 dst 中的原值;
+// dst 归调用方所有,不与其他调用合并,调用方需保证同一个 dst 不被并发刷新
+func (c *Collector) CollectInto(ctx context.Context, dst *model.HardwareInfo, modules []string) error {
+	if dst == nil {
+		return errors.New("nil destination")
+	}
+	_, err := c.collect(ctx, modules, dst)
+	return err
+}
+
+// moduleKey 将模块列表规范化为去重、排序后的字符串,作为合并采集的键
+func moduleKey(modules []string) string {
 	if len(modules) == 0 {
-		moduleSet = map[string]bool{
-			"system": true, "memory": true, "disk": true,
-			"network": true, "gpu": true,
-		}
-	} else {
-		for _, m := range modules {
-			moduleSet[m] = true
-		}
+		return "*"
 	}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, 5)
+	keys := slices.Clone(modules)
+	slices.Sort(keys)
+	return strings.Join(slices.Compact(keys), ",")
+}
 
-	if moduleSet["system"] {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if sysInfo, err := c.collectSystemInfo(ctx); err != nil {
-				errChan <- err
-			} else {
-				info.System = sysInfo
+// PermissionWarnings 返回最近一次采集中因权限不足(非root运行)而未能读取的文件或命令
+func (c *Collector) PermissionWarnings() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.permissionWarnings
+}
+
+// Warnings 返回最近一次采集中不影响整体结果的问题,如工具未安装、单个设备读取失败
+func (c *Collector) Warnings() []utils.Warning {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.warnings
+}
+
+// LastStats 返回最近一次采集的统计信息
+func (c *Collector) LastStats() CollectStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastStats
+}
+
+// moduleResult 一个模块的采集结果,由采集 goroutine 通过 channel 交给 collect 统一写入 HardwareInfo,
+// 避免多个 goroutine 同时写同一个结构体
+type moduleResult struct {
+	module   string
+	optional bool // 失败时只记录警告,不影响整体结果
+	panicked bool // 采集时发生 panic
+	timedOut bool // 超过该模块单独配置的超时,整体采集的 ctx 仍有效
+	elapsed  time.Duration
+	err      error
+	apply    func(info *model.HardwareInfo)
+}
+
+// moduleCollector 描述一个采集模块,collect 返回将结果写入 HardwareInfo 的函数
+type moduleCollector struct {
+	name     string
+	optional bool
+	minimal  bool // minimal 采集配置下未指定模块时是否采集
+	explicit bool // 只在显式指定时采集,未指定模块时不采集
+
+	description string   // 采集内容说明
+	root        bool     // 是否需要root权限才能采集完整
+	tools       []string // 使用的外部命令,缺失时相应字段为空
+	collect     func(c *Collector, ctx context.Context) (func(info *model.HardwareInfo), error)
+}
+
+// moduleCollectors 全部采集模块,顺序即统计信息中模块的顺序
+var moduleCollectors = []moduleCollector{
+	// 整机型号、序列号和BIOS,序列号等字段需要root权限
+	{name: "product", optional: true, description: "产品、主板、机箱和BIOS信息", root: true, collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := product.Collect()
+		return func(info *model.HardwareInfo) { info.Product = &v }, err
+	}},
+	{name: "system", minimal: true, description: "内核、负载、运行时间、资源上限和CPU微码", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := system.Collect(ctx)
+		return func(info *model.HardwareInfo) { info.System = &v }, err
+	}},
+	{name: "memory", minimal: true, description: "内存使用情况、内存条和EDAC错误计数", root: true, tools: []string{"dmidecode"}, collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := memory.Collect(ctx)
+		return func(info *model.HardwareInfo) { info.Memory = &v }, err
+	}},
+	{name: "disk", description: "块设备、分区和磁盘健康信息", root: true, tools: []string{"smartctl", "nvme"}, collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := disk.Collect(ctx)
+		return func(info *model.HardwareInfo) { info.Disk = &v }, err
+	}},
+	{name: "network", minimal: true, description: "网络接口、链路状态和上联交换机", tools: []string{"ethtool", "lldpctl", "ip"}, collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := network.Collect(ctx)
+		return func(info *model.HardwareInfo) { info.Network = &v }, err
+	}},
+	// GPU 采集失败(如无GPU或未安装驱动)不影响其他模块
+	{name: "gpu", optional: true, description: "NVIDIA GPU和NVLink", tools: []string{"nvidia-smi"}, collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := gpu.Collect(ctx)
+		return func(info *model.HardwareInfo) { info.GPU = v }, err
+	}},
+	// 传感器读数,没有 hwmon 设备(如虚拟机)时为空,采集失败不影响其他模块
+	{name: "sensor", optional: true, description: "温度和风扇传感器", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := sensor.Collect()
+		return func(info *model.HardwareInfo) { info.Sensors = v }, err
+	}},
+	// 电源和电池状态,台式机和虚拟机上可能为空,采集失败不影响其他模块
+	{name: "power", optional: true, description: "电源和电池状态", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := power.Collect()
+		return func(info *model.HardwareInfo) { info.Power = v }, err
+	}},
+	// InfiniBand/RDMA(含RoCE)设备,没有RDMA设备时为空,采集失败不影响其他模块
+	{name: "infiniband", optional: true, description: "InfiniBand/RDMA设备和端口状态", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := infiniband.Collect()
+		return func(info *model.HardwareInfo) { info.InfiniBand = v }, err
+	}},
+	// PCIe AER错误计数,出现过不可纠正错误的设备标记为 Degraded 并记录警告,采集失败不影响其他模块
+	{name: "pci", optional: true, description: "PCIe设备的AER错误计数", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := pci.ScanAER()
+		for _, d := range v {
+			if d.Degraded {
+				utils.WarningsFrom(ctx).Addf("pci", "device %s reported uncorrectable AER errors (fatal %s, non-fatal %s)", d.PCIAddr, d.AER.Fatal, d.AER.NonFatal)
 			}
-		}()
+		}
+		return func(info *model.HardwareInfo) { info.PCI = v }, err
+	}},
+	// 失败的 systemd unit,需通过 -m service 显式开启
+	{name: "service", optional: true, explicit: true, description: "失败的 systemd unit", tools: []string{"systemctl"}, collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v, err := service.Collect(ctx)
+		return func(info *model.HardwareInfo) { info.Service = &v }, err
+	}},
+}
+
+// ModuleInfo 采集模块的描述信息,供 -doctor 等展示
+type ModuleInfo struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	RequiresRoot bool          `json:"requires_root"`    // 需要root权限才能采集完整
+	Tools        []string      `json:"tools,omitzero"`   // 使用的外部命令
+	Optional     bool          `json:"optional"`         // 采集失败不影响其他模块
+	Minimal      bool          `json:"minimal"`          // minimal 采集配置下默认采集
+	Explicit     bool          `json:"explicit"`         // 只在显式指定时采集
+	Timeout      time.Duration `json:"timeout,omitzero"` // 配置的采集超时,0 表示不单独限制
+}
+
+// BuiltinModules 返回全部采集模块的描述信息,顺序与采集顺序一致
+func BuiltinModules() []ModuleInfo {
+	infos := make([]ModuleInfo, 0, len(moduleCollectors))
+	for _, m := range moduleCollectors {
+		infos = append(infos, ModuleInfo{
+			Name:         m.name,
+			Description:  m.description,
+			RequiresRoot: m.root,
+			Tools:        slices.Clone(m.tools),
+			Optional:     m.optional,
+			Minimal:      m.minimal,
+			Explicit:     m.explicit,
+		})
 	}
+	return infos
+}
 
-	if moduleSet["memory"] {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if memInfo, err := c.collectMemoryInfo(ctx); err != nil {
-				errChan <- err
-			} else {
-				info.Memory = memInfo
-			}
-		}()
+// Modules 返回全部采集模块的描述信息,包含该采集器配置的模块超时
+func (c *Collector) Modules() []ModuleInfo {
+	infos := BuiltinModules()
+	for i := range infos {
+		infos[i].Timeout = c.moduleTimeouts[infos[i].Name]
 	}
+	return infos
+}
 
-	if moduleSet["disk"] {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if diskInfo, err := c.collectDiskInfo(ctx); err != nil {
-				errChan <- err
-			} else {
-				info.Disk = diskInfo
-			}
-		}()
+// runModule 执行一个模块的采集。模块内的 panic(如解析格式异常的工具输出)转换为该模块的采集错误,
+// 并记录堆栈,避免一个模块导致整个进程退出
+func (c *Collector) runModule(ctx context.Context, m moduleCollector) (apply func(info *model.HardwareInfo), panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("collector module panicked", "module", m.name, "panic", r, "stack", string(debug.Stack()))
+			apply, panicked, err = nil, true, fmt.Errorf("module %s panicked: %v", m.name, r)
+		}
+	}()
+
+	apply, err = m.collect(c, ctx)
+	return apply, false, err
+}
+
+// ValidateModules 检查模块名是否都受支持
+func ValidateModules(modules []string) error {
+	for _, m := range modules {
+		if !slices.ContainsFunc(moduleCollectors, func(mc moduleCollector) bool { return mc.name == m }) {
+			return fmt.Errorf("unknown module: %s", m)
+		}
 	}
+	return nil
+}
 
-	if moduleSet["network"] {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if netInfo, err := c.collectNetworkInfo(ctx); err != nil {
-				errChan <- err
-			} else {
-				info.Network = netInfo
-			}
-		}()
+// collect 采集 modules 并将结果写入 dst,dst 为 nil 时写入新建的 HardwareInfo。
+// 采集依次执行,等待期间 ctx 取消则直接返回 ctx 的错误
+func (c *Collector) collect(ctx context.Context, modules []string, dst *model.HardwareInfo) (*model.HardwareInfo, error) {
+	select {
+	case c.running <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-c.running }()
+
+	// 丢弃上一次采集之后残留的记录
+	_ = utils.TakePermissionWarnings()
+
+	stats := CollectStats{Durations: make(map[string]time.Duration)}
+
+	// 各模块将不影响整体结果的问题记录到 warnings
+	warnings := &utils.Warnings{}
+	ctx = utils.WithWarnings(ctx, warnings)
+	ctx = utils.WithProfile(ctx, c.profile)
+	ctx = utils.WithClock(ctx, c.clock)
+
+	info := dst
+	if info == nil {
+		info = &model.HardwareInfo{}
+	}
+	info.Timestamp = c.clock.Now()
+	defer func() {
+		permissionWarnings := utils.TakePermissionWarnings()
+		stats.Duration = c.clock.Now().Sub(info.Timestamp)
+		c.mu.Lock()
+		c.permissionWarnings = permissionWarnings
+		c.warnings = warnings.List()
+		c.lastStats = stats
+		c.mu.Unlock()
+	}()
+
+	info.Hostname = c.hostname(warnings)
+
+	// 根据指定模块采集信息
+	moduleSet := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		moduleSet[m] = true
 	}
 
-	if moduleSet["gpu"] {
+	results := make(chan moduleResult, len(moduleCollectors))
+	var wg sync.WaitGroup
+
+	minimal := c.profile == utils.ProfileMinimal
+	for _, m := range moduleCollectors {
+		if len(modules) > 0 && !moduleSet[m.name] {
+			continue
+		}
+		if len(modules) == 0 && (m.explicit || minimal && !m.minimal) {
+			continue
+		}
+		stats.Modules = append(stats.Modules, m.name)
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if gpuInfo, err := c.collectGPUInfo(ctx); err != nil {
-				// GPU 采集失败不影响其他模块
-				errChan <- nil
-			} else {
-				info.GPU = gpuInfo
+
+			mctx := ctx
+			if timeout, ok := c.moduleTimeouts[m.name]; ok {
+				var cancel context.CancelFunc
+				mctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			start := c.clock.Now()
+			apply, panicked, err := c.runModule(mctx, m)
+			results <- moduleResult{
+				module:   m.name,
+				optional: m.optional,
+				panicked: panicked,
+				timedOut: err != nil && mctx.Err() != nil && ctx.Err() == nil,
+				elapsed:  c.observe(m.name, start, err),
+				err:      err,
+				apply:    apply,
 			}
 		}()
 	}
 
 	wg.Wait()
-	close(errChan)
+	close(results)
 
-	// 检查是否有错误
-	for err := range errChan {
-		if err != nil {
-			return nil, err
+	// 只在当前 goroutine 中写入结果
+	var (
+		firstErr error
+		applied  []func(info *model.HardwareInfo) // 采集成功的模块,用于更新变化检测的基准
+	)
+	for r := range results {
+		stats.Durations[r.module] = r.elapsed
+		switch {
+		case r.err == nil:
+			r.apply(info)
+			applied = append(applied, r.apply)
+		case r.optional:
+			warnings.Add(r.module, r.err)
+		case r.panicked, r.timedOut:
+			// panic 通常由该主机上特定的异常输出触发,每个周期都会重现;
+			// 模块超时只说明该模块慢,两种情况都只标记该模块失败,其他模块的结果照常返回
+			stats.Errors = append(stats.Errors, utils.Warning{Module: r.module, Message: r.err.Error()})
+			warnings.Add(r.module, r.err)
+		default:
+			stats.Errors = append(stats.Errors, utils.Warning{Module: r.module, Message: r.err.Error()})
+			if firstErr == nil {
+				firstErr = r.err
+			}
 		}
 	}
 
-	// 检查缓存,判断是否需要更新
-	if c.shouldUpdate(info) {
-		c.updateCache(info)
+	if firstErr != nil {
+		// 超时或取消时返回已采集到的部分结果,由调用方决定是否使用
+		if ctx.Err() != nil {
+			return info, firstErr
+		}
+		return nil, firstErr
 	}
 
+	// 检查缓存,判断是否需要更新
+	stats.Changed = c.updateIfChanged(info, applied)
+
 	return info, nil
 }
 
-func (c *Collector) shouldUpdate(newInfo *models.HardwareInfo) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// updateIfChanged 用本次采集成功的模块更新上次的结果并判断是否有变化,在同一把锁内完成比较和更新。
+// 未采集的模块保留原值,采集不同模块集合时不会把未采集的模块误判为移除;
+// 基准保存为深拷贝,返回给调用方的结果与它不共享任何数据,调用方修改结果不影响变化检测
+func (c *Collector) updateIfChanged(info *model.HardwareInfo, applied []func(info *model.HardwareInfo)) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if c.lastData == nil {
-		return true
+	next := &model.HardwareInfo{}
+	if c.lastData != nil {
+		copied := *c.lastData
+		next = &copied
+	}
+	next.Timestamp = info.Timestamp
+	next.Hostname = info.Hostname
+	for _, apply := range applied {
+		apply(next)
 	}
 
-	// 比较数据是否有变化
-	oldJSON, _ := json.Marshal(c.lastData)
-	newJSON, _ := json.Marshal(newInfo)
+	if !c.shouldUpdateLocked(next) {
+		return false
+	}
 
-	return string(oldJSON) != string(newJSON)
+	c.lastData = next.Clone()
+	_ = c.cache.Save(next)
+	return true
 }
 
-func (c *Collector) updateCache(info *models.HardwareInfo) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *Collector) shouldUpdateLocked(newInfo *model.HardwareInfo) bool {
+	if c.lastData == nil {
+		return true
+	}
 
-	c.lastData = info
-	_ = c.cache.Save(info)
+	// 比较数据是否有变化,采集时间和运行时间、负载等每次都会变化的字段不算作变化
+	return !c.lastData.Diff(newInfo, model.VolatileFields...).Empty()
 }
@@ -0,0 +1,727 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/executor"
+)
+
+// setModules 在测试期间用 mods 替换全部采集模块
+func setModules(t *testing.T, mods ...moduleCollector) {
+	t.Helper()
+	saved := moduleCollectors
+	moduleCollectors = mods
+	t.Cleanup(func() { moduleCollectors = saved })
+}
+
+// systemModule 返回将 bootID 写入 system 模块的测试模块
+func systemModule(name, bootID string) moduleCollector {
+	return moduleCollector{
+		name: name,
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			return func(info *model.HardwareInfo) {
+				info.System = &model.System{BootID: bootID}
+			}, nil
+		},
+	}
+}
+
+// blockingModule 返回一直阻塞到 ctx 结束的测试模块
+func blockingModule(name string) moduleCollector {
+	return moduleCollector{
+		name: name,
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+}
+
+func newTestCollector(t *testing.T) *Collector {
+	t.Helper()
+	c, err := NewCollector(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCollectReturnsPartialResultsOnDeadline(t *testing.T) {
+	setModules(t, systemModule("system", "boot-1"), blockingModule("disk"))
+	c := newTestCollector(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	info, err := c.Collect(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if info == nil || info.System == nil || info.System.BootID != "boot-1" {
+		t.Fatalf("partial result = %+v, want the system module", info)
+	}
+}
+
+func TestCollectFailsOnRequiredModuleError(t *testing.T) {
+	failing := moduleCollector{
+		name: "disk",
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			return nil, errors.New("lsblk failed")
+		},
+	}
+	setModules(t, systemModule("system", "boot-1"), failing)
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err == nil || info != nil {
+		t.Fatalf("Collect() = %v, %v, want nil and an error", info, err)
+	}
+}
+
+// countingMetrics 按指标名和标签记录计数器的累计值
+type countingMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func (m *countingMetrics) IncCounter(name string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name+","+labels["module"]+","+labels["result"]]++
+}
+
+func (m *countingMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
+
+func TestCollectReportsModuleMetrics(t *testing.T) {
+	failing := moduleCollector{
+		name:     "gpu",
+		optional: true,
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			return nil, errors.New("nvidia-smi not found")
+		},
+	}
+	setModules(t, systemModule("system", "boot-1"), failing)
+	c := newTestCollector(t)
+	m := &countingMetrics{counters: map[string]int{}}
+	c.SetMetrics(m)
+
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{
+		"collector_module_total,system,success": 1,
+		"collector_module_total,gpu,failure":    1,
+	}
+	if !maps.Equal(m.counters, want) {
+		t.Errorf("counters = %v, want %v", m.counters, want)
+	}
+}
+
+func TestCollectSerializesDifferentModuleSets(t *testing.T) {
+	var (
+		mu              sync.Mutex
+		running, maxRun int
+	)
+	probe := func(name string) moduleCollector {
+		return moduleCollector{
+			name: name,
+			collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+				mu.Lock()
+				running++
+				maxRun = max(maxRun, running)
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return func(*model.HardwareInfo) {}, nil
+			},
+		}
+	}
+	setModules(t, probe("system"), probe("memory"))
+	c := newTestCollector(t)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			modules := []string{"system"}
+			if i%2 == 1 {
+				modules = []string{"memory"}
+			}
+			if _, err := c.Collect(context.Background(), modules); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxRun != 1 {
+		t.Errorf("%d collections ran at the same time, want them queued", maxRun)
+	}
+}
+
+func TestCollectQueuedCallerCanceled(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := moduleCollector{
+		name: "system",
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			close(started)
+			<-release
+			return func(*model.HardwareInfo) {}, nil
+		},
+	}
+	setModules(t, slow, systemModule("memory", "boot-1"))
+	c := newTestCollector(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Collect(context.Background(), []string{"system"})
+	}()
+	<-started
+
+	// 排队等待期间 ctx 到期，直接返回而不是等前一次采集结束
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Collect(ctx, []string{"memory"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestCollectSharesInflightResult(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	probe := moduleCollector{
+		name: "system",
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			calls.Add(1)
+			<-release
+			return func(info *model.HardwareInfo) { info.System = &model.System{BootID: "boot-1"} }, nil
+		},
+	}
+	setModules(t, probe)
+	c := newTestCollector(t)
+
+	const n = 10
+	results := make([]*model.HardwareInfo, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := c.Collect(context.Background(), []string{"system"})
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = info
+		}()
+	}
+	// 等所有调用方都加入进行中的采集后再放行
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("probe ran %d times, want 1", got)
+	}
+	for i, info := range results {
+		if info != results[0] || info.System.BootID != "boot-1" {
+			t.Errorf("caller %d got %p, want the shared result %p", i, info, results[0])
+		}
+	}
+}
+
+func TestModuleKey(t *testing.T) {
+	tests := []struct {
+		modules []string
+		want    string
+	}{
+		{nil, "*"},
+		{[]string{"disk", "system"}, "disk,system"},
+		{[]string{"system", "disk", "system"}, "disk,system"},
+	}
+	for _, tt := range tests {
+		if got := moduleKey(tt.modules); got != tt.want {
+			t.Errorf("moduleKey(%q) = %q, want %q", tt.modules, got, tt.want)
+		}
+	}
+}
+
+func TestCollectRecordsPermissionWarnings(t *testing.T) {
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("/dev/mem: Permission denied\n"), errors.New("exit status 1")
+	})
+	t.Cleanup(func() { executor.SetRunner(nil) })
+
+	dmidecode := moduleCollector{
+		name:     "memory",
+		optional: true,
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			_, err := executor.ExecuteWithContext(ctx, "dmidecode", "-t", "17")
+			return nil, err
+		},
+	}
+	setModules(t, systemModule("system", "boot-1"), dmidecode)
+	c := newTestCollector(t)
+
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.PermissionWarnings(); !slices.Equal(got, []string{"dmidecode -t 17"}) {
+		t.Errorf("PermissionWarnings() = %q, want the dmidecode command", got)
+	}
+
+	// 每次采集重新记录
+	setModules(t, systemModule("system", "boot-1"))
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.PermissionWarnings(); got != nil {
+		t.Errorf("PermissionWarnings() = %q after a clean collection", got)
+	}
+}
+
+func TestCollectStats(t *testing.T) {
+	failing := moduleCollector{
+		name:     "gpu",
+		optional: true,
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			return nil, errors.New("nvidia-smi not found")
+		},
+	}
+	setModules(t, systemModule("system", "boot-1"), failing)
+	c := newTestCollector(t)
+
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	stats := c.LastStats()
+	if !slices.Equal(stats.Modules, []string{"system", "gpu"}) {
+		t.Errorf("Modules = %v", stats.Modules)
+	}
+	if _, ok := stats.Durations["system"]; !ok || len(stats.Durations) != 2 {
+		t.Errorf("Durations = %v, want one per module", stats.Durations)
+	}
+	if !stats.Changed {
+		t.Error("first collection not reported as changed")
+	}
+
+	// 数据未变化
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.LastStats().Changed {
+		t.Error("unchanged collection reported as changed")
+	}
+
+	// 数据变化
+	setModules(t, systemModule("system", "boot-2"))
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !c.LastStats().Changed {
+		t.Error("changed collection not reported as changed")
+	}
+}
+
+func TestCollectIgnoresVolatileFields(t *testing.T) {
+	var runs int
+	module := func(release string) moduleCollector {
+		return moduleCollector{name: "system", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			runs++
+			v := model.System{
+				Kernel:      model.Kernel{Release: release},
+				Uptime:      model.Uptime{Seconds: strconv.Itoa(runs)},
+				LoadAverage: model.LoadAverage{Load1: strconv.Itoa(runs)},
+			}
+			return func(info *model.HardwareInfo) {
+				info.System = &v
+				info.Memory = &model.Memory{Total: 1 << 30, Used: uint64(runs), Free: uint64(runs)}
+			}, nil
+		}}
+	}
+	setModules(t, module("6.8.0"))
+	c := newTestCollector(t)
+
+	for range 2 {
+		if _, err := c.Collect(context.Background(), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 只有运行时间、负载和内存用量变化
+	if c.LastStats().Changed {
+		t.Error("collection with only volatile changes reported as changed")
+	}
+
+	setModules(t, module("6.9.0"))
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !c.LastStats().Changed {
+		t.Error("kernel upgrade not reported as changed")
+	}
+}
+
+// moduleByName 返回名为 name 的内置采集模块
+func moduleByName(t *testing.T, name string) moduleCollector {
+	t.Helper()
+	for _, m := range moduleCollectors {
+		if m.name == name {
+			return m
+		}
+	}
+	t.Fatalf("no module %q", name)
+	return moduleCollector{}
+}
+
+func TestCollectGPUFailureIsWarning(t *testing.T) {
+	testutil.FakeCommands(t, nil)
+	setModules(t, systemModule("system", "boot-1"), moduleByName(t, "gpu"))
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.GPU != nil {
+		t.Errorf("GPU = %+v, want none", info.GPU)
+	}
+	warnings := c.Warnings()
+	if len(warnings) != 1 || warnings[0].Module != "gpu" || !strings.Contains(warnings[0].Message, "nvidia-smi") {
+		t.Errorf("Warnings() = %v, want the nvidia-smi failure", warnings)
+	}
+
+	// 每次采集重新记录
+	setModules(t, systemModule("system", "boot-1"))
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Warnings(); len(got) != 0 {
+		t.Errorf("Warnings() = %v after a clean collection", got)
+	}
+}
+
+func TestCollectAppliesResultsSequentially(t *testing.T) {
+	// apply 中无锁地追加到同一切片，并发写入时 -race 会报告
+	var order []string
+	mods := make([]moduleCollector, 0, 16)
+	for i := range 16 {
+		name := string(rune('a' + i))
+		mods = append(mods, moduleCollector{
+			name:     name,
+			optional: i%4 == 3,
+			collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+				if i%4 == 3 {
+					return nil, errors.New(name + " failed")
+				}
+				return func(info *model.HardwareInfo) {
+					order = append(order, name)
+					info.GPU = append(info.GPU, model.GPU{Index: name})
+				}, nil
+			},
+		})
+	}
+	setModules(t, mods...)
+	c := newTestCollector(t)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Collect(context.Background(), nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.GPU) != 12 {
+		t.Errorf("got %d module results, want 12", len(info.GPU))
+	}
+	if got := len(c.Warnings()); got != 4 {
+		t.Errorf("got %d warnings, want one per failing optional module", got)
+	}
+}
+
+func TestValidateModules(t *testing.T) {
+	if err := ValidateModules([]string{"system", "disk", "gpu"}); err != nil {
+		t.Errorf("ValidateModules() of built-in modules = %v", err)
+	}
+	if err := ValidateModules(nil); err != nil {
+		t.Errorf("ValidateModules(nil) = %v", err)
+	}
+	if err := ValidateModules([]string{"system", "smart"}); err == nil || !strings.Contains(err.Error(), "smart") {
+		t.Errorf("ValidateModules() = %v, want an error naming the unknown module", err)
+	}
+}
+
+func TestCollectOnlyConfiguredModules(t *testing.T) {
+	var ran []string
+	var mu sync.Mutex
+	probe := func(name string) moduleCollector {
+		return moduleCollector{
+			name: name,
+			collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+				mu.Lock()
+				ran = append(ran, name)
+				mu.Unlock()
+				return func(*model.HardwareInfo) {}, nil
+			},
+		}
+	}
+	setModules(t, probe("system"), probe("memory"), probe("disk"), probe("network"))
+	c := newTestCollector(t)
+
+	if _, err := c.Collect(context.Background(), []string{"network", "system"}); err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(ran)
+	if !slices.Equal(ran, []string{"network", "system"}) {
+		t.Errorf("collected %q, want only network and system", ran)
+	}
+	if !slices.Equal(c.LastStats().Modules, []string{"system", "network"}) {
+		t.Errorf("LastStats().Modules = %q", c.LastStats().Modules)
+	}
+}
+
+func TestCollectIdentity(t *testing.T) {
+	setModules(t, systemModule("system", "boot-1"))
+	c := newTestCollector(t)
+
+	c.SetIdentity(func() (string, error) { return "rack1-node7", nil })
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Hostname != "rack1-node7" {
+		t.Errorf("Hostname = %q, want the resolved identity", info.Hostname)
+	}
+
+	// 解析失败时回退到 os.Hostname 并记录警告
+	c.SetIdentity(func() (string, error) { return "", errors.New("environment variable NODE_NAME is empty") })
+	info, err = c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := os.Hostname(); info.Hostname != want {
+		t.Errorf("Hostname = %q, want %q", info.Hostname, want)
+	}
+	if w := c.Warnings(); len(w) != 1 || w[0].Module != "identity" {
+		t.Errorf("Warnings() = %v, want the identity failure", w)
+	}
+}
+
+func TestCollectModuleTimeouts(t *testing.T) {
+	setModules(t, systemModule("system", "boot-1"), blockingModule("disk"))
+	c := newTestCollector(t)
+	if err := c.SetModuleTimeouts(map[string]time.Duration{"disk": 20 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 只有超时的模块失败,其他模块的结果照常返回
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Collect() = %v, want the timed out module reported as a module error", err)
+	}
+	if info.System == nil || info.System.BootID != "boot-1" {
+		t.Errorf("System = %+v", info.System)
+	}
+	errs := c.LastStats().Errors
+	if len(errs) != 1 || errs[0].Module != "disk" || !strings.Contains(errs[0].Message, "deadline exceeded") {
+		t.Errorf("Errors = %v, want the disk timeout", errs)
+	}
+
+	if err := c.SetModuleTimeouts(map[string]time.Duration{"smart": time.Second}); err == nil {
+		t.Error("SetModuleTimeouts() accepted an unknown module")
+	}
+}
+
+func TestCollectPCIDegradedIsWarning(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/bus/pci/devices/0000:5e:00.0/aer_dev_correctable": "TOTAL_ERR_COR 7\n",
+		"/sys/bus/pci/devices/0000:5e:00.0/aer_dev_fatal":       "TOTAL_ERR_FATAL 0\n",
+		"/sys/bus/pci/devices/0000:5e:00.0/aer_dev_nonfatal":    "TOTAL_ERR_NONFATAL 2\n",
+	})
+	setModules(t, systemModule("system", "boot-1"), moduleByName(t, "pci"))
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.PCI) != 1 || !info.PCI[0].Degraded || info.PCI[0].AER.Correctable != "7" {
+		t.Errorf("PCI = %+v, want the degraded device", info.PCI)
+	}
+	warnings := c.Warnings()
+	if len(warnings) != 1 || warnings[0].Module != "pci" || !strings.Contains(warnings[0].Message, "0000:5e:00.0") {
+		t.Errorf("Warnings() = %v, want the uncorrectable AER errors", warnings)
+	}
+}
+
+func TestCollectIntoRefreshesOnlyNamedModules(t *testing.T) {
+	var memoryRuns int
+	setModules(t,
+		systemModule("system", "boot-2"),
+		moduleCollector{name: "memory", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			memoryRuns++
+			return func(info *model.HardwareInfo) { info.Memory = &model.Memory{Used: uint64(memoryRuns)} }, nil
+		}},
+		moduleCollector{name: "disk", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			return nil, errors.New("lsblk failed")
+		}},
+	)
+	c := newTestCollector(t)
+
+	system := &model.System{BootID: "boot-1"}
+	disk := &model.Storage{}
+	dst := &model.HardwareInfo{System: system, Disk: disk, Memory: &model.Memory{Used: 100}}
+
+	if err := c.CollectInto(context.Background(), dst, []string{"memory"}); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Memory == nil || dst.Memory.Used != 1 {
+		t.Errorf("Memory = %+v, want it refreshed", dst.Memory)
+	}
+	if dst.System != system || dst.Disk != disk {
+		t.Errorf("unrequested sections changed: System = %+v, Disk = %p", dst.System, dst.Disk)
+	}
+	if dst.Timestamp.IsZero() || dst.Hostname == "" {
+		t.Errorf("Timestamp = %v, Hostname = %q, want them set", dst.Timestamp, dst.Hostname)
+	}
+
+	// 采集失败的模块保留原值
+	if err := c.CollectInto(context.Background(), dst, []string{"memory", "disk"}); err == nil {
+		t.Fatal("CollectInto() with a failing module succeeded")
+	}
+	if dst.Disk != disk || dst.Memory.Used != 2 {
+		t.Errorf("after a failed refresh Disk = %p, Memory = %+v", dst.Disk, dst.Memory)
+	}
+
+	if err := c.CollectInto(context.Background(), nil, nil); err == nil {
+		t.Error("CollectInto(nil) succeeded")
+	}
+}
+
+func TestCollectResultIsIndependentOfBaseline(t *testing.T) {
+	// 与内置模块一样,应用函数把同一份采集结果写入返回值和变化检测的基准
+	setModules(t, moduleCollector{name: "system", collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+		v := model.System{BootID: "boot-1"}
+		return func(info *model.HardwareInfo) { info.System = &v }, nil
+	}})
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 调用方修改返回结果中的模块数据,不影响下一次的变化检测
+	info.System.BootID = "tampered"
+	dst := &model.HardwareInfo{System: &model.System{BootID: "tampered"}}
+	if err := c.CollectInto(context.Background(), dst, []string{"system"}); err != nil {
+		t.Fatal(err)
+	}
+	if c.LastStats().Changed {
+		t.Error("collection reported as changed after the caller modified the previous result")
+	}
+	dst.System.BootID = "tampered again"
+
+	if _, err := c.Collect(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.LastStats().Changed {
+		t.Error("collection reported as changed after the caller modified a CollectInto result")
+	}
+}
+
+func TestCollectServiceIsOptIn(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/run/systemd/system/.keep": ""})
+	testutil.FakeCommands(t, map[string]string{
+		"systemctl --failed --no-legend --plain": "nginx.service loaded failed failed A high performance web server\n",
+	})
+	setModules(t, systemModule("system", "boot-1"), moduleByName(t, "service"))
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Service != nil || !slices.Equal(c.LastStats().Modules, []string{"system"}) {
+		t.Errorf("default collection ran %v, Service = %+v, want service skipped", c.LastStats().Modules, info.Service)
+	}
+
+	info, err = c.Collect(context.Background(), []string{"service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Service == nil || info.Service.FailedCount != 1 {
+		t.Errorf("Service = %+v, want the failed unit", info.Service)
+	}
+}
+
+func TestCollectRecoversModulePanic(t *testing.T) {
+	panicking := moduleCollector{
+		name: "disk",
+		collect: func(c *Collector, ctx context.Context) (func(*model.HardwareInfo), error) {
+			// 模拟解析异常的工具输出时的空指针
+			var lsblk *model.Storage
+			_ = lsblk.BlockDevices
+			return nil, nil
+		},
+	}
+	setModules(t, systemModule("system", "boot-1"), panicking)
+	c := newTestCollector(t)
+
+	var logs bytes.Buffer
+	saved := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(saved) })
+
+	// 其他模块的结果照常返回,只标记发生 panic 的模块失败
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Collect() = %v, want the panic reported as a module error", err)
+	}
+	if info.System == nil || info.System.BootID != "boot-1" || info.Disk != nil {
+		t.Errorf("info = %+v, want system only", info)
+	}
+	errs := c.LastStats().Errors
+	if len(errs) != 1 || errs[0].Module != "disk" || !strings.Contains(errs[0].Message, "panicked") || !strings.Contains(errs[0].Message, "nil pointer") {
+		t.Errorf("Errors = %v, want the disk panic", errs)
+	}
+	if warnings := c.Warnings(); len(warnings) != 1 || warnings[0].Module != "disk" {
+		t.Errorf("Warnings() = %v, want the disk panic", warnings)
+	}
+
+	// 记录堆栈,便于定位触发 panic 的代码
+	if out := logs.String(); !strings.Contains(out, "module=disk") || !strings.Contains(out, "TestCollectRecoversModulePanic") {
+		t.Errorf("log = %s, want the module and its stack", out)
+	}
+}
@@ -0,0 +1,135 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	sysfsBlock string = "/sys/block"
+	sectorSize uint64 = 512 // /sys/block/<dev>/size 的单位固定为512字节
+)
+
+// ignoredPrefixes 不采集的虚拟块设备
+var ignoredPrefixes = []string{"loop", "ram", "zram"}
+
+// Collect 采集块设备树(物理盘 -> 分区 -> MD RAID/LVM)、MD软RAID状态和NVMe健康信息，
+// minimal 采集配置下跳过NVMe健康信息(SMART)
+func Collect(ctx context.Context) (model.Storage, error) {
+	var storage model.Storage
+
+	dirs, err := os.ReadDir(utils.HostPath(sysfsBlock))
+	if err != nil {
+		return storage, fmt.Errorf("read directory %s failed: %w", sysfsBlock, err)
+	}
+
+	for _, dir := range dirs {
+		name := dir.Name()
+		if slices.ContainsFunc(ignoredPrefixes, func(p string) bool { return strings.HasPrefix(name, p) }) {
+			continue
+		}
+		if utils.DeviceExcluded(name) {
+			continue
+		}
+		storage.BlockDevices = append(storage.BlockDevices, collectBlockDevice(name))
+	}
+
+	mdstat, err := utils.ReadSysfsFile(utils.HostPath(procMdstat))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return storage, fmt.Errorf("read %s failed: %w", procMdstat, err)
+	}
+	storage.MDRaids = parseMdstat(mdstat)
+
+	if utils.IsMinimal(ctx) {
+		return storage, nil
+	}
+
+	if storage.NVMes, err = collectNVMe(ctx); err != nil {
+		return storage, err
+	}
+
+	return storage, nil
+}
+
+func collectBlockDevice(name string) model.BlockDevice {
+	dir := filepath.Join(utils.HostPath(sysfsBlock), name)
+
+	dev := model.BlockDevice{
+		Name:    name,
+		Type:    blockDeviceType(dir),
+		Size:    readSize(dir),
+		Slaves:  listDir(filepath.Join(dir, "slaves")),
+		Holders: listDir(filepath.Join(dir, "holders")),
+		Queue:   collectQueue(dir),
+	}
+	if dev.Type == "lvm" || dev.Type == "dm" {
+		dev.DMName, _ = utils.ReadSysfsFile(filepath.Join(dir, "dm", "name"))
+	}
+	if dev.Type == "disk" {
+		fillIdentity(&dev, dir)
+	}
+
+	// 分区是设备目录下包含 partition 文件的子目录
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		partDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(partDir, "partition")); err != nil {
+			continue
+		}
+		dev.Partitions = append(dev.Partitions, model.Partition{
+			Name:    entry.Name(),
+			Size:    readSize(partDir),
+			Holders: listDir(filepath.Join(partDir, "holders")),
+		})
+	}
+
+	return dev
+}
+
+// blockDeviceType 根据sysfs属性目录判断设备类型
+func blockDeviceType(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "md")); err == nil {
+		return "md"
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dm")); err == nil {
+		uuid, _ := utils.ReadSysfsFile(filepath.Join(dir, "dm", "uuid"))
+		if strings.HasPrefix(uuid, "LVM-") {
+			return "lvm"
+		}
+		return "dm"
+	}
+	return "disk"
+}
+
+// readSize 读取以扇区为单位的容量，转换为字节
+func readSize(dir string) string {
+	sectors, err := utils.ReadSysfsUint64(filepath.Join(dir, "size"))
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatUint(sectors*sectorSize, 10)
+}
+
+// listDir 返回目录下的条目名称，目录不存在或为空时返回nil
+func listDir(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
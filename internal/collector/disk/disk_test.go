@@ -0,0 +1,100 @@
+package disk
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCollectBlockDeviceTree(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		// 两块物理盘的第一个分区组成md0，md0上是LVM卷
+		"/sys/block/sda/size":             "1953525168\n",
+		"/sys/block/sda/device/model":     "ST1000NM0033\n",
+		"/sys/block/sda/sda1/partition":   "1\n",
+		"/sys/block/sda/sda1/size":        "2048\n",
+		"/sys/block/sda/sda1/holders/md0": "",
+		"/sys/block/sdb/size":             "1953525168\n",
+		"/sys/block/sdb/sdb1/partition":   "1\n",
+		"/sys/block/sdb/sdb1/size":        "2048\n",
+		"/sys/block/sdb/sdb1/holders/md0": "",
+		"/sys/block/md0/size":             "2048\n",
+		"/sys/block/md0/md/level":         "raid1\n",
+		"/sys/block/md0/slaves/sda1":      "",
+		"/sys/block/md0/slaves/sdb1":      "",
+		"/sys/block/md0/holders/dm-0":     "",
+		"/sys/block/dm-0/size":            "1024\n",
+		"/sys/block/dm-0/dm/name":         "vg0-root\n",
+		"/sys/block/dm-0/dm/uuid":         "LVM-abcdef\n",
+		"/sys/block/dm-0/slaves/md0":      "",
+		"/sys/block/loop0/size":           "0\n",
+		procMdstat:                        "Personalities : [raid1]\nmd0 : active raid1 sdb1[1] sda1[0]\n      1024 blocks super 1.2 [2/2] [UU]\n\nunused devices: <none>\n",
+	})
+
+	storage, err := Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	devices := make(map[string]int)
+	for i, dev := range storage.BlockDevices {
+		devices[dev.Name] = i
+	}
+	if _, ok := devices["loop0"]; ok {
+		t.Error("loop device collected")
+	}
+	if len(storage.BlockDevices) != 4 {
+		t.Fatalf("got %d block devices, want 4: %+v", len(storage.BlockDevices), storage.BlockDevices)
+	}
+
+	sda := storage.BlockDevices[devices["sda"]]
+	if sda.Type != "disk" || sda.Size != "1000204886016" || sda.Model != "ST1000NM0033" {
+		t.Errorf("sda = %+v", sda)
+	}
+	if len(sda.Partitions) != 1 || sda.Partitions[0].Name != "sda1" || sda.Partitions[0].Size != "1048576" ||
+		!reflect.DeepEqual(sda.Partitions[0].Holders, []string{"md0"}) {
+		t.Errorf("sda partitions = %+v", sda.Partitions)
+	}
+
+	md0 := storage.BlockDevices[devices["md0"]]
+	if md0.Type != "md" || !reflect.DeepEqual(md0.Slaves, []string{"sda1", "sdb1"}) || !reflect.DeepEqual(md0.Holders, []string{"dm-0"}) {
+		t.Errorf("md0 = %+v", md0)
+	}
+
+	dm0 := storage.BlockDevices[devices["dm-0"]]
+	if dm0.Type != "lvm" || dm0.DMName != "vg0-root" || !reflect.DeepEqual(dm0.Slaves, []string{"md0"}) {
+		t.Errorf("dm-0 = %+v", dm0)
+	}
+
+	if len(storage.MDRaids) != 1 || storage.MDRaids[0].Health != "clean" {
+		t.Errorf("md raids = %+v", storage.MDRaids)
+	}
+}
+
+func TestCollectExcludedDevices(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/block/sda/size": "1\n",
+		"/sys/block/sdb/size": "1\n",
+	})
+	utils.SetExcludedDevices([]string{"sdb"})
+	t.Cleanup(func() { utils.SetExcludedDevices(nil) })
+
+	storage, err := Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(storage.BlockDevices) != 1 || storage.BlockDevices[0].Name != "sda" {
+		t.Errorf("block devices = %+v, want only sda", storage.BlockDevices)
+	}
+}
+
+func TestCollectWithoutSysBlock(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	if _, err := Collect(context.Background()); err == nil {
+		t.Error("Collect() without /sys/block succeeded")
+	}
+}
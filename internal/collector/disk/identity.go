@@ -0,0 +1,82 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const devDiskByID string = "/dev/disk/by-id"
+
+// fillIdentity 采集磁盘的型号、序列号、WWN和by-id链接，virtio、loop等虚拟设备通常没有这些属性
+func fillIdentity(dev *model.BlockDevice, dir string) {
+	devDir := filepath.Join(dir, "device")
+	read := func(path string) string {
+		v, _ := utils.ReadSysfsFile(path)
+		return v
+	}
+
+	dev.Model = read(filepath.Join(devDir, "model"))
+
+	// NVMe的序列号在控制器目录下，SCSI/SATA磁盘的序列号在VPD 0x80页中
+	dev.Serial = read(filepath.Join(devDir, "serial"))
+	if dev.Serial == "" {
+		dev.Serial = readVPDSerial(filepath.Join(devDir, "vpd_pg80"))
+	}
+
+	// NVMe命名空间的wwid在块设备目录下，SCSI磁盘在device目录下
+	dev.WWN = read(filepath.Join(dir, "wwid"))
+	if dev.WWN == "" {
+		dev.WWN = read(filepath.Join(devDir, "wwid"))
+	}
+
+	dev.ByID = listByID(dev.Name)
+	if dev.WWN == "" {
+		for _, link := range dev.ByID {
+			if wwn, ok := strings.CutPrefix(link, "wwn-"); ok {
+				dev.WWN = wwn
+				break
+			}
+		}
+	}
+}
+
+// readVPDSerial 解析SCSI VPD 0x80页(Unit Serial Number)，前4字节为页头。
+// 磁盘页头的首字节(外设类型)为0，读取时去掉首尾空白不会破坏页头
+func readVPDSerial(path string) string {
+	data, err := utils.ReadSysfsFile(path)
+	if err != nil || len(data) <= 4 || data[1] != 0x80 {
+		return ""
+	}
+	return strings.TrimFunc(string(data[4:]), func(r rune) bool {
+		return unicode.IsSpace(r) || r == 0
+	})
+}
+
+// listByID 返回/dev/disk/by-id下指向设备name的链接名称
+func listByID(name string) []string {
+	dir := utils.HostPath(devDiskByID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if filepath.Base(target) == name {
+			links = append(links, entry.Name())
+		}
+	}
+	slices.Sort(links)
+
+	return links
+}
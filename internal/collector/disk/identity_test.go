@@ -0,0 +1,65 @@
+package disk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestFillIdentity(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		// SATA磁盘：序列号在VPD 0x80页，WWN来自by-id链接
+		"/sys/block/sda/device/model":    "ST1000NM0033-9ZM\n",
+		"/sys/block/sda/device/vpd_pg80": "\x00\x80\x00\x14        Z1W0ABCD\x00",
+		// NVMe命名空间：wwid在块设备目录，序列号在device目录
+		"/sys/block/nvme0n1/wwid":          "eui.0025388b91b3c4e1\n",
+		"/sys/block/nvme0n1/device/model":  "SAMSUNG MZQLB3T8HALS-00007\n",
+		"/sys/block/nvme0n1/device/serial": "S4EWNX0R123456\n",
+		// virtio磁盘没有任何标识
+		"/sys/block/vda/size": "1\n",
+	})
+	testutil.Symlink(t, root, "../../sda", "/dev/disk/by-id/ata-ST1000NM0033-9ZM_Z1W0ABCD")
+	testutil.Symlink(t, root, "../../sda", "/dev/disk/by-id/wwn-0x5000c500a1b2c3d4")
+	testutil.Symlink(t, root, "../../sda1", "/dev/disk/by-id/wwn-0x5000c500a1b2c3d4-part1")
+	testutil.Symlink(t, root, "../../nvme0n1", "/dev/disk/by-id/nvme-eui.0025388b91b3c4e1")
+
+	tests := []struct {
+		name string
+		want model.BlockDevice
+	}{
+		{"sda", model.BlockDevice{
+			Name: "sda", Model: "ST1000NM0033-9ZM", Serial: "Z1W0ABCD", WWN: "0x5000c500a1b2c3d4",
+			ByID: []string{"ata-ST1000NM0033-9ZM_Z1W0ABCD", "wwn-0x5000c500a1b2c3d4"},
+		}},
+		{"nvme0n1", model.BlockDevice{
+			Name: "nvme0n1", Model: "SAMSUNG MZQLB3T8HALS-00007", Serial: "S4EWNX0R123456", WWN: "eui.0025388b91b3c4e1",
+			ByID: []string{"nvme-eui.0025388b91b3c4e1"},
+		}},
+		{"vda", model.BlockDevice{Name: "vda"}},
+	}
+	for _, tt := range tests {
+		dev := model.BlockDevice{Name: tt.name}
+		fillIdentity(&dev, root+"/sys/block/"+tt.name)
+		if !reflect.DeepEqual(dev, tt.want) {
+			t.Errorf("%s: fillIdentity() =\n%+v\nwant\n%+v", tt.name, dev, tt.want)
+		}
+	}
+}
+
+func TestReadVPDSerial(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		"/ok":     "\x00\x80\x00\x0aSERIAL0001",
+		"/wrong":  "\x00\x83\x00\x0aSERIAL0001",
+		"/short":  "\x00\x80\x00",
+		"/padded": "\x00\x80\x00\x10  SN42  \x00\x00\n",
+	})
+
+	tests := map[string]string{"/ok": "SERIAL0001", "/wrong": "", "/short": "", "/padded": "SN42", "/missing": ""}
+	for path, want := range tests {
+		if got := readVPDSerial(root + path); got != want {
+			t.Errorf("readVPDSerial(%s) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package disk
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+const procMdstat string = "/proc/mdstat"
+
+// mdMemberStates /proc/mdstat中成员盘后缀标记的含义
+var mdMemberStates = map[string]string{
+	"F": "faulty",
+	"S": "spare",
+	"W": "write-mostly",
+	"R": "replacement",
+}
+
+// parseMdstat 解析/proc/mdstat，格式示例：
+//
+//	Personalities : [raid1] [raid6] [raid5] [raid4]
+//	md0 : active raid1 sdb1[1] sda1[0](F)
+//	      1048512 blocks super 1.2 [2/1] [U_]
+//	      [=>...................]  recovery = 12.6% (132480/1048512) finish=0.1min speed=132480K/sec
+//
+//	md1 : active raid5 sdd[3] sdc[1] sdb[0]
+//	      2095104 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/3] [UUU]
+//	      bitmap: 0/1 pages [0KB], 65536KB chunk
+//
+//	md127 : inactive sde[0](S)
+//	      1048576 blocks super 1.2
+//
+//	unused devices: <none>
+func parseMdstat(text string) []model.MDRaid {
+	var (
+		raids []model.MDRaid
+		cur   *model.MDRaid
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			cur = nil
+		case strings.HasPrefix(line, "md"):
+			name, rest, ok := strings.Cut(line, " : ")
+			if !ok {
+				cur = nil
+				continue
+			}
+			raids = append(raids, parseMdHeader(strings.TrimSpace(name), rest))
+			cur = &raids[len(raids)-1]
+		case cur != nil && (line[0] == ' ' || line[0] == '\t'):
+			parseMdDetail(cur, trimmed)
+		}
+	}
+
+	for i := range raids {
+		raids[i].Health = mdHealth(&raids[i])
+	}
+
+	return raids
+}
+
+// parseMdHeader 解析阵列首行 "active raid1 sdb1[1] sda1[0](F)"
+func parseMdHeader(name, rest string) model.MDRaid {
+	raid := model.MDRaid{Name: name}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return raid
+	}
+	raid.State = fields[0]
+
+	for _, f := range fields[1:] {
+		// 跳过 (auto-read-only)、(read-only) 等附加状态
+		if strings.HasPrefix(f, "(") {
+			continue
+		}
+
+		open := strings.IndexByte(f, '[')
+		if open < 0 {
+			if raid.Level == "" {
+				raid.Level = f
+			}
+			continue
+		}
+
+		member := model.MDMember{Name: f[:open], State: "active"}
+		closing := strings.IndexByte(f, ']')
+		if closing > open {
+			member.Role = f[open+1 : closing]
+			flags := f[closing+1:]
+			for flag := range strings.SplitSeq(strings.Trim(flags, "()"), ")(") {
+				if state, ok := mdMemberStates[flag]; ok {
+					member.State = state
+				}
+			}
+		}
+		raid.Members = append(raid.Members, member)
+	}
+
+	return raid
+}
+
+// parseMdDetail 解析阵列的后续缩进行，包括容量、成员状态和同步进度
+func parseMdDetail(raid *model.MDRaid, line string) {
+	fields := strings.Fields(line)
+
+	if len(fields) > 1 && fields[1] == "blocks" {
+		raid.Blocks = fields[0]
+		for _, f := range fields {
+			if !strings.HasPrefix(f, "[") || !strings.HasSuffix(f, "]") {
+				continue
+			}
+			inner := f[1 : len(f)-1]
+			if strings.Contains(inner, "/") {
+				raid.Devices = inner
+			} else if strings.Trim(inner, "U_") == "" {
+				raid.Status = inner
+			}
+		}
+		return
+	}
+
+	for i, f := range fields {
+		if f == "=" && i > 0 && i+1 < len(fields) {
+			switch fields[i-1] {
+			case "resync", "recovery", "reshape", "check", "repair":
+				raid.SyncAction = fields[i-1]
+				raid.SyncProgress = fields[i+1]
+			}
+		}
+	}
+}
+
+// mdHealth 根据成员状态判断阵列健康状态
+func mdHealth(raid *model.MDRaid) string {
+	if raid.State != "active" {
+		return raid.State
+	}
+	if strings.Contains(raid.Status, "_") {
+		return "degraded"
+	}
+
+	if total, active, ok := strings.Cut(raid.Devices, "/"); ok && total != active {
+		return "degraded"
+	}
+	return "clean"
+}
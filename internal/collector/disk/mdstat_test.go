@@ -0,0 +1,70 @@
+package disk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+const mdstatFixture = `Personalities : [raid1] [raid6] [raid5] [raid4]
+md0 : active raid1 sdb1[1] sda1[0](F)
+      1048512 blocks super 1.2 [2/1] [_U]
+      [=>...................]  recovery = 12.6% (132480/1048512) finish=0.1min speed=132480K/sec
+
+md1 : active raid5 sdd[3] sdc[1] sdb[0] sde[4](S)
+      2095104 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/3] [UUU]
+      bitmap: 0/1 pages [0KB], 65536KB chunk
+
+md127 : inactive sdf[0](S)
+      1048576 blocks super 1.2
+
+unused devices: <none>
+`
+
+func TestParseMdstat(t *testing.T) {
+	want := []model.MDRaid{
+		{
+			Name: "md0", Level: "raid1", State: "active", Health: "degraded",
+			Devices: "2/1", Status: "_U", Blocks: "1048512",
+			SyncAction: "recovery", SyncProgress: "12.6%",
+			Members: []model.MDMember{
+				{Name: "sdb1", Role: "1", State: "active"},
+				{Name: "sda1", Role: "0", State: "faulty"},
+			},
+		},
+		{
+			Name: "md1", Level: "raid5", State: "active", Health: "clean",
+			Devices: "3/3", Status: "UUU", Blocks: "2095104",
+			Members: []model.MDMember{
+				{Name: "sdd", Role: "3", State: "active"},
+				{Name: "sdc", Role: "1", State: "active"},
+				{Name: "sdb", Role: "0", State: "active"},
+				{Name: "sde", Role: "4", State: "spare"},
+			},
+		},
+		{
+			Name: "md127", State: "inactive", Health: "inactive", Blocks: "1048576",
+			Members: []model.MDMember{{Name: "sdf", Role: "0", State: "spare"}},
+		},
+	}
+
+	got := parseMdstat(mdstatFixture)
+	if len(got) != len(want) {
+		t.Fatalf("got %d arrays, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("array %d =\n%+v\nwant\n%+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseMdstatEmpty(t *testing.T) {
+	if got := parseMdstat("Personalities : \nunused devices: <none>\n"); got != nil {
+		t.Errorf("parseMdstat() = %+v, want no arrays", got)
+	}
+	if got := parseMdstat(""); got != nil {
+		t.Errorf("parseMdstat(\"\") = %+v, want no arrays", got)
+	}
+}
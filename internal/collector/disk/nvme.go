@@ -0,0 +1,159 @@
+package disk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	nvmeCLI     string = "nvme"
+	sysfsNVMe   string = "/sys/class/nvme"
+	devNVMeRoot string = "/dev"
+)
+
+// criticalWarningBits NVMe SMART日志 critical_warning 字段各比特位的含义
+var criticalWarningBits = []string{
+	"available_spare_below_threshold",
+	"temperature_threshold_exceeded",
+	"reliability_degraded",
+	"media_read_only",
+	"volatile_memory_backup_failed",
+	"persistent_memory_region_read_only",
+}
+
+// collectNVMe 通过nvme命令采集所有NVMe控制器的健康信息，未安装nvme工具时返回空结果
+func collectNVMe(ctx context.Context) ([]model.NVMe, error) {
+	dirs, err := os.ReadDir(utils.HostPath(sysfsNVMe))
+	if err != nil {
+		return nil, nil
+	}
+
+	var nvmes []model.NVMe
+	for _, dir := range dirs {
+		if utils.DeviceExcluded(dir.Name()) {
+			continue
+		}
+		dev := filepath.Join(devNVMeRoot, dir.Name())
+
+		nvme, err := collectNVMeDevice(ctx, dev)
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			continue
+		}
+		nvmes = append(nvmes, nvme)
+	}
+
+	return nvmes, nil
+}
+
+func collectNVMeDevice(ctx context.Context, dev string) (model.NVMe, error) {
+	nvme := model.NVMe{Device: dev}
+
+	out, err := executor.ExecuteWithContext(ctx, nvmeCLI, "id-ctrl", "-o", "json", dev)
+	if err != nil {
+		return nvme, fmt.Errorf("run %s id-ctrl %s failed: %w", nvmeCLI, dev, err)
+	}
+	if err := parseIDCtrl(out, &nvme); err != nil {
+		return nvme, err
+	}
+
+	out, err = executor.ExecuteWithContext(ctx, nvmeCLI, "smart-log", "-o", "json", dev)
+	if err != nil {
+		return nvme, fmt.Errorf("run %s smart-log %s failed: %w", nvmeCLI, dev, err)
+	}
+	if err := parseSmartLog(out, &nvme); err != nil {
+		return nvme, err
+	}
+
+	return nvme, nil
+}
+
+// parseIDCtrl 解析 nvme id-ctrl -o json 输出中的型号、序列号和固件版本
+func parseIDCtrl(out []byte, nvme *model.NVMe) error {
+	fields, err := decodeJSONObject(out)
+	if err != nil {
+		return fmt.Errorf("parse %s id-ctrl output failed: %w", nvmeCLI, err)
+	}
+
+	nvme.Model = jsonString(fields, "mn")
+	nvme.Serial = jsonString(fields, "sn")
+	nvme.Firmware = jsonString(fields, "fr")
+	return nil
+}
+
+// parseSmartLog 解析 nvme smart-log -o json 输出，兼容不同版本nvme-cli的字段名
+func parseSmartLog(out []byte, nvme *model.NVMe) error {
+	fields, err := decodeJSONObject(out)
+	if err != nil {
+		return fmt.Errorf("parse %s smart-log output failed: %w", nvmeCLI, err)
+	}
+
+	nvme.PercentUsed = jsonString(fields, "percent_used", "percentage_used")
+	nvme.AvailableSpare = jsonString(fields, "avail_spare")
+	nvme.SpareThreshold = jsonString(fields, "spare_thresh")
+	nvme.MediaErrors = jsonString(fields, "media_errors")
+	nvme.Temperature = jsonString(fields, "temperature")
+
+	// nvme-cli 2.x 将 critical_warning 输出为包含 value 的对象
+	warning := fields["critical_warning"]
+	if obj, ok := warning.(map[string]any); ok {
+		warning = obj["value"]
+	}
+	if warning != nil {
+		nvme.CriticalWarning = fmt.Sprint(warning)
+		nvme.CriticalWarnings = decodeCriticalWarning(nvme.CriticalWarning)
+	}
+
+	return nil
+}
+
+// decodeCriticalWarning 将 critical_warning 位图解码为告警名称列表
+func decodeCriticalWarning(v string) []string {
+	bits, err := strconv.ParseUint(v, 0, 8)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	for i, name := range criticalWarningBits {
+		if bits&(1<<i) != 0 {
+			warnings = append(warnings, name)
+		}
+	}
+	return warnings
+}
+
+func decodeJSONObject(out []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// jsonString 按顺序查找第一个存在的字段，返回去除首尾空白后的字符串值
+func jsonString(fields map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := fields[key]; ok && v != nil {
+			return strings.TrimSpace(fmt.Sprint(v))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// collectQueue 读取块设备 queue 目录下的I/O调度器和队列参数，
+// 以及 mq 目录下的硬件队列数(blk-mq)，没有 queue 目录的设备返回空值
+func collectQueue(dir string) model.BlockQueue {
+	queueDir := filepath.Join(dir, "queue")
+	read := func(attr string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(queueDir, attr))
+		return v
+	}
+
+	var queue model.BlockQueue
+	queue.Scheduler, queue.AvailableSchedulers = parseScheduler(read("scheduler"))
+	queue.NrRequests = read("nr_requests")
+	queue.ReadAheadKB = read("read_ahead_kb")
+	queue.Rotational = read("rotational")
+
+	if entries, err := os.ReadDir(filepath.Join(dir, "mq")); err == nil && len(entries) > 0 {
+		queue.NrHWQueues = strconv.Itoa(len(entries))
+	}
+
+	return queue
+}
+
+// parseScheduler 解析 queue/scheduler，方括号中为当前使用的调度器：
+//
+//	mq-deadline kyber [bfq] none
+//
+// 不支持调度器的设备内容为 "none"，此时当前调度器即为 none
+func parseScheduler(content string) (active string, available []string) {
+	for _, field := range strings.Fields(content) {
+		if name, ok := strings.CutPrefix(field, "["); ok {
+			field = strings.TrimSuffix(name, "]")
+			active = field
+		}
+		available = append(available, field)
+	}
+
+	if active == "" && len(available) == 1 {
+		active = available[0]
+	}
+	return active, available
+}
@@ -0,0 +1,71 @@
+package disk
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestParseScheduler(t *testing.T) {
+	tests := []struct {
+		content       string
+		wantActive    string
+		wantAvailable []string
+	}{
+		{"mq-deadline kyber [bfq] none", "bfq", []string{"mq-deadline", "kyber", "bfq", "none"}},
+		{"[none] mq-deadline", "none", []string{"none", "mq-deadline"}},
+		{"none", "none", []string{"none"}},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		active, available := parseScheduler(tt.content)
+		if active != tt.wantActive || !slices.Equal(available, tt.wantAvailable) {
+			t.Errorf("parseScheduler(%q) = %q, %q, want %q, %q", tt.content, active, available, tt.wantActive, tt.wantAvailable)
+		}
+	}
+}
+
+func TestCollectQueue(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		// NVMe SSD:blk-mq,每个CPU一个硬件队列
+		"/sys/block/nvme0n1/queue/scheduler":     "[none] mq-deadline\n",
+		"/sys/block/nvme0n1/queue/nr_requests":   "1023\n",
+		"/sys/block/nvme0n1/queue/read_ahead_kb": "128\n",
+		"/sys/block/nvme0n1/queue/rotational":    "0\n",
+		"/sys/block/nvme0n1/mq/0/cpu_list":       "0\n",
+		"/sys/block/nvme0n1/mq/1/cpu_list":       "1\n",
+		"/sys/block/nvme0n1/mq/2/cpu_list":       "2\n",
+		// 机械盘
+		"/sys/block/sda/queue/scheduler":     "mq-deadline kyber [bfq] none\n",
+		"/sys/block/sda/queue/nr_requests":   "64\n",
+		"/sys/block/sda/queue/read_ahead_kb": "4096\n",
+		"/sys/block/sda/queue/rotational":    "1\n",
+		"/sys/block/sda/mq/0/cpu_list":       "0-3\n",
+		// 没有 queue 目录的设备
+		"/sys/block/md0/size": "1\n",
+	})
+
+	tests := []struct {
+		name string
+		want model.BlockQueue
+	}{
+		{"nvme0n1", model.BlockQueue{
+			Scheduler: "none", AvailableSchedulers: []string{"none", "mq-deadline"},
+			NrRequests: "1023", ReadAheadKB: "128", Rotational: "0", NrHWQueues: "3",
+		}},
+		{"sda", model.BlockQueue{
+			Scheduler: "bfq", AvailableSchedulers: []string{"mq-deadline", "kyber", "bfq", "none"},
+			NrRequests: "64", ReadAheadKB: "4096", Rotational: "1", NrHWQueues: "1",
+		}},
+		{"md0", model.BlockQueue{}},
+	}
+	for _, tt := range tests {
+		if got := collectQueue(filepath.Join(root, "sys/block", tt.name)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("collectQueue(%s) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Package ethtool 解析 ethtool 各子命令的输出，供网络相关采集复用
+package ethtool
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// 不支持或未提供的值，如 ring 参数中的 RX Mini: n/a
+const notAvailable string = "n/a"
+
+// Settings 表示 ethtool <eth> 输出的链路设置
+type Settings struct {
+	Speed          string   // 协商速率，如 1000Mb/s
+	Duplex         string   // 双工模式
+	Port           string   // 端口类型
+	LinkDetected   string   // 链路检测
+	SupportedModes []string // 网卡支持的链路模式，如 10000baseT/Full
+}
+
+// DriverInfo 表示 ethtool -i <eth> 输出的驱动信息
+type DriverInfo struct {
+	Driver          string // 驱动名称
+	Version         string // 驱动版本
+	FirmwareVersion string // 固件版本
+	BusInfo         string // 总线地址，PCI网卡为PCI地址
+}
+
+// RingBuffer 表示 ethtool -g <eth> 输出的一组环形缓冲区大小
+type RingBuffer struct {
+	RX      string
+	RXMini  string
+	RXJumbo string
+	TX      string
+}
+
+// Channel 表示 ethtool -l <eth> 输出的一组队列数
+type Channel struct {
+	RX       string
+	TX       string
+	Other    string
+	Combined string
+}
+
+// Feature 表示 ethtool -k <eth> 输出中一个特性(卸载功能)的状态
+type Feature struct {
+	Enabled bool // 是否开启
+	Fixed   bool // 是否固定，不能通过 ethtool -K 修改
+}
+
+// 带上下限的输出(-g、-l)中的两个段落标题
+const (
+	sectionMax     string = "Pre-set maximums"
+	sectionCurrent string = "Current hardware settings"
+)
+
+// ParseSettings 解析 ethtool <eth> 输出，链路模式可能跨多行：
+//
+//	Settings for eth0:
+//		Supported link modes:   1000baseT/Full
+//		                        10000baseT/Full
+//		Speed: 1000Mb/s
+//		Duplex: Full
+func ParseSettings(out []byte) Settings {
+	var (
+		settings Settings
+		key      string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, ":")
+		if ok {
+			key = strings.TrimSpace(k)
+			v = strings.TrimSpace(v)
+		} else {
+			// 没有冒号的行是上一个键的续行
+			v = line
+		}
+
+		switch key {
+		case "Speed":
+			settings.Speed = v
+		case "Duplex":
+			settings.Duplex = v
+		case "Port":
+			settings.Port = v
+		case "Link detected":
+			settings.LinkDetected = v
+		case "Supported link modes":
+			if v != "Not reported" {
+				settings.SupportedModes = append(settings.SupportedModes, strings.Fields(v)...)
+			}
+		}
+	}
+
+	return settings
+}
+
+// ParseDriverInfo 解析 ethtool -i <eth> 输出：
+//
+//	driver: ixgbe
+//	version: 5.1.0-k
+//	firmware-version: 0x800007b8
+//	bus-info: 0000:3b:00.0
+func ParseDriverInfo(out []byte) DriverInfo {
+	var info DriverInfo
+
+	for key, value := range parseKeyValues(out) {
+		switch key {
+		case "driver":
+			info.Driver = value
+		case "version":
+			info.Version = value
+		case "firmware-version":
+			info.FirmwareVersion = value
+		case "bus-info":
+			info.BusInfo = value
+		}
+	}
+
+	return info
+}
+
+// ParseRing 解析 ethtool -g <eth> 输出，分别返回当前值和最大值：
+//
+//	Ring parameters for eth0:
+//	Pre-set maximums:
+//	RX:		4096
+//	RX Mini:	n/a
+//	TX:		4096
+//	Current hardware settings:
+//	RX:		512
+//	RX Mini:	n/a
+//	TX:		512
+func ParseRing(out []byte) (current, max RingBuffer) {
+	sections := parseSections(out)
+	return ringFrom(sections[sectionCurrent]), ringFrom(sections[sectionMax])
+}
+
+// ParseChannels 解析 ethtool -l <eth> 输出，分别返回当前值和最大值：
+//
+//	Channel parameters for eth0:
+//	Pre-set maximums:
+//	RX:		n/a
+//	TX:		n/a
+//	Other:		1
+//	Combined:	63
+//	Current hardware settings:
+//	RX:		n/a
+//	TX:		n/a
+//	Other:		1
+//	Combined:	8
+func ParseChannels(out []byte) (current, max Channel) {
+	sections := parseSections(out)
+	return channelFrom(sections[sectionCurrent]), channelFrom(sections[sectionMax])
+}
+
+// ParseFeatures 解析 ethtool -k <eth> 输出，返回特性名到状态的映射，子特性缩进显示，与顶层特性同样处理：
+//
+//	Features for eth0:
+//	rx-checksumming: on
+//	tx-checksumming: on
+//		tx-checksum-ipv4: off [fixed]
+//	generic-receive-offload: on
+//	large-receive-offload: off [fixed]
+//	rx-vlan-filter: off [requested on]
+func ParseFeatures(out []byte) map[string]Feature {
+	features := make(map[string]Feature)
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) == 0 || (fields[0] != "on" && fields[0] != "off") {
+			continue
+		}
+
+		features[strings.TrimSpace(key)] = Feature{
+			Enabled: fields[0] == "on",
+			Fixed:   strings.Contains(value, "[fixed]"),
+		}
+	}
+
+	return features
+}
+
+// ParseStatistics 解析 ethtool -S <eth> 输出的驱动计数器，计数器名称和含义由驱动决定：
+//
+//	NIC statistics:
+//	     rx_packets: 1385769
+//	     rx_missed_errors: 0
+//	     rx_queue_0_drops: 12
+//
+// 部分驱动(如 bnxt_en)按队列输出 "[0]: rx_ucast_packets: 12"，记为 rx_ucast_packets[0]。
+// 标题行和非数值的行被忽略
+func ParseStatistics(out []byte) map[string]uint64 {
+	stats := make(map[string]uint64)
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.LastIndex(line, ":")
+		if i < 0 {
+			continue
+		}
+
+		v, err := strconv.ParseUint(strings.TrimSpace(line[i+1:]), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		key := strings.TrimSpace(line[:i])
+		if queue, name, ok := strings.Cut(key, ":"); ok && strings.HasPrefix(queue, "[") {
+			key = strings.TrimSpace(name) + queue
+		}
+		if key != "" {
+			stats[key] = v
+		}
+	}
+
+	return stats
+}
+
+func ringFrom(kv map[string]string) RingBuffer {
+	return RingBuffer{
+		RX:      kv["RX"],
+		RXMini:  kv["RX Mini"],
+		RXJumbo: kv["RX Jumbo"],
+		TX:      kv["TX"],
+	}
+}
+
+func channelFrom(kv map[string]string) Channel {
+	return Channel{
+		RX:       kv["RX"],
+		TX:       kv["TX"],
+		Other:    kv["Other"],
+		Combined: kv["Combined"],
+	}
+}
+
+// parseSections 按段落解析形如 "Pre-set maximums:" 后接若干 "键: 值" 行的输出，
+// 段落标题为冒号后没有值的行；n/a 的值视为未提供，不写入结果
+func parseSections(out []byte) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+
+	var cur map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if value == "" {
+			cur = make(map[string]string)
+			sections[key] = cur
+			continue
+		}
+		if cur == nil || value == notAvailable {
+			continue
+		}
+		cur[key] = value
+	}
+
+	return sections
+}
+
+// parseKeyValues 解析每行一个 "键: 值" 的输出，忽略没有值的行
+func parseKeyValues(out []byte) map[string]string {
+	kv := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			kv[strings.TrimSpace(key)] = value
+		}
+	}
+
+	return kv
+}
@@ -0,0 +1,361 @@
+package ethtool
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseDriverInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want DriverInfo
+	}{
+		{
+			name: "ixgbe",
+			out: `driver: ixgbe
+version: 5.1.0-k
+firmware-version: 0x800007b8
+expansion-rom-version: 
+bus-info: 0000:3b:00.0
+supports-statistics: yes
+supports-test: yes
+`,
+			want: DriverInfo{Driver: "ixgbe", Version: "5.1.0-k", FirmwareVersion: "0x800007b8", BusInfo: "0000:3b:00.0"},
+		},
+		{
+			// 虚拟网卡没有固件和总线地址
+			name: "veth",
+			out: `driver: veth
+version: 1.0
+firmware-version: 
+expansion-rom-version: 
+bus-info: 
+supports-statistics: yes
+`,
+			want: DriverInfo{Driver: "veth", Version: "1.0"},
+		},
+		{
+			name: "not supported",
+			out:  "Cannot get driver information: Operation not supported\n",
+			want: DriverInfo{},
+		},
+		{name: "empty", out: "", want: DriverInfo{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseDriverInfo([]byte(tt.out)); got != tt.want {
+				t.Errorf("ParseDriverInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRing(t *testing.T) {
+	tests := []struct {
+		name         string
+		out          string
+		current, max RingBuffer
+	}{
+		{
+			name: "ixgbe",
+			out: `Ring parameters for eth0:
+Pre-set maximums:
+RX:		4096
+RX Mini:	n/a
+RX Jumbo:	n/a
+TX:		4096
+Current hardware settings:
+RX:		512
+RX Mini:	n/a
+RX Jumbo:	n/a
+TX:		512
+`,
+			current: RingBuffer{RX: "512", TX: "512"},
+			max:     RingBuffer{RX: "4096", TX: "4096"},
+		},
+		{
+			// 较新的 ethtool 在段落后追加了不属于 ring 的参数
+			name: "mlx5 with extra fields",
+			out: `Ring parameters for ens1f0np0:
+Pre-set maximums:
+RX:			8192
+RX Mini:		n/a
+RX Jumbo:		n/a
+TX:			8192
+Current hardware settings:
+RX:			1024
+RX Mini:		n/a
+RX Jumbo:		n/a
+TX:			1024
+RX Buf Len:		n/a
+CQE Size:		n/a
+TX Push:		off
+`,
+			current: RingBuffer{RX: "1024", TX: "1024"},
+			max:     RingBuffer{RX: "8192", TX: "8192"},
+		},
+		{
+			name: "not supported",
+			out: `Ring parameters for veth0:
+Cannot get device ring settings: Operation not supported
+`,
+		},
+		{name: "empty", out: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, max := ParseRing([]byte(tt.out))
+			if current != tt.current || max != tt.max {
+				t.Errorf("ParseRing() = %+v, %+v, want %+v, %+v", current, max, tt.current, tt.max)
+			}
+		})
+	}
+}
+
+func TestParseChannels(t *testing.T) {
+	tests := []struct {
+		name         string
+		out          string
+		current, max Channel
+	}{
+		{
+			name: "combined queues",
+			out: `Channel parameters for eth0:
+Pre-set maximums:
+RX:		n/a
+TX:		n/a
+Other:		1
+Combined:	63
+Current hardware settings:
+RX:		n/a
+TX:		n/a
+Other:		1
+Combined:	8
+`,
+			current: Channel{Other: "1", Combined: "8"},
+			max:     Channel{Other: "1", Combined: "63"},
+		},
+		{
+			// 部分驱动用 0 表示不适用
+			name: "separate rx and tx",
+			out: `Channel parameters for eno1:
+Pre-set maximums:
+RX:		16
+TX:		16
+Other:		0
+Combined:	0
+Current hardware settings:
+RX:		4
+TX:		4
+Other:		0
+Combined:	0
+`,
+			current: Channel{RX: "4", TX: "4", Other: "0", Combined: "0"},
+			max:     Channel{RX: "16", TX: "16", Other: "0", Combined: "0"},
+		},
+		{
+			name: "not supported",
+			out: `Channel parameters for lo:
+Cannot get device channel parameters
+: Operation not supported
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, max := ParseChannels([]byte(tt.out))
+			if current != tt.current || max != tt.max {
+				t.Errorf("ParseChannels() = %+v, %+v, want %+v, %+v", current, max, tt.current, tt.max)
+			}
+		})
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want Settings
+	}{
+		{
+			name: "multi-line link modes",
+			out: `Settings for eth0:
+	Supported ports: [ FIBRE ]
+	Supported link modes:   1000baseT/Full 
+	                        10000baseT/Full 
+	Supported pause frame use: Symmetric
+	Supports auto-negotiation: Yes
+	Speed: 10000Mb/s
+	Duplex: Full
+	Port: FIBRE
+	Link detected: yes
+`,
+			want: Settings{
+				Speed: "10000Mb/s", Duplex: "Full", Port: "FIBRE", LinkDetected: "yes",
+				SupportedModes: []string{"1000baseT/Full", "10000baseT/Full"},
+			},
+		},
+		{
+			name: "virtual device",
+			out: `Settings for veth0:
+	Supported ports: [ ]
+	Supported link modes:   Not reported
+	Speed: 10000Mb/s
+	Duplex: Full
+	Port: Twisted Pair
+	Link detected: yes
+`,
+			want: Settings{Speed: "10000Mb/s", Duplex: "Full", Port: "Twisted Pair", LinkDetected: "yes"},
+		},
+		{
+			name: "link down",
+			out: `Settings for eno2:
+	Supported link modes:   1000baseT/Full
+	Speed: Unknown!
+	Duplex: Unknown! (255)
+	Port: Twisted Pair
+	Link detected: no
+`,
+			want: Settings{
+				Speed: "Unknown!", Duplex: "Unknown! (255)", Port: "Twisted Pair", LinkDetected: "no",
+				SupportedModes: []string{"1000baseT/Full"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSettings([]byte(tt.out))
+			if got.Speed != tt.want.Speed || got.Duplex != tt.want.Duplex || got.Port != tt.want.Port ||
+				got.LinkDetected != tt.want.LinkDetected || !slices.Equal(got.SupportedModes, tt.want.SupportedModes) {
+				t.Errorf("ParseSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFeatures(t *testing.T) {
+	out := `Features for eth0:
+rx-checksumming: on
+tx-checksumming: on
+	tx-checksum-ipv4: off [fixed]
+	tx-checksum-ip-generic: on
+scatter-gather: on
+tcp-segmentation-offload: on
+	tx-tcp-segmentation: on
+	tx-tcp-ecn-segmentation: off [fixed]
+generic-segmentation-offload: on
+generic-receive-offload: on
+large-receive-offload: off [fixed]
+rx-vlan-filter: off [requested on]
+hsr-tag-ins-offload: off [fixed]
+`
+	got := ParseFeatures([]byte(out))
+	want := map[string]Feature{
+		"rx-checksumming":              {Enabled: true},
+		"tx-checksumming":              {Enabled: true},
+		"tx-checksum-ipv4":             {Fixed: true},
+		"tx-checksum-ip-generic":       {Enabled: true},
+		"scatter-gather":               {Enabled: true},
+		"tcp-segmentation-offload":     {Enabled: true},
+		"tx-tcp-segmentation":          {Enabled: true},
+		"tx-tcp-ecn-segmentation":      {Fixed: true},
+		"generic-segmentation-offload": {Enabled: true},
+		"generic-receive-offload":      {Enabled: true},
+		"large-receive-offload":        {Fixed: true},
+		"rx-vlan-filter":               {},
+		"hsr-tag-ins-offload":          {Fixed: true},
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("ParseFeatures() = %v, want %v", got, want)
+	}
+
+	if got := ParseFeatures([]byte("Cannot get device feature names: Operation not supported\n")); len(got) != 0 {
+		t.Errorf("ParseFeatures(not supported) = %v, want empty", got)
+	}
+}
+
+func TestParseStatistics(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[string]uint64
+	}{
+		{
+			name: "ixgbe",
+			out: `NIC statistics:
+     rx_packets: 1385769
+     tx_packets: 1021338
+     rx_missed_errors: 0
+     tx_heartbeat_errors: 0
+     rx_crc_errors: 3
+     tx_queue_0_packets: 512034
+     rx_queue_0_drops: 12
+     os2bmc_rx_by_bmc: 18446744073709551615
+`,
+			want: map[string]uint64{
+				"rx_packets":          1385769,
+				"tx_packets":          1021338,
+				"rx_missed_errors":    0,
+				"tx_heartbeat_errors": 0,
+				"rx_crc_errors":       3,
+				"tx_queue_0_packets":  512034,
+				"rx_queue_0_drops":    12,
+				"os2bmc_rx_by_bmc":    18446744073709551615,
+			},
+		},
+		{
+			name: "mlx5",
+			out: `NIC statistics:
+     rx_packets: 9876543210
+     rx_bytes: 123456789012
+     rx_out_of_buffer: 42
+     rx_discards_phy: 7
+     tx_pause_ctrl_phy: 0
+     rx_prio0_bytes: 123456789012
+     ch0_events: 1893
+     rx0_cache_reuse: 0
+     link_down_events_phy: 2
+`,
+			want: map[string]uint64{
+				"rx_packets":           9876543210,
+				"rx_bytes":             123456789012,
+				"rx_out_of_buffer":     42,
+				"rx_discards_phy":      7,
+				"tx_pause_ctrl_phy":    0,
+				"rx_prio0_bytes":       123456789012,
+				"ch0_events":           1893,
+				"rx0_cache_reuse":      0,
+				"link_down_events_phy": 2,
+			},
+		},
+		{
+			// bnxt_en 按队列输出
+			name: "bnxt_en",
+			out: `NIC statistics:
+     [0]: rx_ucast_packets: 12
+     [1]: rx_ucast_packets: 34
+     rx_total_discard_pkts: 5
+`,
+			want: map[string]uint64{"rx_ucast_packets[0]": 12, "rx_ucast_packets[1]": 34, "rx_total_discard_pkts": 5},
+		},
+		{
+			name: "non-numeric",
+			out:  "NIC statistics:\n     rx_packets: 10\n     link_state: up\n     tx_timeout: -1\n",
+			want: map[string]uint64{"rx_packets": 10},
+		},
+		{
+			name: "not supported",
+			out:  "no stats available\n",
+			want: map[string]uint64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseStatistics([]byte(tt.out)); !maps.Equal(got, tt.want) {
+				t.Errorf("ParseStatistics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
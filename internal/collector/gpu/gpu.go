@@ -0,0 +1,109 @@
+package gpu
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/collector/pci"
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const nvidiaSMI string = "nvidia-smi"
+
+// Collect 通过nvidia-smi采集GPU信息和使用各GPU的计算进程，并补充PCIe链路和NUMA亲和性，
+// minimal 采集配置下跳过PCIe链路和NVLink。计算进程采集失败只记录警告
+func Collect(ctx context.Context) ([]model.GPU, error) {
+	out, err := executor.ExecuteWithContext(ctx, nvidiaSMI,
+		"--query-gpu=index,name,uuid,pci.bus_id", "--format=csv,noheader")
+	if err != nil {
+		return nil, fmt.Errorf("run %s failed: %w", nvidiaSMI, err)
+	}
+
+	gpus, err := parseQueryGPU(out)
+	if err != nil {
+		return nil, err
+	}
+
+	processes, err := collectProcesses(ctx)
+	if err != nil {
+		utils.WarningsFrom(ctx).Add("gpu", err)
+	}
+
+	kept := gpus[:0]
+	for _, g := range gpus {
+		if utils.DeviceExcluded(g.Index, g.UUID, pci.NormalizeBusID(g.BusID)) {
+			continue
+		}
+		if !utils.IsMinimal(ctx) {
+			fillPCI(&g)
+			g.NVLink = hasActiveNVLink(ctx, g.Index)
+		}
+		g.Processes = processes[g.UUID]
+		kept = append(kept, g)
+	}
+
+	return kept, nil
+}
+
+// parseQueryGPU 解析 nvidia-smi --query-gpu 的csv输出
+func parseQueryGPU(out []byte) ([]model.GPU, error) {
+	r := csv.NewReader(strings.NewReader(string(out)))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s output failed: %w", nvidiaSMI, err)
+	}
+
+	gpus := make([]model.GPU, 0, len(records))
+	for _, rec := range records {
+		if len(rec) < 4 {
+			continue
+		}
+		gpus = append(gpus, model.GPU{
+			Index: strings.TrimSpace(rec[0]),
+			Name:  strings.TrimSpace(rec[1]),
+			UUID:  strings.TrimSpace(rec[2]),
+			BusID: strings.TrimSpace(rec[3]),
+		})
+	}
+
+	return gpus, nil
+}
+
+// fillPCI 根据总线地址关联sysfs中的PCI设备，读取NUMA节点、链路信息和AER错误计数
+func fillPCI(g *model.GPU) {
+	if g.BusID == "" {
+		return
+	}
+
+	addr := pci.NormalizeBusID(g.BusID)
+	g.PCI.PCIAddr = addr
+	g.PCI.Numa = pci.ReadNuma(addr)
+	g.PCI.Link = pci.ReadLink(addr)
+	g.LinkDegraded = pci.LinkDegraded(g.PCI.Link)
+	g.PCI.AER = pci.ReadAER(addr)
+	g.PCI.Degraded = pci.AERDegraded(g.PCI.AER)
+}
+
+// hasActiveNVLink 通过 nvidia-smi nvlink -s 判断GPU是否存在活动的NVLink链路，
+// 不支持NVLink的GPU或驱动会报错或输出 inactive
+func hasActiveNVLink(ctx context.Context, index string) bool {
+	out, err := executor.ExecuteWithContext(ctx, nvidiaSMI, "nvlink", "-s", "-i", index)
+	if err != nil {
+		return false
+	}
+
+	for line := range strings.Lines(string(out)) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Link ") && !strings.Contains(line, "inactive") {
+			return true
+		}
+	}
+	return false
+}
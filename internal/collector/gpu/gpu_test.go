@@ -0,0 +1,84 @@
+package gpu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const queryGPU = "nvidia-smi --query-gpu=index,name,uuid,pci.bus_id --format=csv,noheader"
+
+func TestParseQueryGPU(t *testing.T) {
+	out := "0, NVIDIA A100-SXM4-80GB, GPU-aaaa, 00000000:3B:00.0\n1, NVIDIA A100-SXM4-80GB, GPU-bbbb, 00000000:AF:00.0\nbroken line\n"
+
+	gpus, err := parseQueryGPU([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("got %d GPUs, want 2", len(gpus))
+	}
+	if g := gpus[1]; g.Index != "1" || g.Name != "NVIDIA A100-SXM4-80GB" || g.UUID != "GPU-bbbb" || g.BusID != "00000000:AF:00.0" {
+		t.Errorf("gpus[1] = %+v", g)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	dev := "/sys/bus/pci/devices/0000:3b:00.0/"
+	testutil.FakeRoot(t, map[string]string{
+		dev + "numa_node":          "0\n",
+		dev + "max_link_speed":     "16.0 GT/s PCIe\n",
+		dev + "max_link_width":     "16\n",
+		dev + "current_link_speed": "16.0 GT/s PCIe\n",
+		dev + "current_link_width": "8\n",
+	})
+	testutil.FakeCommands(t, map[string]string{
+		queryGPU:                    "0, NVIDIA H100, GPU-aaaa, 00000000:3B:00.0\n",
+		"nvidia-smi nvlink -s -i 0": "GPU 0: NVIDIA H100\n\t Link 0: 26.562 GB/s\n\t Link 1: <inactive>\n",
+		"nvidia-smi --query-compute-apps=gpu_uuid,pid,used_memory --format=csv,noheader,nounits": "",
+	})
+
+	gpus, err := Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 1 {
+		t.Fatalf("got %d GPUs, want 1", len(gpus))
+	}
+	g := gpus[0]
+	if g.PCI.PCIAddr != "0000:3b:00.0" || g.PCI.Numa != "0" {
+		t.Errorf("PCI = %+v, want address 0000:3b:00.0 on NUMA node 0", g.PCI)
+	}
+	if !g.LinkDegraded {
+		t.Error("x8 link on an x16 device is not reported as degraded")
+	}
+	if !g.NVLink {
+		t.Error("active NVLink not detected")
+	}
+}
+
+func TestCollectMinimalSkipsPCIAndNVLink(t *testing.T) {
+	testutil.FakeCommands(t, map[string]string{
+		queryGPU: "0, NVIDIA H100, GPU-aaaa, 00000000:3B:00.0\n",
+		"nvidia-smi --query-compute-apps=gpu_uuid,pid,used_memory --format=csv,noheader,nounits": "",
+	})
+
+	ctx := utils.WithProfile(context.Background(), utils.ProfileMinimal)
+	gpus, err := Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 1 || gpus[0].PCI.PCIAddr != "" || gpus[0].NVLink {
+		t.Errorf("minimal profile collected PCI or NVLink details: %+v", gpus)
+	}
+}
+
+func TestCollectWithoutNvidiaSMI(t *testing.T) {
+	testutil.FakeCommands(t, nil)
+
+	if _, err := Collect(context.Background()); err == nil {
+		t.Fatal("Collect() without nvidia-smi succeeded")
+	}
+}
@@ -0,0 +1,72 @@
+package gpu
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// collectProcesses 通过 nvidia-smi --query-compute-apps 采集使用GPU的计算进程，按GPU UUID分组，
+// 并从 /proc/<pid>/comm 读取进程名。nvidia-smi 报告的是宿主机PID，在独立PID命名空间的容器中进程名可能为空
+func collectProcesses(ctx context.Context) (map[string][]model.GPUProcess, error) {
+	out, err := executor.ExecuteWithContext(ctx, nvidiaSMI,
+		"--query-compute-apps=gpu_uuid,pid,used_memory", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, fmt.Errorf("run %s --query-compute-apps failed: %w", nvidiaSMI, err)
+	}
+
+	processes, err := parseComputeApps(out)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, procs := range processes {
+		for i := range procs {
+			procs[i].Command, _ = utils.ReadSysfsFile(utils.HostPath(filepath.Join("/proc", procs[i].PID, "comm")))
+		}
+	}
+	return processes, nil
+}
+
+// parseComputeApps 解析 nvidia-smi --query-compute-apps=gpu_uuid,pid,used_memory --format=csv,noheader,nounits 输出：
+//
+//	GPU-5a8f2c1e-0d3b-4c6a-9e7f-1b2c3d4e5f60, 12345, 10240
+//	GPU-5a8f2c1e-0d3b-4c6a-9e7f-1b2c3d4e5f60, 12346, [N/A]
+//
+// 没有计算进程时输出为空(旧版本驱动输出 "No running processes found")，返回空映射。
+// 权限不足或 MIG 模式下显存可能为 [N/A]，此时显存字段为空
+func parseComputeApps(out []byte) (map[string][]model.GPUProcess, error) {
+	r := csv.NewReader(strings.NewReader(string(out)))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s --query-compute-apps output failed: %w", nvidiaSMI, err)
+	}
+
+	processes := make(map[string][]model.GPUProcess)
+	for _, rec := range records {
+		if len(rec) < 3 {
+			continue
+		}
+
+		uuid, pid := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		if uuid == "" || pid == "" {
+			continue
+		}
+
+		used := strings.TrimSpace(rec[2])
+		if strings.HasPrefix(used, "[") {
+			used = ""
+		}
+		processes[uuid] = append(processes[uuid], model.GPUProcess{PID: pid, UsedMemory: used})
+	}
+	return processes, nil
+}
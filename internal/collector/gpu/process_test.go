@@ -0,0 +1,103 @@
+package gpu
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const queryComputeApps = "nvidia-smi --query-compute-apps=gpu_uuid,pid,used_memory --format=csv,noheader,nounits"
+
+func TestParseComputeApps(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[string][]model.GPUProcess
+	}{
+		{
+			name: "processes",
+			out: "GPU-aaaa, 12345, 10240\n" +
+				"GPU-aaaa, 12346, [N/A]\n" +
+				"GPU-bbbb, 23456, 512\n",
+			want: map[string][]model.GPUProcess{
+				"GPU-aaaa": {{PID: "12345", UsedMemory: "10240"}, {PID: "12346"}},
+				"GPU-bbbb": {{PID: "23456", UsedMemory: "512"}},
+			},
+		},
+		{name: "no processes", out: "", want: map[string][]model.GPUProcess{}},
+		{name: "old driver", out: "No running processes found\n", want: map[string][]model.GPUProcess{}},
+		{
+			name: "incomplete lines",
+			out:  "GPU-aaaa, 12345\n, 12346, 100\nGPU-aaaa, , 100\nGPU-aaaa, 12347, 64\n",
+			want: map[string][]model.GPUProcess{"GPU-aaaa": {{PID: "12347", UsedMemory: "64"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseComputeApps([]byte(tt.out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseComputeApps() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseComputeApps([]byte("GPU-aaaa, \"12345, 100\n")); err == nil {
+		t.Error("parseComputeApps() accepted malformed CSV")
+	}
+}
+
+func TestCollectProcesses(t *testing.T) {
+	// 12346 在独立PID命名空间中,读不到进程名
+	testutil.FakeRoot(t, map[string]string{
+		"/proc/12345/comm": "python3\n",
+		"/proc/23456/comm": "triton_server\n",
+	})
+	testutil.FakeCommands(t, map[string]string{
+		queryGPU:         "0, NVIDIA H100, GPU-aaaa, 00000000:3B:00.0\n1, NVIDIA H100, GPU-bbbb, 00000000:AF:00.0\n2, NVIDIA H100, GPU-cccc, 00000000:D8:00.0\n",
+		queryComputeApps: "GPU-aaaa, 12345, 10240\nGPU-aaaa, 12346, 2048\nGPU-bbbb, 23456, 512\n",
+	})
+
+	gpus, err := Collect(utils.WithProfile(context.Background(), utils.ProfileMinimal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 3 {
+		t.Fatalf("got %d GPUs, want 3", len(gpus))
+	}
+	want := [][]model.GPUProcess{
+		{{PID: "12345", Command: "python3", UsedMemory: "10240"}, {PID: "12346", UsedMemory: "2048"}},
+		{{PID: "23456", Command: "triton_server", UsedMemory: "512"}},
+		nil,
+	}
+	for i, g := range gpus {
+		if !reflect.DeepEqual(g.Processes, want[i]) {
+			t.Errorf("GPU %s processes = %+v, want %+v", g.Index, g.Processes, want[i])
+		}
+	}
+}
+
+func TestCollectProcessesFailureIsWarning(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+	testutil.FakeCommands(t, map[string]string{queryGPU: "0, NVIDIA H100, GPU-aaaa, 00000000:3B:00.0\n"})
+
+	warnings := &utils.Warnings{}
+	gpus, err := Collect(utils.WithProfile(utils.WithWarnings(context.Background(), warnings), utils.ProfileMinimal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 1 || gpus[0].Processes != nil {
+		t.Errorf("gpus = %+v, want one GPU without processes", gpus)
+	}
+	list := warnings.List()
+	if len(list) != 1 || list[0].Module != "gpu" || !strings.Contains(list[0].Message, "query-compute-apps") {
+		t.Errorf("warnings = %v, want the compute-apps failure", list)
+	}
+}
@@ -0,0 +1,108 @@
+package infiniband
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/collector/pci"
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysfsInfiniBand string = "/sys/class/infiniband"
+
+// Collect 采集/sys/class/infiniband下的RDMA设备和端口信息，并关联对应的PCI设备，
+// 没有InfiniBand/RoCE设备时返回空结果
+func Collect() ([]model.InfiniBand, error) {
+	dirs, err := os.ReadDir(utils.HostPath(sysfsInfiniBand))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read directory %s failed: %w", sysfsInfiniBand, err)
+	}
+
+	devices := make([]model.InfiniBand, 0, len(dirs))
+	for _, dir := range dirs {
+		if utils.DeviceExcluded(dir.Name()) {
+			continue
+		}
+		devices = append(devices, collectDevice(dir.Name()))
+	}
+
+	return devices, nil
+}
+
+func collectDevice(name string) model.InfiniBand {
+	dir := filepath.Join(utils.HostPath(sysfsInfiniBand), name)
+	read := func(attr string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(dir, attr))
+		return v
+	}
+
+	device := model.InfiniBand{
+		Name:            name,
+		HCAType:         read("hca_type"),
+		BoardID:         read("board_id"),
+		FirmwareVersion: read("fw_ver"),
+		NodeGUID:        read("node_guid"),
+		Ports:           collectPorts(filepath.Join(dir, "ports")),
+	}
+
+	// device 链接指向PCI设备目录，目录名即PCI地址；软件RDMA设备(rxe、siw)没有PCI设备
+	if target, err := filepath.EvalSymlinks(filepath.Join(dir, "device")); err == nil && isPCIDevice(target) {
+		addr := filepath.Base(target)
+		device.PCI.PCIAddr = addr
+		device.PCI.Numa = pci.ReadNuma(addr)
+		device.PCI.Link = pci.ReadLink(addr)
+		device.PCI.AER = pci.ReadAER(addr)
+		device.PCI.Degraded = pci.AERDegraded(device.PCI.AER)
+	}
+
+	return device
+}
+
+// isPCIDevice 判断sysfs设备目录是否属于PCI总线
+func isPCIDevice(dir string) bool {
+	subsystem, err := filepath.EvalSymlinks(filepath.Join(dir, "subsystem"))
+	return err == nil && filepath.Base(subsystem) == "pci"
+}
+
+func collectPorts(dir string) []model.IBPort {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	ports := make([]model.IBPort, 0, len(entries))
+	for _, entry := range entries {
+		portDir := filepath.Join(dir, entry.Name())
+		read := func(attr string) string {
+			v, _ := utils.ReadSysfsFile(filepath.Join(portDir, attr))
+			return v
+		}
+
+		ports = append(ports, model.IBPort{
+			Port:      entry.Name(),
+			State:     trimStateCode(read("state")),
+			PhysState: trimStateCode(read("phys_state")),
+			Rate:      read("rate"),
+			LinkLayer: read("link_layer"),
+			LID:       read("lid"),
+		})
+	}
+
+	return ports
+}
+
+// trimStateCode 去掉状态前的数字编码，如 "4: ACTIVE" 为 "ACTIVE"，"5: LinkUp" 为 "LinkUp"
+func trimStateCode(state string) string {
+	if _, name, ok := strings.Cut(state, ":"); ok {
+		return strings.TrimSpace(name)
+	}
+	return state
+}
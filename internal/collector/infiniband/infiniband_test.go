@@ -0,0 +1,101 @@
+package infiniband
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestCollectMellanoxCA(t *testing.T) {
+	const (
+		pciDir = "/sys/devices/pci0000:3a/0000:3a:00.0/0000:3b:00.0"
+		ibDir  = "/sys/class/infiniband/mlx5_0"
+	)
+	root := testutil.FakeRoot(t, map[string]string{
+		pciDir + "/numa_node":          "1\n",
+		pciDir + "/max_link_speed":     "16.0 GT/s PCIe\n",
+		pciDir + "/max_link_width":     "16\n",
+		pciDir + "/current_link_speed": "16.0 GT/s PCIe\n",
+		pciDir + "/current_link_width": "16\n",
+
+		ibDir + "/hca_type":  "MT4123\n",
+		ibDir + "/board_id":  "MT_0000000223\n",
+		ibDir + "/fw_ver":    "20.31.1014\n",
+		ibDir + "/node_guid": "b859:9f03:00d4:e6a2\n",
+
+		ibDir + "/ports/1/state":      "4: ACTIVE\n",
+		ibDir + "/ports/1/phys_state": "5: LinkUp\n",
+		ibDir + "/ports/1/rate":       "200 Gb/sec (4X HDR)\n",
+		ibDir + "/ports/1/link_layer": "InfiniBand\n",
+		ibDir + "/ports/1/lid":        "0x12\n",
+
+		ibDir + "/ports/2/state":      "1: DOWN\n",
+		ibDir + "/ports/2/phys_state": "3: Disabled\n",
+		ibDir + "/ports/2/rate":       "10 Gb/sec (4X SDR)\n",
+		ibDir + "/ports/2/link_layer": "Ethernet\n",
+		ibDir + "/ports/2/lid":        "0x0\n",
+
+		// 软件RDMA设备没有PCI设备
+		"/sys/devices/virtual/infiniband/rxe0/.keep": "",
+		"/sys/class/infiniband/rxe0/fw_ver":          "0.0.0\n",
+	})
+	testutil.Symlink(t, root, root+pciDir, "/sys/bus/pci/devices/0000:3b:00.0")
+	testutil.Symlink(t, root, root+"/sys/bus/pci", pciDir+"/subsystem")
+	testutil.Symlink(t, root, root+pciDir, ibDir+"/device")
+	testutil.Symlink(t, root, root+"/sys/devices/virtual/infiniband/rxe0", "/sys/class/infiniband/rxe0/device")
+
+	devices, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("got %d devices, want mlx5_0 and rxe0", len(devices))
+	}
+
+	mlx := devices[0]
+	if mlx.Name != "mlx5_0" || mlx.HCAType != "MT4123" || mlx.BoardID != "MT_0000000223" ||
+		mlx.FirmwareVersion != "20.31.1014" || mlx.NodeGUID != "b859:9f03:00d4:e6a2" {
+		t.Errorf("device = %+v", mlx)
+	}
+	wantPorts := []model.IBPort{
+		{Port: "1", State: "ACTIVE", PhysState: "LinkUp", Rate: "200 Gb/sec (4X HDR)", LinkLayer: "InfiniBand", LID: "0x12"},
+		{Port: "2", State: "DOWN", PhysState: "Disabled", Rate: "10 Gb/sec (4X SDR)", LinkLayer: "Ethernet", LID: "0x0"},
+	}
+	if !reflect.DeepEqual(mlx.Ports, wantPorts) {
+		t.Errorf("ports = %+v, want %+v", mlx.Ports, wantPorts)
+	}
+	if mlx.PCI.PCIAddr != "0000:3b:00.0" || mlx.PCI.Numa != "1" || mlx.PCI.Link.CurrWidth != "16" {
+		t.Errorf("PCI = %+v, want the correlated PCI device", mlx.PCI)
+	}
+
+	rxe := devices[1]
+	if rxe.Name != "rxe0" || rxe.PCI.PCIAddr != "" || rxe.Ports != nil {
+		t.Errorf("soft RDMA device = %+v, want no PCI device", rxe)
+	}
+}
+
+func TestCollectNoInfiniBand(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	devices, err := Collect()
+	if err != nil || devices != nil {
+		t.Errorf("Collect() = %+v, %v, want nil without InfiniBand devices", devices, err)
+	}
+}
+
+func TestTrimStateCode(t *testing.T) {
+	tests := map[string]string{
+		"4: ACTIVE":   "ACTIVE",
+		"5: LinkUp":   "LinkUp",
+		"ACTIVE":      "ACTIVE",
+		"":            "",
+		"1:   DOWN  ": "DOWN",
+	}
+	for in, want := range tests {
+		if got := trimStateCode(in); got != want {
+			t.Errorf("trimStateCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
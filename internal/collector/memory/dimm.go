@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const dmidecode string = "dmidecode"
+
+// collectDIMMs 通过 dmidecode -t 17 采集内存插槽，返回全部插槽(含未插内存条的空槽)，需要root权限
+func collectDIMMs(ctx context.Context) ([]model.DIMM, error) {
+	out, err := executor.ExecuteWithContext(ctx, dmidecode, "-t", "17")
+	if err != nil {
+		return nil, fmt.Errorf("run %s -t 17 failed: %w", dmidecode, err)
+	}
+	return parseDIMMs(string(out)), nil
+}
+
+// parseDIMMs 解析 dmidecode -t 17 输出中的 Memory Device 段落：
+//
+//	Handle 0x0040, DMI type 17, 84 bytes
+//	Memory Device
+//		Size: 32 GB
+//		Locator: CPU0_DIMM_A1
+//		Bank Locator: P0_Node0_Channel0_Dimm0
+//		Type: DDR4
+//		Speed: 3200 MT/s
+//		Configured Memory Speed: 2933 MT/s
+//
+// 空槽的 Size 为 "No Module Installed"，记为空；Unknown 等未提供的值同样记为空
+func parseDIMMs(out string) []model.DIMM {
+	var dimms []model.DIMM
+	for _, node := range utils.ParseIndentedKeyValue(out, ":") {
+		if node.Key != "Memory Device" {
+			continue
+		}
+
+		value := func(keys ...string) string {
+			for _, k := range keys {
+				switch v := node.ChildValue(k); v {
+				case "", "Unknown", "Not Specified", "No Module Installed", "None":
+				default:
+					return v
+				}
+			}
+			return ""
+		}
+
+		dimms = append(dimms, model.DIMM{
+			Locator:         value("Locator"),
+			BankLocator:     value("Bank Locator"),
+			Size:            value("Size"),
+			Type:            value("Type"),
+			Speed:           value("Speed"),
+			ConfiguredSpeed: value("Configured Memory Speed", "Configured Clock Speed"),
+			Manufacturer:    value("Manufacturer"),
+			SerialNumber:    value("Serial Number"),
+			PartNumber:      value("Part Number"),
+			Rank:            value("Rank"),
+		})
+	}
+	return dimms
+}
+
+// channelPattern 从插槽名称中提取内存通道，如 CPU0_DIMM_A1、DIMM_B2 中的 A、B，
+// P0_Node0_Channel0_Dimm0、ChannelA-DIMM0 中的 0、A
+var channelPattern = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)channel[ _-]?([a-z0-9]+)`),
+	regexp.MustCompile(`(?i)dimm[ _-]?([a-z])\d`),
+}
+
+// slotChannel 返回插槽所在的通道(CPU/节点前缀 + 通道号)，无法识别时返回空字符串
+func slotChannel(d model.DIMM) string {
+	for _, locator := range []string{d.BankLocator, d.Locator} {
+		for _, re := range channelPattern {
+			m := re.FindStringSubmatchIndex(locator)
+			if m == nil {
+				continue
+			}
+			// 通道号前的部分(如 CPU0_、P0_Node0_)区分不同CPU上的同名通道
+			return strings.ToUpper(locator[:m[0]] + locator[m[2]:m[3]])
+		}
+	}
+	return ""
+}
+
+// diagnoseDIMMs 检查内存插法和运行速率，返回发现的问题，没有问题时返回空字符串：
+//   - 各内存条容量或额定速率不一致
+//   - 内存运行速率(Configured Memory Speed)低于内存条的额定速率，即降频运行
+//   - 主板有多个通道但只有一个通道插了内存，内存带宽减半
+func diagnoseDIMMs(slots []model.DIMM) string {
+	var (
+		problems  []string
+		sizes     []string
+		speeds    []string
+		allChans  = make(map[string]bool)
+		usedChans = make(map[string]bool)
+	)
+
+	for _, d := range slots {
+		ch := slotChannel(d)
+		if ch != "" {
+			allChans[ch] = true
+		}
+		if d.Size == "" {
+			continue
+		}
+		if ch != "" {
+			usedChans[ch] = true
+		}
+
+		sizes = append(sizes, d.Size)
+		if d.Speed != "" {
+			speeds = append(speeds, d.Speed)
+		}
+
+		rated, configured := speedMTs(d.Speed), speedMTs(d.ConfiguredSpeed)
+		if rated > 0 && configured > 0 && configured < rated {
+			problems = append(problems, fmt.Sprintf("%s 降频运行(%s，额定 %s)", d.Locator, d.ConfiguredSpeed, d.Speed))
+		}
+	}
+
+	if distinct := slices.Compact(slices.Sorted(slices.Values(sizes))); len(distinct) > 1 {
+		problems = append(problems, "内存条容量不一致: "+strings.Join(distinct, ", "))
+	}
+	if distinct := slices.Compact(slices.Sorted(slices.Values(speeds))); len(distinct) > 1 {
+		problems = append(problems, "内存条额定速率不一致: "+strings.Join(distinct, ", "))
+	}
+	if len(allChans) > 1 && len(usedChans) == 1 {
+		problems = append(problems, fmt.Sprintf("只有一个内存通道插了内存条(共 %d 个通道)", len(allChans)))
+	}
+
+	return strings.Join(problems, "; ")
+}
+
+// speedMTs 解析 "3200 MT/s"、"2666 MHz" 形式的速率，无法解析时返回0
+func speedMTs(s string) int {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+	v, _ := strconv.Atoi(fields[0])
+	return v
+}
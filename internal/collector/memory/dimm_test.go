@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// memoryDevice 返回 dmidecode -t 17 中的一个 Memory Device 段落,size 为空表示空槽
+func memoryDevice(locator, bank, size, speed, configured string) string {
+	if size == "" {
+		size, speed, configured = "No Module Installed", "Unknown", "Unknown"
+	}
+	return fmt.Sprintf(`Handle 0x0040, DMI type 17, 84 bytes
+Memory Device
+	Total Width: 72 bits
+	Size: %s
+	Locator: %s
+	Bank Locator: %s
+	Type: DDR4
+	Speed: %s
+	Manufacturer: Samsung
+	Serial Number: 0x1A2B3C4D
+	Part Number: M393A4K40DB3-CWE
+	Rank: 2
+	Configured Memory Speed: %s
+
+`, size, locator, bank, speed, configured)
+}
+
+func TestParseDIMMs(t *testing.T) {
+	out := "# dmidecode 3.3\nGetting SMBIOS data from sysfs.\n\n" +
+		memoryDevice("CPU0_DIMM_A1", "P0_Node0_Channel0_Dimm0", "32 GB", "3200 MT/s", "2933 MT/s") +
+		memoryDevice("CPU0_DIMM_A2", "P0_Node0_Channel0_Dimm1", "", "", "")
+	want := []model.DIMM{
+		{
+			Locator: "CPU0_DIMM_A1", BankLocator: "P0_Node0_Channel0_Dimm0", Size: "32 GB", Type: "DDR4",
+			Speed: "3200 MT/s", ConfiguredSpeed: "2933 MT/s", Manufacturer: "Samsung",
+			SerialNumber: "0x1A2B3C4D", PartNumber: "M393A4K40DB3-CWE", Rank: "2",
+		},
+		// 空槽的容量和速率记为空
+		{
+			Locator: "CPU0_DIMM_A2", BankLocator: "P0_Node0_Channel0_Dimm1", Type: "DDR4",
+			Manufacturer: "Samsung", SerialNumber: "0x1A2B3C4D", PartNumber: "M393A4K40DB3-CWE", Rank: "2",
+		},
+	}
+	if got := parseDIMMs(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDIMMs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSlotChannel(t *testing.T) {
+	tests := []struct {
+		locator, bank string
+		want          string
+	}{
+		{"CPU0_DIMM_A1", "P0_Node0_Channel0_Dimm0", "P0_NODE0_0"},
+		{"CPU1_DIMM_A1", "P1_Node1_Channel0_Dimm0", "P1_NODE1_0"},
+		{"ChannelA-DIMM0", "BANK 0", "A"},
+		{"CPU0_DIMM_B2", "NODE 1", "CPU0_B"},
+		{"DIMM 0", "BANK 0", ""},
+	}
+	for _, tt := range tests {
+		if got := slotChannel(model.DIMM{Locator: tt.locator, BankLocator: tt.bank}); got != tt.want {
+			t.Errorf("slotChannel(%s, %s) = %q, want %q", tt.locator, tt.bank, got, tt.want)
+		}
+	}
+}
+
+func TestDiagnoseDIMMs(t *testing.T) {
+	dimm := func(locator, size, speed, configured string) model.DIMM {
+		return model.DIMM{Locator: locator, Size: size, Speed: speed, ConfiguredSpeed: configured}
+	}
+	tests := []struct {
+		name  string
+		slots []model.DIMM
+		want  []string
+	}{
+		{
+			name: "matched",
+			slots: []model.DIMM{
+				dimm("CPU0_DIMM_A1", "32 GB", "3200 MT/s", "3200 MT/s"),
+				dimm("CPU0_DIMM_B1", "32 GB", "3200 MT/s", "3200 MT/s"),
+				dimm("CPU0_DIMM_A2", "", "", ""),
+				dimm("CPU0_DIMM_B2", "", "", ""),
+			},
+		},
+		{
+			name: "mixed size",
+			slots: []model.DIMM{
+				dimm("CPU0_DIMM_A1", "32 GB", "3200 MT/s", "3200 MT/s"),
+				dimm("CPU0_DIMM_B1", "16 GB", "3200 MT/s", "3200 MT/s"),
+			},
+			want: []string{"内存条容量不一致: 16 GB, 32 GB"},
+		},
+		{
+			name: "mixed speed",
+			slots: []model.DIMM{
+				dimm("CPU0_DIMM_A1", "32 GB", "3200 MT/s", "2933 MT/s"),
+				dimm("CPU0_DIMM_B1", "32 GB", "2933 MT/s", "2933 MT/s"),
+			},
+			want: []string{"CPU0_DIMM_A1 降频运行(2933 MT/s，额定 3200 MT/s)", "内存条额定速率不一致: 2933 MT/s, 3200 MT/s"},
+		},
+		{
+			name: "downclocked",
+			slots: []model.DIMM{
+				dimm("CPU0_DIMM_A1", "32 GB", "3200 MT/s", "2666 MT/s"),
+				dimm("CPU0_DIMM_B1", "32 GB", "3200 MT/s", "2666 MT/s"),
+			},
+			want: []string{"CPU0_DIMM_A1 降频运行(2666 MT/s，额定 3200 MT/s)", "CPU0_DIMM_B1 降频运行(2666 MT/s，额定 3200 MT/s)"},
+		},
+		{
+			// 旧版本 dmidecode 使用 MHz
+			name: "downclocked MHz",
+			slots: []model.DIMM{
+				dimm("DIMM_A1", "16 GB", "2666 MHz", "2400 MHz"),
+			},
+			want: []string{"DIMM_A1 降频运行(2400 MHz，额定 2666 MHz)"},
+		},
+		{
+			name: "single channel",
+			slots: []model.DIMM{
+				dimm("DIMM_A1", "16 GB", "3200 MT/s", "3200 MT/s"),
+				dimm("DIMM_A2", "16 GB", "3200 MT/s", "3200 MT/s"),
+				dimm("DIMM_B1", "", "", ""),
+				dimm("DIMM_B2", "", "", ""),
+			},
+			want: []string{"只有一个内存通道插了内存条(共 2 个通道)"},
+		},
+		{
+			// 无法识别通道时不检查单通道
+			name:  "unknown channels",
+			slots: []model.DIMM{dimm("DIMM 0", "16 GB", "3200 MT/s", "3200 MT/s"), dimm("DIMM 1", "", "", "")},
+		},
+		{name: "no slots"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := diagnoseDIMMs(tt.slots), strings.Join(tt.want, "; "); got != want {
+				t.Errorf("diagnoseDIMMs() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCollectDIMMs(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/proc/meminfo": meminfoFixture})
+	testutil.FakeCommands(t, map[string]string{
+		"dmidecode -t 17": memoryDevice("CPU0_DIMM_A1", "P0_Node0_Channel0_Dimm0", "32 GB", "3200 MT/s", "2933 MT/s") +
+			memoryDevice("CPU0_DIMM_B1", "P0_Node0_Channel1_Dimm0", "", "", ""),
+	})
+
+	// 只输出已插的内存条,空槽仍参与单通道检查
+	mem, err := Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mem.DIMMs) != 1 || mem.DIMMs[0].Locator != "CPU0_DIMM_A1" {
+		t.Errorf("DIMMs = %+v, want CPU0_DIMM_A1", mem.DIMMs)
+	}
+	if want := "CPU0_DIMM_A1 降频运行(2933 MT/s，额定 3200 MT/s); 只有一个内存通道插了内存条(共 2 个通道)"; mem.Diagnose != want {
+		t.Errorf("Diagnose = %q, want %q", mem.Diagnose, want)
+	}
+}
+
+func TestCollectDIMMsFailureIsWarning(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/proc/meminfo": meminfoFixture})
+	testutil.FakeCommands(t, nil)
+
+	warnings := &utils.Warnings{}
+	mem, err := Collect(utils.WithWarnings(context.Background(), warnings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mem.Total == 0 || mem.DIMMs != nil || mem.Diagnose != "" {
+		t.Errorf("Collect() = %+v, want usage without DIMMs", mem)
+	}
+	if list := warnings.List(); len(list) != 1 || list[0].Module != "memory" || !strings.Contains(list[0].Message, "dmidecode") {
+		t.Errorf("warnings = %v, want the dmidecode failure", list)
+	}
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysfsEDAC string = "/sys/devices/system/edac/mc"
+
+// collectEDAC 读取EDAC各内存控制器及其下内存条(或 rank)的可纠正/不可纠正错误计数。
+// 4.x 以后的内核按内存条提供 dimm*/dimm_ce_count，旧内核只有按 rank 的 csrow*/ce_count；
+// 未加载EDAC驱动(如虚拟机)时没有该目录，返回空值
+func collectEDAC() []model.MemoryController {
+	root := utils.HostPath(sysfsEDAC)
+	mcs, err := filepath.Glob(filepath.Join(root, "mc[0-9]*"))
+	if err != nil || len(mcs) == 0 {
+		return nil
+	}
+	slices.Sort(mcs)
+
+	controllers := make([]model.MemoryController, 0, len(mcs))
+	for _, dir := range mcs {
+		read := func(attr string) string {
+			v, _ := utils.ReadSysfsFile(filepath.Join(dir, attr))
+			return v
+		}
+
+		mc := model.MemoryController{
+			Name:    filepath.Base(dir),
+			CECount: read("ce_count"),
+			UECount: read("ue_count"),
+		}
+
+		mc.DIMMs = collectEDACDIMMs(dir)
+		controllers = append(controllers, mc)
+	}
+	return controllers
+}
+
+// collectEDACDIMMs 读取内存控制器下各内存条的错误计数，没有 dimm* 目录时读取 csrow*
+func collectEDACDIMMs(mcDir string) []model.EDACDIMM {
+	readIn := func(dir, attr string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(dir, attr))
+		return v
+	}
+
+	var dimms []model.EDACDIMM
+	if dirs, _ := filepath.Glob(filepath.Join(mcDir, "dimm[0-9]*")); len(dirs) > 0 {
+		for _, dir := range sortedDirs(dirs) {
+			dimms = append(dimms, model.EDACDIMM{
+				Name:     filepath.Base(dir),
+				Label:    readIn(dir, "dimm_label"),
+				Location: readIn(dir, "dimm_location"),
+				CECount:  readIn(dir, "dimm_ce_count"),
+				UECount:  readIn(dir, "dimm_ue_count"),
+			})
+		}
+		return dimms
+	}
+
+	dirs, _ := filepath.Glob(filepath.Join(mcDir, "csrow[0-9]*"))
+	for _, dir := range sortedDirs(dirs) {
+		dimms = append(dimms, model.EDACDIMM{
+			Name:    filepath.Base(dir),
+			Label:   readIn(dir, "ch0_dimm_label"),
+			CECount: readIn(dir, "ce_count"),
+			UECount: readIn(dir, "ue_count"),
+		})
+	}
+	return dimms
+}
+
+// sortedDirs 返回按名称中的序号排序的目录，使 dimm10 排在 dimm9 之后
+func sortedDirs(dirs []string) []string {
+	slices.SortFunc(dirs, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+	return dirs
+}
+
+// diagnoseEDAC 返回EDAC错误计数中的问题：不可纠正错误说明内存条即将故障，应尽快更换；
+// 可纠正错误持续增长同样预示故障。没有错误时返回空字符串
+func diagnoseEDAC(controllers []model.MemoryController) string {
+	var problems []string
+	for _, mc := range controllers {
+		if len(mc.DIMMs) == 0 {
+			problems = append(problems, edacProblem(mc.Name, mc.CECount, mc.UECount)...)
+			continue
+		}
+		for _, d := range mc.DIMMs {
+			name := mc.Name + "/" + d.Name
+			if d.Label != "" {
+				name += "(" + d.Label + ")"
+			}
+			problems = append(problems, edacProblem(name, d.CECount, d.UECount)...)
+		}
+	}
+	return strings.Join(problems, "; ")
+}
+
+func edacProblem(name, ce, ue string) []string {
+	var problems []string
+	if ue != "" && ue != "0" {
+		problems = append(problems, fmt.Sprintf("%s 存在不可纠正内存错误(ue_count=%s)", name, ue))
+	}
+	if ce != "" && ce != "0" {
+		problems = append(problems, fmt.Sprintf("%s 存在可纠正内存错误(ce_count=%s)", name, ce))
+	}
+	return problems
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCollectEDAC(t *testing.T) {
+	const mc = sysfsEDAC + "/"
+	testutil.FakeRoot(t, map[string]string{
+		mc + "mc0/ce_count":             "17\n",
+		mc + "mc0/ue_count":             "0\n",
+		mc + "mc0/dimm0/dimm_label":     "CPU_SrcID#0_Ha#0_Chan#0_DIMM#0\n",
+		mc + "mc0/dimm0/dimm_location":  "channel 0 slot 0\n",
+		mc + "mc0/dimm0/dimm_ce_count":  "0\n",
+		mc + "mc0/dimm0/dimm_ue_count":  "0\n",
+		mc + "mc0/dimm1/dimm_label":     "CPU_SrcID#0_Ha#0_Chan#1_DIMM#0\n",
+		mc + "mc0/dimm1/dimm_location":  "channel 1 slot 0\n",
+		mc + "mc0/dimm1/dimm_ce_count":  "17\n",
+		mc + "mc0/dimm1/dimm_ue_count":  "0\n",
+		mc + "mc0/dimm10/dimm_ce_count": "0\n",
+		mc + "mc0/dimm10/dimm_ue_count": "0\n",
+		// 旧内核只有按 rank 的 csrow
+		mc + "mc1/ce_count":              "0\n",
+		mc + "mc1/ue_count":              "2\n",
+		mc + "mc1/csrow0/ch0_dimm_label": "DIMM_B1\n",
+		mc + "mc1/csrow0/ce_count":       "0\n",
+		mc + "mc1/csrow0/ue_count":       "2\n",
+		mc + "mc1/csrow1/ce_count":       "0\n",
+		mc + "mc1/csrow1/ue_count":       "0\n",
+	})
+
+	got := collectEDAC()
+	want := []model.MemoryController{
+		{
+			Name: "mc0", CECount: "17", UECount: "0",
+			DIMMs: []model.EDACDIMM{
+				{Name: "dimm0", Label: "CPU_SrcID#0_Ha#0_Chan#0_DIMM#0", Location: "channel 0 slot 0", CECount: "0", UECount: "0"},
+				{Name: "dimm1", Label: "CPU_SrcID#0_Ha#0_Chan#1_DIMM#0", Location: "channel 1 slot 0", CECount: "17", UECount: "0"},
+				{Name: "dimm10", CECount: "0", UECount: "0"},
+			},
+		},
+		{
+			Name: "mc1", CECount: "0", UECount: "2",
+			DIMMs: []model.EDACDIMM{
+				{Name: "csrow0", Label: "DIMM_B1", CECount: "0", UECount: "2"},
+				{Name: "csrow1", CECount: "0", UECount: "0"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("collectEDAC() = %+v, want %+v", got, want)
+	}
+
+	wantDiagnose := "mc0/dimm1(CPU_SrcID#0_Ha#0_Chan#1_DIMM#0) 存在可纠正内存错误(ce_count=17); " +
+		"mc1/csrow0(DIMM_B1) 存在不可纠正内存错误(ue_count=2)"
+	if got := diagnoseEDAC(got); got != wantDiagnose {
+		t.Errorf("diagnoseEDAC() = %q, want %q", got, wantDiagnose)
+	}
+}
+
+func TestCollectEDACWithoutDIMMs(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		sysfsEDAC + "/mc0/ce_count": "3\n",
+		sysfsEDAC + "/mc0/ue_count": "1\n",
+	})
+
+	// 没有按内存条的计数时按内存控制器诊断
+	got := collectEDAC()
+	if len(got) != 1 || got[0].DIMMs != nil {
+		t.Fatalf("collectEDAC() = %+v, want mc0 without DIMMs", got)
+	}
+	want := "mc0 存在不可纠正内存错误(ue_count=1); mc0 存在可纠正内存错误(ce_count=3)"
+	if got := diagnoseEDAC(got); got != want {
+		t.Errorf("diagnoseEDAC() = %q, want %q", got, want)
+	}
+}
+
+func TestCollectNoEDAC(t *testing.T) {
+	// 未加载EDAC驱动(如虚拟机)
+	testutil.FakeRoot(t, map[string]string{"/proc/meminfo": meminfoFixture})
+	testutil.FakeCommands(t, nil)
+
+	mem, err := Collect(utils.WithProfile(context.Background(), utils.ProfileMinimal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mem.EDAC != nil || mem.Diagnose != "" {
+		t.Errorf("EDAC = %+v, Diagnose = %q, want none", mem.EDAC, mem.Diagnose)
+	}
+
+	// 存在错误时写入 Diagnose
+	testutil.FakeRoot(t, map[string]string{
+		"/proc/meminfo":                        meminfoFixture,
+		sysfsEDAC + "/mc0/ce_count":            "0\n",
+		sysfsEDAC + "/mc0/ue_count":            "0\n",
+		sysfsEDAC + "/mc0/dimm0/dimm_ce_count": "5\n",
+		sysfsEDAC + "/mc0/dimm0/dimm_ue_count": "0\n",
+	})
+	mem, err = Collect(utils.WithProfile(context.Background(), utils.ProfileMinimal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "mc0/dimm0 存在可纠正内存错误(ce_count=5)"; len(mem.EDAC) != 1 || mem.Diagnose != want {
+		t.Errorf("EDAC = %+v, Diagnose = %q, want %q", mem.EDAC, mem.Diagnose, want)
+	}
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const procMeminfo string = "/proc/meminfo"
+
+// Collect 从/proc/meminfo采集内存使用情况和EDAC内存错误计数，并通过dmidecode采集内存条和检查内存插法。
+// minimal 采集配置下跳过dmidecode；dmidecode 失败(如非root运行)只记录警告
+func Collect(ctx context.Context) (model.Memory, error) {
+	content, err := utils.ReadSysfsFile(utils.HostPath(procMeminfo))
+	if err != nil {
+		return model.Memory{}, fmt.Errorf("read %s failed: %w", procMeminfo, err)
+	}
+
+	mem := parseMeminfo(content)
+	mem.EDAC = collectEDAC()
+	problems := []string{diagnoseEDAC(mem.EDAC)}
+
+	if !utils.IsMinimal(ctx) {
+		slots, err := collectDIMMs(ctx)
+		if err != nil {
+			utils.WarningsFrom(ctx).Add("memory", err)
+		}
+		for _, d := range slots {
+			if d.Size != "" {
+				mem.DIMMs = append(mem.DIMMs, d)
+			}
+		}
+		problems = append(problems, diagnoseDIMMs(slots))
+	}
+
+	mem.Diagnose = strings.Join(slices.DeleteFunc(problems, func(p string) bool { return p == "" }), "; ")
+	return mem, nil
+}
+
+// parseMeminfo 解析/proc/meminfo，每行格式为 "名称: 数值 kB"：
+//
+//	MemTotal:       16318480 kB
+//	MemFree:         1238468 kB
+//	MemAvailable:    9730836 kB
+//
+// 已用内存按 Total - Available 计算，而不是 Total - Free：页缓存等可回收的内存不算作已用，
+// 否则长时间运行的主机使用率会接近100%。3.14 之前的内核没有 MemAvailable，以 Free + Buffers + Cached 估算
+func parseMeminfo(content string) model.Memory {
+	values := make(map[string]uint64)
+	for line := range strings.Lines(content) {
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			v *= 1024
+		}
+		values[strings.TrimSpace(name)] = v
+	}
+
+	mem := model.Memory{
+		Total:     values["MemTotal"],
+		Free:      values["MemFree"],
+		Buffers:   values["Buffers"],
+		Cached:    values["Cached"],
+		SwapTotal: values["SwapTotal"],
+	}
+
+	available, ok := values["MemAvailable"]
+	if !ok {
+		available = mem.Free + mem.Buffers + mem.Cached
+	}
+	mem.Available = min(available, mem.Total)
+	mem.Used = mem.Total - mem.Available
+
+	if swapFree := values["SwapFree"]; swapFree <= mem.SwapTotal {
+		mem.SwapUsed = mem.SwapTotal - swapFree
+	}
+	if mem.Total > 0 {
+		mem.UsedPercent = float64(mem.Used) / float64(mem.Total) * 100
+	}
+
+	return mem
+}
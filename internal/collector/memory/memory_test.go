@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const meminfoFixture = `MemTotal:       16000000 kB
+MemFree:         1000000 kB
+MemAvailable:    12000000 kB
+Buffers:          500000 kB
+Cached:          9000000 kB
+SwapCached:            0 kB
+SwapTotal:       4000000 kB
+SwapFree:        3000000 kB
+HugePages_Total:       0
+Hugepagesize:       2048 kB
+`
+
+func TestParseMeminfo(t *testing.T) {
+	const kb = 1024
+	tests := []struct {
+		name        string
+		content     string
+		want        model.Memory
+		wantPercent float64
+	}{
+		{
+			name:    "MemAvailable",
+			content: meminfoFixture,
+			want: model.Memory{
+				Total: 16000000 * kb, Free: 1000000 * kb, Available: 12000000 * kb, Used: 4000000 * kb,
+				Buffers: 500000 * kb, Cached: 9000000 * kb, SwapTotal: 4000000 * kb, SwapUsed: 1000000 * kb,
+			},
+			wantPercent: 25, // 按 Free 计算会得到 93.75%
+		},
+		{
+			name:    "kernel without MemAvailable",
+			content: "MemTotal: 1000 kB\nMemFree: 100 kB\nBuffers: 100 kB\nCached: 300 kB\n",
+			want: model.Memory{
+				Total: 1000 * kb, Free: 100 * kb, Buffers: 100 * kb, Cached: 300 * kb,
+				Available: 500 * kb, Used: 500 * kb,
+			},
+			wantPercent: 50,
+		},
+		{
+			name:    "inconsistent values",
+			content: "MemTotal: 1000 kB\nMemAvailable: 2000 kB\nSwapTotal: 10 kB\nSwapFree: 20 kB\n",
+			want:    model.Memory{Total: 1000 * kb, Available: 1000 * kb, SwapTotal: 10 * kb},
+		},
+		{
+			name:    "empty",
+			content: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMeminfo(tt.content)
+			percent := got.UsedPercent
+			got.UsedPercent = 0
+			if got.Total != tt.want.Total || got.Used != tt.want.Used || got.Free != tt.want.Free ||
+				got.Available != tt.want.Available || got.Buffers != tt.want.Buffers || got.Cached != tt.want.Cached ||
+				got.SwapTotal != tt.want.SwapTotal || got.SwapUsed != tt.want.SwapUsed {
+				t.Errorf("parseMeminfo() = %+v, want %+v", got, tt.want)
+			}
+			if percent != tt.wantPercent {
+				t.Errorf("UsedPercent = %v, want %v", percent, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestCollectMinimal(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/proc/meminfo": meminfoFixture})
+	testutil.FakeCommands(t, nil)
+
+	mem, err := Collect(utils.WithProfile(context.Background(), utils.ProfileMinimal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mem.Total != 16000000*1024 || mem.UsedPercent != 25 || mem.DIMMs != nil || mem.Diagnose != "" {
+		t.Errorf("Collect() = %+v", mem)
+	}
+
+	testutil.FakeRoot(t, nil)
+	if _, err := Collect(context.Background()); err == nil {
+		t.Error("Collect() without /proc/meminfo succeeded")
+	}
+}
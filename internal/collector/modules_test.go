@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBuiltinModules(t *testing.T) {
+	infos := BuiltinModules()
+
+	// 顺序与采集顺序一致,每个模块都有说明
+	var names []string
+	for _, m := range infos {
+		names = append(names, m.Name)
+		if m.Description == "" {
+			t.Errorf("module %s has no description", m.Name)
+		}
+		if err := ValidateModules([]string{m.Name}); err != nil {
+			t.Errorf("module %s: %v", m.Name, err)
+		}
+	}
+	if len(names) != len(moduleCollectors) || names[0] != "product" || !slices.Contains(names, "service") {
+		t.Fatalf("modules = %q", names)
+	}
+
+	byName := make(map[string]ModuleInfo)
+	for _, m := range infos {
+		byName[m.Name] = m
+	}
+	tests := []struct {
+		name                              string
+		root, optional, minimal, explicit bool
+		tools                             []string
+	}{
+		{name: "system", minimal: true},
+		{name: "memory", root: true, minimal: true, tools: []string{"dmidecode"}},
+		{name: "disk", root: true, tools: []string{"smartctl", "nvme"}},
+		{name: "network", minimal: true, tools: []string{"ethtool", "lldpctl", "ip"}},
+		{name: "gpu", optional: true, tools: []string{"nvidia-smi"}},
+		{name: "service", optional: true, explicit: true, tools: []string{"systemctl"}},
+	}
+	for _, tt := range tests {
+		m, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("module %s missing", tt.name)
+			continue
+		}
+		if m.RequiresRoot != tt.root || m.Optional != tt.optional || m.Minimal != tt.minimal || m.Explicit != tt.explicit || !slices.Equal(m.Tools, tt.tools) {
+			t.Errorf("module %s = %+v", tt.name, m)
+		}
+		if m.Timeout != 0 {
+			t.Errorf("module %s Timeout = %v, want 0 without a collector", tt.name, m.Timeout)
+		}
+	}
+
+	// 返回的是副本,修改不影响模块定义
+	byName["disk"].Tools[0] = "changed"
+	if got := BuiltinModules(); !slices.Contains(got[slices.IndexFunc(got, func(m ModuleInfo) bool { return m.Name == "disk" })].Tools, "smartctl") {
+		t.Error("modifying the returned tools changed the module definition")
+	}
+}
+
+func TestCollectorModulesTimeouts(t *testing.T) {
+	c := newTestCollector(t)
+	if err := c.SetModuleTimeouts(map[string]time.Duration{"disk": 30 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range c.Modules() {
+		want := time.Duration(0)
+		if m.Name == "disk" {
+			want = 30 * time.Second
+		}
+		if m.Timeout != want {
+			t.Errorf("module %s Timeout = %v, want %v", m.Name, m.Timeout, want)
+		}
+	}
+}
@@ -1,7 +1,130 @@
 package network
 
-import "github.com/zenithax-cc/diting/internal/model"
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
-func (n *model.NetInterface) collectEthtoolSetting(eth string) error {
-	return nil
+	"github.com/zenithax-cc/diting/internal/collector/ethtool"
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+const (
+	ethtoolCmd     string = "ethtool"
+	ethtoolTimeout        = 10 * time.Second
+)
+
+// runEthtool 执行 ethtool [args...] <eth>
+func runEthtool(ctx context.Context, eth string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, ethtoolTimeout)
+	defer cancel()
+
+	args = append(args, eth)
+	out, err := runNetCommand(ctx, ethtoolCmd, args...)
+	if err != nil {
+		return nil, fmt.Errorf("run %s %s failed: %w", ethtoolCmd, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+func collectEthtoolSetting(ctx context.Context, eth string) (ethtool.Settings, error) {
+	out, err := runEthtool(ctx, eth)
+	if err != nil {
+		return ethtool.Settings{}, err
+	}
+
+	return ethtool.ParseSettings(out), nil
+}
+
+func collectDriverInfo(ctx context.Context, eth string) (ethtool.DriverInfo, error) {
+	out, err := runEthtool(ctx, eth, "-i")
+	if err != nil {
+		return ethtool.DriverInfo{}, err
+	}
+
+	return ethtool.ParseDriverInfo(out), nil
+}
+
+// collectRingBuffer 采集网卡环形缓冲区的当前值和最大值，驱动不支持时返回错误
+func collectRingBuffer(ctx context.Context, eth string) (model.RingBuffer, error) {
+	out, err := runEthtool(ctx, eth, "-g")
+	if err != nil {
+		return model.RingBuffer{}, err
+	}
+
+	cur, max := ethtool.ParseRing(out)
+	return model.RingBuffer{
+		CurrentRX: cur.RX,
+		CurrentTX: cur.TX,
+		MaxRX:     max.RX,
+		MaxTX:     max.TX,
+	}, nil
+}
+
+// collectChannel 采集网卡队列数的当前值和最大值，驱动不支持时返回错误
+func collectChannel(ctx context.Context, eth string) (model.Channel, error) {
+	out, err := runEthtool(ctx, eth, "-l")
+	if err != nil {
+		return model.Channel{}, err
+	}
+
+	cur, max := ethtool.ParseChannels(out)
+	return model.Channel{
+		MaxRX:           max.RX,
+		MaxTX:           max.TX,
+		MaxCombined:     max.Combined,
+		CurrentRX:       cur.RX,
+		CurrentTX:       cur.TX,
+		CurrentCombined: cur.Combined,
+	}, nil
+}
+
+// collectFeatures 采集网卡特性(卸载功能)的开启状态
+func collectFeatures(ctx context.Context, eth string) (map[string]model.NetFeature, error) {
+	out, err := runEthtool(ctx, eth, "-k")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := ethtool.ParseFeatures(out)
+	if len(parsed) == 0 {
+		return nil, nil
+	}
+
+	features := make(map[string]model.NetFeature, len(parsed))
+	for name, f := range parsed {
+		features[name] = model.NetFeature{Enabled: f.Enabled, Fixed: f.Fixed}
+	}
+	return features, nil
+}
+
+// collectStatistics 采集网卡驱动的统计计数器(ethtool -S)，驱动不支持时返回错误
+func collectStatistics(ctx context.Context, eth string) (map[string]uint64, error) {
+	out, err := runEthtool(ctx, eth, "-S")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := ethtool.ParseStatistics(out)
+	if len(stats) == 0 {
+		return nil, nil
+	}
+	return stats, nil
+}
+
+// maxSupportedSpeed 返回链路模式中的最大速率，单位Mb/s，如 10000baseT/Full 为 10000
+func maxSupportedSpeed(modes []string) int {
+	var maxSpeed int
+	for _, mode := range modes {
+		speed, _, ok := strings.Cut(mode, "base")
+		if !ok {
+			continue
+		}
+		if v, err := strconv.Atoi(speed); err == nil && v > maxSpeed {
+			maxSpeed = v
+		}
+	}
+	return maxSpeed
 }
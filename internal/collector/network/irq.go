@@ -0,0 +1,129 @@
+package network
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	procInterrupts string = "/proc/interrupts"
+	procIRQ        string = "/proc/irq"
+)
+
+// collectIRQs 采集网卡的中断(收发队列)及其CPU亲和性。
+// 优先从PCI设备的 msi_irqs 获取中断号，没有时按中断名称中包含的接口名匹配
+func collectIRQs(eth string, interrupts map[int]string) []model.IRQ {
+	numbers := deviceMSIIRQs(eth)
+	if len(numbers) == 0 {
+		for number, name := range interrupts {
+			if irqNameMatches(name, eth) {
+				numbers = append(numbers, number)
+			}
+		}
+	}
+	slices.Sort(numbers)
+
+	irqs := make([]model.IRQ, 0, len(numbers))
+	for _, number := range numbers {
+		affinity, _ := utils.ReadSysfsFile(filepath.Join(utils.HostPath(procIRQ), strconv.Itoa(number), "smp_affinity_list"))
+		irqs = append(irqs, model.IRQ{
+			Number:   strconv.Itoa(number),
+			Name:     interrupts[number],
+			Affinity: affinity,
+		})
+	}
+
+	return irqs
+}
+
+// irqNameMatches 判断中断名称中是否包含接口名，如 eth0-TxRx-0、i40e-eth0-TxRx-1，
+// 按分隔符切分后比较，避免 eth1 匹配到 eth10 的中断
+func irqNameMatches(name, eth string) bool {
+	return slices.Contains(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '@' || r == ',' || r == ' '
+	}), eth)
+}
+
+// deviceMSIIRQs 读取网卡对应PCI设备的MSI/MSI-X中断号，virtio网卡的 device 指向
+// virtio 设备，msi_irqs 位于其上一级的PCI设备目录
+func deviceMSIIRQs(eth string) []int {
+	dev, err := filepath.EvalSymlinks(filepath.Join(netSysfsDir(), eth, "device"))
+	if err != nil {
+		return nil
+	}
+
+	for _, dir := range []string{dev, filepath.Dir(dev)} {
+		entries, err := os.ReadDir(filepath.Join(dir, "msi_irqs"))
+		if err != nil {
+			continue
+		}
+
+		numbers := make([]int, 0, len(entries))
+		for _, entry := range entries {
+			if n, err := strconv.Atoi(entry.Name()); err == nil {
+				numbers = append(numbers, n)
+			}
+		}
+		return numbers
+	}
+
+	return nil
+}
+
+// parseInterrupts 解析 /proc/interrupts，返回中断号到中断名称的映射。
+// 每行的CPU计数列数与表头一致，之后依次为中断控制器、硬件中断号/触发方式和名称，
+// 这几列的个数因内核版本和控制器而异，因此名称从行尾取，共享中断的多个名称以 ", " 分隔：
+//
+//	           CPU0       CPU1
+//	 40:       1719          0 PCI-MSIX-0000:00:04.0   1-edge      virtio3-input.0
+//	 16:          0          0 IO-APIC  16-fasteoi   ehci_hcd:usb1, eth1
+//	NMI:          0          0   Non-maskable interrupts
+func parseInterrupts(out string) map[int]string {
+	interrupts := make(map[int]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	if !scanner.Scan() {
+		return interrupts
+	}
+	cpus := len(strings.Fields(scanner.Text()))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		number, err := strconv.Atoi(strings.TrimSuffix(fields[0], ":"))
+		if err != nil {
+			// NMI、LOC 等非数字中断
+			continue
+		}
+
+		rest := fields[1:]
+		for i := 0; i < cpus && len(rest) > 0; i++ {
+			if _, err := strconv.ParseUint(rest[0], 10, 64); err != nil {
+				break
+			}
+			rest = rest[1:]
+		}
+		if len(rest) == 0 {
+			continue
+		}
+
+		// 从行尾向前合并以逗号结尾的名称
+		start := len(rest) - 1
+		for start > 0 && strings.HasSuffix(rest[start-1], ",") {
+			start--
+		}
+		interrupts[number] = strings.Join(rest[start:], " ")
+	}
+
+	return interrupts
+}
@@ -0,0 +1,96 @@
+package network
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+// 四核主机上一块4队列的 i40e 网卡，以及与 USB 共享中断的 eth1、名称相近的 eth10
+const procInterruptsFixture = `           CPU0       CPU1       CPU2       CPU3       
+   0:         27          0          0          0   IO-APIC    2-edge      timer
+  16:          0          0          0          0   IO-APIC   16-fasteoi   ehci_hcd:usb1, eth1
+  64:    1719392          0          0          0   PCI-MSI 1048576-edge      i40e-0000:3b:00.0:misc
+  65:     128301     901283          0          0   PCI-MSI 1048577-edge      i40e-eth0-TxRx-0
+  66:          0     128733     812731          0   PCI-MSI 1048578-edge      i40e-eth0-TxRx-1
+  67:          0          0     731282     128312   PCI-MSI 1048579-edge      i40e-eth0-TxRx-2
+  68:     821732          0          0     912731   PCI-MSI 1048580-edge      i40e-eth0-TxRx-3
+  70:         12          0          0          0   PCI-MSI 1050624-edge      eth10-TxRx-0
+ NMI:          0          0          0          0   Non-maskable interrupts
+ LOC:   12873123   12387123   12837123   12387123   Local timer interrupts
+ ERR:          0
+`
+
+func TestParseInterrupts(t *testing.T) {
+	got := parseInterrupts(procInterruptsFixture)
+	want := map[int]string{
+		0:  "timer",
+		16: "ehci_hcd:usb1, eth1",
+		64: "i40e-0000:3b:00.0:misc",
+		65: "i40e-eth0-TxRx-0",
+		66: "i40e-eth0-TxRx-1",
+		67: "i40e-eth0-TxRx-2",
+		68: "i40e-eth0-TxRx-3",
+		70: "eth10-TxRx-0",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseInterrupts() = %v, want %v", got, want)
+	}
+
+	if got := parseInterrupts(""); len(got) != 0 {
+		t.Errorf("parseInterrupts(\"\") = %v", got)
+	}
+}
+
+func TestCollectIRQsByName(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/proc/irq/65/smp_affinity_list": "0\n",
+		"/proc/irq/66/smp_affinity_list": "1\n",
+		"/proc/irq/67/smp_affinity_list": "2\n",
+		"/proc/irq/68/smp_affinity_list": "0-3\n",
+		"/proc/irq/16/smp_affinity_list": "0-3\n",
+	})
+	interrupts := parseInterrupts(procInterruptsFixture)
+
+	got := collectIRQs("eth0", interrupts)
+	want := []model.IRQ{
+		{Number: "65", Name: "i40e-eth0-TxRx-0", Affinity: "0"},
+		{Number: "66", Name: "i40e-eth0-TxRx-1", Affinity: "1"},
+		{Number: "67", Name: "i40e-eth0-TxRx-2", Affinity: "2"},
+		{Number: "68", Name: "i40e-eth0-TxRx-3", Affinity: "0-3"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("collectIRQs(eth0) = %+v, want %+v", got, want)
+	}
+
+	// 共享中断按名称匹配，eth1 不匹配 eth10 的中断
+	got = collectIRQs("eth1", interrupts)
+	if len(got) != 1 || got[0].Number != "16" {
+		t.Errorf("collectIRQs(eth1) = %+v, want only IRQ 16", got)
+	}
+}
+
+func TestCollectIRQsFromMSI(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		"/sys/devices/pci0000:00/0000:00:04.0/msi_irqs/41":    "msix\n",
+		"/sys/devices/pci0000:00/0000:00:04.0/msi_irqs/40":    "msix\n",
+		"/sys/devices/pci0000:00/0000:00:04.0/virtio3/uevent": "",
+		"/proc/irq/40/smp_affinity_list":                      "0\n",
+		"/proc/irq/41/smp_affinity_list":                      "1\n",
+	})
+	// virtio 网卡的 device 指向 virtio 设备，msi_irqs 在上一级的PCI设备中
+	testutil.Symlink(t, root, root+"/sys/devices/pci0000:00/0000:00:04.0/virtio3", "/sys/class/net/ens4/device")
+
+	interrupts := map[int]string{40: "virtio3-input.0", 41: "virtio3-output.0"}
+	got := collectIRQs("ens4", interrupts)
+	want := []model.IRQ{
+		{Number: "40", Name: "virtio3-input.0", Affinity: "0"},
+		{Number: "41", Name: "virtio3-output.0", Affinity: "1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("collectIRQs(ens4) = %+v, want %+v", got, want)
+	}
+}
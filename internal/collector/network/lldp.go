@@ -1 +1,92 @@
 package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/executor"
+)
+
+const lldpctl string = "lldpctl"
+
+// lldpTimeout lldpd 繁忙时 lldpctl 会阻塞，单个接口最多等待的时间
+var lldpTimeout = 5 * time.Second
+
+// collectLLDP 通过 lldpctl 采集接口上联交换机的 LLDP 信息，超时返回错误，不阻塞整个网络采集
+func collectLLDP(ctx context.Context, eth string) (model.LLDP, error) {
+	ctx, cancel := context.WithTimeout(ctx, lldpTimeout)
+	defer cancel()
+
+	out, err := executor.ExecuteWithContext(ctx, lldpctl, "-f", "keyvalue", eth)
+	if err != nil {
+		return model.LLDP{}, fmt.Errorf("run %s %s failed: %w", lldpctl, eth, err)
+	}
+
+	return parseLLDP(string(out), eth), nil
+}
+
+// parseLLDP 解析 lldpctl -f keyvalue <eth> 输出，描述信息可能跨多行：
+//
+//	lldp.eth0.chassis.mac=00:1c:73:aa:bb:cc
+//	lldp.eth0.chassis.name=tor-01
+//	lldp.eth0.chassis.descr=Arista Networks EOS version 4.20
+//	running on an Arista Networks DCS-7050SX
+//	lldp.eth0.chassis.mgmt-ip=10.0.0.1
+//	lldp.eth0.port.ifname=Ethernet1
+//	lldp.eth0.vlan.vlan-id=100
+func parseLLDP(out, eth string) model.LLDP {
+	var (
+		lldp    model.LLDP
+		lastKey string
+	)
+
+	prefix := "lldp." + eth + "."
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			// 上一个字段的续行
+			if lastKey == "chassis.descr" && strings.TrimSpace(line) != "" {
+				lldp.SystemDesc += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		lastKey = strings.TrimPrefix(key, prefix)
+		value = strings.TrimSpace(value)
+		switch lastKey {
+		case "chassis.mac", "chassis.local":
+			if lldp.ChassisID == "" {
+				lldp.ChassisID = value
+			}
+		case "chassis.name":
+			lldp.SystemName = value
+		case "chassis.descr":
+			lldp.SystemDesc = value
+		case "chassis.mgmt-ip":
+			if lldp.ManagementIP == "" {
+				lldp.ManagementIP = value
+			}
+		case "port.ifname", "port.local", "port.mac":
+			if lldp.PortID == "" {
+				lldp.PortID = value
+			}
+		case "vlan.vlan-id":
+			lldp.VLAN = value
+		case "ppvid.ppvid":
+			lldp.PPVID = value
+		}
+	}
+
+	if lldp != (model.LLDP{}) {
+		lldp.Interface = eth
+	}
+
+	return lldp
+}
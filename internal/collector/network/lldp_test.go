@@ -0,0 +1,93 @@
+package network
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const lldpctlOutput = `lldp.eth0.via=LLDP
+lldp.eth0.rid=1
+lldp.eth0.chassis.mac=00:1c:73:aa:bb:cc
+lldp.eth0.chassis.name=tor-01
+lldp.eth0.chassis.descr=Arista Networks EOS version 4.20
+running on an Arista Networks DCS-7050SX
+lldp.eth0.chassis.mgmt-ip=10.0.0.1
+lldp.eth0.chassis.mgmt-ip=fe80::1
+lldp.eth0.port.ifname=Ethernet1
+lldp.eth0.vlan.vlan-id=100
+lldp.eth1.chassis.name=other
+`
+
+func TestParseLLDP(t *testing.T) {
+	want := model.LLDP{
+		Interface:    "eth0",
+		ChassisID:    "00:1c:73:aa:bb:cc",
+		SystemName:   "tor-01",
+		SystemDesc:   "Arista Networks EOS version 4.20 running on an Arista Networks DCS-7050SX",
+		ManagementIP: "10.0.0.1",
+		PortID:       "Ethernet1",
+		VLAN:         "100",
+	}
+	if got := parseLLDP(lldpctlOutput, "eth0"); got != want {
+		t.Errorf("parseLLDP() =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := parseLLDP("", "eth0"); got != (model.LLDP{}) {
+		t.Errorf("parseLLDP(\"\") = %+v, want empty", got)
+	}
+}
+
+func TestCollectLLDPTimeout(t *testing.T) {
+	saved := lldpTimeout
+	lldpTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { lldpTimeout = saved })
+
+	root := testutil.FakeRoot(t, map[string]string{
+		"/sys/devices/pci0000:00/0000:00:03.0/net/eth0/address": "52:54:00:12:34:56\n",
+		"/proc/net/arp": "IP address       HW type     Flags       HW address            Mask     Device\n",
+	})
+	testutil.Symlink(t, root, "../../devices/pci0000:00/0000:00:03.0/net/eth0", "/sys/class/net/eth0")
+	testutil.Symlink(t, root, "../../../0000:00:03.0", "/sys/devices/pci0000:00/0000:00:03.0/net/eth0/device")
+
+	// lldpctl 一直阻塞到超时，其他命令未安装
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if name == lldpctl {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+	})
+	t.Cleanup(func() { executor.SetRunner(nil) })
+
+	warnings := &utils.Warnings{}
+	ctx := utils.WithWarnings(context.Background(), warnings)
+
+	start := time.Now()
+	network, err := Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Collect() took %s with a stuck lldpctl", elapsed)
+	}
+
+	if len(network.PhyInterfaces) != 1 || network.PhyInterfaces[0].LLDP != (model.LLDP{}) {
+		t.Errorf("physical interfaces = %+v, want eth0 without LLDP", network.PhyInterfaces)
+	}
+	var found bool
+	for _, w := range warnings.List() {
+		if w.Module == "network" && strings.Contains(w.Message, "lldpctl eth0") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %v, want the lldpctl timeout", warnings.List())
+	}
+}
@@ -0,0 +1,123 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	procNetARP string = "/proc/net/arp"
+	ipCmd      string = "ip"
+)
+
+// /proc/net/arp 中 Flags 列的标志位，见内核 include/uapi/linux/if_arp.h
+const (
+	atfCom  = 0x02 // 已解析到MAC地址
+	atfPerm = 0x04 // 静态表项
+)
+
+// collectNeighbors 采集IPv4(ARP)和IPv6邻居表，sysfs-only 模式或未安装 ip 命令时只返回IPv4邻居
+func collectNeighbors(ctx context.Context) ([]model.Neighbor, error) {
+	content, err := utils.ReadSysfsFile(procNetPath(procNetARP))
+	if err != nil {
+		return nil, fmt.Errorf("read %s failed: %w", procNetARP, err)
+	}
+	neighbors := parseARP(content)
+	if sysfsOnly {
+		return neighbors, nil
+	}
+
+	out, err := runNetCommand(ctx, ipCmd, "-6", "neigh", "show")
+	if err == nil {
+		neighbors = append(neighbors, parseIPNeigh(string(out))...)
+	}
+
+	return neighbors, nil
+}
+
+// parseARP 解析 /proc/net/arp：
+//
+//	IP address       HW type     Flags       HW address            Mask     Device
+//	192.0.2.1        0x1         0x2         02:fc:00:00:00:05     *        eth0
+func parseARP(content string) []model.Neighbor {
+	var neighbors []model.Neighbor
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[0] == "IP" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[2], 0, 32)
+		if err != nil {
+			continue
+		}
+
+		state := "INCOMPLETE"
+		switch {
+		case flags&atfPerm != 0:
+			state = "PERMANENT"
+		case flags&atfCom != 0:
+			state = "COMPLETE"
+		}
+
+		neighbor := model.Neighbor{
+			Interface: fields[5],
+			IP:        fields[0],
+			State:     state,
+		}
+		if flags&atfCom != 0 {
+			neighbor.MACAddress = fields[3]
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
+
+// parseIPNeigh 解析 ip -6 neigh show 输出，未解析的表项没有 lladdr：
+//
+//	fe80::1 dev eth0 lladdr 00:1c:73:aa:bb:cc router REACHABLE
+//	2001:db8::2 dev eth0 FAILED
+func parseIPNeigh(out string) []model.Neighbor {
+	var neighbors []model.Neighbor
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		neighbor := model.Neighbor{IP: fields[0]}
+		for i := 1; i < len(fields); i++ {
+			switch fields[i] {
+			case "dev":
+				if i+1 < len(fields) {
+					i++
+					neighbor.Interface = fields[i]
+				}
+			case "lladdr":
+				if i+1 < len(fields) {
+					i++
+					neighbor.MACAddress = fields[i]
+				}
+			case "router":
+				neighbor.Router = true
+			}
+		}
+		// 状态在行尾，如 REACHABLE、STALE、FAILED
+		neighbor.State = fields[len(fields)-1]
+
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
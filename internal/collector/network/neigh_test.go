@@ -0,0 +1,85 @@
+package network
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+const procNetARPFixture = `IP address       HW type     Flags       HW address            Mask     Device
+192.0.2.1        0x1         0x2         02:fc:00:00:00:05     *        eth0
+192.0.2.9        0x1         0x0         00:00:00:00:00:00     *        eth0
+198.51.100.1     0x1         0x6         02:fc:00:00:00:07     *        bond0
+`
+
+func TestParseARP(t *testing.T) {
+	got := parseARP(procNetARPFixture)
+	want := []model.Neighbor{
+		{Interface: "eth0", IP: "192.0.2.1", MACAddress: "02:fc:00:00:00:05", State: "COMPLETE"},
+		// 未解析的表项不输出全零MAC
+		{Interface: "eth0", IP: "192.0.2.9", State: "INCOMPLETE"},
+		{Interface: "bond0", IP: "198.51.100.1", MACAddress: "02:fc:00:00:00:07", State: "PERMANENT"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseARP() = %+v, want %+v", got, want)
+	}
+
+	// 只有表头
+	if got := parseARP("IP address       HW type     Flags       HW address            Mask     Device\n"); got != nil {
+		t.Errorf("parseARP(header) = %+v, want nil", got)
+	}
+}
+
+func TestParseIPNeigh(t *testing.T) {
+	out := `fe80::1 dev eth0 lladdr 00:1c:73:aa:bb:cc router REACHABLE
+2001:db8::2 dev eth0 lladdr 52:54:00:12:34:56 STALE
+2001:db8::3 dev eth1 FAILED
+`
+	got := parseIPNeigh(out)
+	want := []model.Neighbor{
+		{Interface: "eth0", IP: "fe80::1", MACAddress: "00:1c:73:aa:bb:cc", State: "REACHABLE", Router: true},
+		{Interface: "eth0", IP: "2001:db8::2", MACAddress: "52:54:00:12:34:56", State: "STALE"},
+		{Interface: "eth1", IP: "2001:db8::3", State: "FAILED"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseIPNeigh() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCollectNeighborsWithoutIP(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/proc/net/arp": procNetARPFixture})
+	// 未安装 ip 命令时仍返回IPv4邻居
+	testutil.FakeCommands(t, nil)
+
+	got, err := collectNeighbors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].IP != "192.0.2.1" {
+		t.Errorf("collectNeighbors() = %+v, want the ARP table", got)
+	}
+}
+
+func TestCollectNeighbors(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/proc/net/arp": procNetARPFixture})
+	testutil.FakeCommands(t, map[string]string{
+		"ip -6 neigh show": "fe80::1 dev eth0 lladdr 00:1c:73:aa:bb:cc router REACHABLE\n",
+	})
+
+	got, err := collectNeighbors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got[3].IP != "fe80::1" || !got[3].Router {
+		t.Errorf("collectNeighbors() = %+v, want IPv4 and IPv6 neighbors", got)
+	}
+
+	// 读不到 /proc/net/arp 时报错
+	testutil.FakeRoot(t, nil)
+	if _, err := collectNeighbors(context.Background()); err == nil {
+		t.Error("collectNeighbors() succeeded without /proc/net/arp")
+	}
+}
@@ -1,42 +1,284 @@
 package network
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/zenithax-cc/diting/internal/collector/pci"
 	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
 )
 
-const sysfsNet string = "/sys/class/net"
+const (
+	sysfsNet    string = "/sys/class/net"
+	sysfsModule string = "/sys/module"
+)
+
+var (
+	sysfsOnly         bool
+	ignoredInterfaces []*regexp.Regexp
+)
+
+// SetIgnoredInterfaces 设置不采集的网络接口，每项为匹配接口名称的正则表达式，如 "^veth"、"^docker\d+$"，
+// 回环接口 lo 始终不采集。表达式无效时返回错误且不修改已有设置。
+// It is not safe to call concurrently with collection.
+func SetIgnoredInterfaces(patterns []string) error {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid interface pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+
+	ignoredInterfaces = res
+	return nil
+}
 
-func collectNetInterfaces() ([]model.NetInterface, error) {
-	dirs, err := os.ReadDir(sysfsNet)
+// interfaceIgnored 判断接口是否不采集：回环接口 lo，或匹配 SetIgnoredInterfaces 设置的表达式
+func interfaceIgnored(name string) bool {
+	if name == "lo" {
+		return true
+	}
+	for _, re := range ignoredInterfaces {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSysfsOnly 设置是否只从 /sys、/proc 采集网络信息，不执行 ethtool、lldpctl、ip 等外部命令，
+// 用于没有安装这些工具的受限容器。开启后 LLDP、环形缓冲区、通道和网卡特性为空，并记录一条警告。
+// It is not safe to call concurrently with collection.
+func SetSysfsOnly(enabled bool) {
+	sysfsOnly = enabled
+}
+
+// Collect 采集网络接口信息和物理网卡的LLDP信息，并标记被多个接口共用的MAC地址。
+// minimal 采集配置下只读取sysfs中的链路状态，跳过 ethtool、LLDP、中断、邻居表和监听端口
+func Collect(ctx context.Context) (model.Network, error) {
+	var network model.Network
+
+	minimal := utils.IsMinimal(ctx)
+	netInterfaces, err := collectNetInterfaces(ctx, !minimal && !sysfsOnly)
+	if err != nil {
+		return network, err
+	}
+	network.NetInterfaces = netInterfaces
+	network.DetectDuplicateMACs()
+
+	if minimal {
+		return network, ctx.Err()
+	}
+
+	if sysfsOnly {
+		utils.WarningsFrom(ctx).Addf("network", "sysfs-only mode, LLDP, ring buffer, channel and offload features are not collected")
+	}
+
+	content, _ := utils.ReadSysfsFile(utils.HostPath(procInterrupts))
+	interrupts := parseInterrupts(content)
+
+	lldpAvailable := true
+	for _, iface := range netInterfaces {
+		if !isPhysical(iface.DeviceName) {
+			continue
+		}
+
+		phy := model.PhyInterface{
+			DeviceName: iface.DeviceName,
+			IRQs:       collectIRQs(iface.DeviceName, interrupts),
+			PCI:        collectPhyPCI(iface.DeviceName),
+		}
+		if sysfsOnly {
+			network.PhyInterfaces = append(network.PhyInterfaces, phy)
+			continue
+		}
+
+		// 虚拟化网卡等驱动不支持时保持为空
+		phy.RingBuffer, _ = collectRingBuffer(ctx, iface.DeviceName)
+		phy.Channel, _ = collectChannel(ctx, iface.DeviceName)
+		phy.Features, _ = collectFeatures(ctx, iface.DeviceName)
+		phy.Statistics, _ = collectStatistics(ctx, iface.DeviceName)
+		if lldpAvailable && ctx.Err() == nil {
+			lldp, err := collectLLDP(ctx, iface.DeviceName)
+			switch {
+			case err == nil:
+				phy.LLDP = lldp
+			case errors.Is(err, exec.ErrNotFound):
+				// 未安装 lldpd，其他接口也无需再尝试
+				lldpAvailable = false
+			default:
+				utils.WarningsFrom(ctx).Add("network", err)
+			}
+		}
+		network.PhyInterfaces = append(network.PhyInterfaces, phy)
+	}
+
+	// 邻居表和监听端口只是附加信息，读取失败不影响接口信息的采集
+	if network.Neighbors, err = collectNeighbors(ctx); err != nil {
+		utils.WarningsFrom(ctx).Add("network", err)
+	}
+
+	if network.Sockets, err = collectListeningSockets(); err != nil {
+		utils.WarningsFrom(ctx).Add("network", err)
+	}
+
+	return network, ctx.Err()
+}
+
+// isPhysical 判断接口是否为物理网卡，虚拟接口(bond、bridge、veth等)在sysfs中没有device链接
+func isPhysical(name string) bool {
+	_, err := os.Stat(filepath.Join(netSysfsDir(), name, "device"))
+	return err == nil
+}
+
+// collectNetInterfaces 采集各网络接口，ethtool 为 false 时不执行 ethtool，只读取sysfs
+func collectNetInterfaces(ctx context.Context, ethtool bool) ([]model.NetInterface, error) {
+	root := netSysfsDir()
+	dirs, err := os.ReadDir(root)
 	if err != nil {
 		return nil, fmt.Errorf("read directory %s failed: %w", sysfsNet, err)
 	}
 
-	netInterfaces := make([]model.NetInterface, len(dirs))
+	netInterfaces := make([]model.NetInterface, 0, len(dirs))
 	for _, dir := range dirs {
-		if !dir.IsDir() {
+		// /sys/class/net 下的条目是指向 /sys/devices 的符号链接，DirEntry.IsDir 不跟随链接，
+		// 需要 Stat 解析后的路径来判断；bonding_masters 等普通文件、失效链接被跳过
+		if dir.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.Join(root, dir.Name()))
+			if err != nil || !info.IsDir() {
+				continue
+			}
+		} else if !dir.IsDir() {
 			continue
 		}
 
 		dirName := dir.Name()
-		if strings.HasPrefix(dirName, "lo") || strings.HasPrefix(dirName, "loop") {
+		if interfaceIgnored(dirName) {
+			continue
+		}
+
+		if utils.DeviceExcluded(dirName) {
 			continue
 		}
 
-		netInterfaces = append(netInterfaces, collectNetInterface(dirName))
+		netInterfaces = append(netInterfaces, collectNetInterface(ctx, dirName, ethtool))
 	}
 
 	return netInterfaces, nil
 }
 
-func collectNetInterface(name string) model.NetInterface {
+func collectNetInterface(ctx context.Context, name string, ethtool bool) model.NetInterface {
+	dir := filepath.Join(netSysfsDir(), name)
+	read := func(attr string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(dir, attr))
+		return v
+	}
+
 	netInterface := model.NetInterface{
 		DeviceName: name,
+		MACAddress: read("address"),
+		Status:     read("operstate"),
+		Duplex:     read("duplex"),
+		MTU:        read("mtu"),
+	}
+
+	// 接口未连接时sysfs中的speed为-1或读取报错
+	if speed, err := strconv.Atoi(read("speed")); err == nil && speed > 0 {
+		netInterface.SpeedMbps = speed
+		netInterface.Speed = formatSpeed(speed)
+	}
+
+	// 驱动信息优先取 ethtool -i(含固件版本)，不可用时从sysfs读取
+	type driverInfo struct{ driver, version, firmware string }
+	sysfsSource := utils.Source[driverInfo]{Name: "sysfs", Get: func() (driverInfo, error) {
+		driver, version := readSysfsDriver(dir)
+		return driverInfo{driver: driver, version: version}, nil
+	}}
+	ethtoolSource := utils.Source[driverInfo]{Name: "ethtool", Get: func() (driverInfo, error) {
+		info, err := collectDriverInfo(ctx, name)
+		return driverInfo{driver: info.Driver, version: info.Version, firmware: info.FirmwareVersion}, err
+	}}
+
+	sources := []utils.Source[driverInfo]{sysfsSource}
+	if ethtool {
+		sources = []utils.Source[driverInfo]{ethtoolSource, sysfsSource}
+	}
+	drv, source, _ := utils.FirstOf(sources...)
+	netInterface.Driver, netInterface.DriverVersion, netInterface.FirmwareVersion = drv.driver, drv.version, drv.firmware
+	netInterface.DriverSource = source
+
+	if !ethtool {
+		return netInterface
+	}
+
+	if setting, err := collectEthtoolSetting(ctx, name); err == nil {
+		netInterface.Port = setting.Port
+		netInterface.LinkDetected = setting.LinkDetected
+		if netInterface.Duplex == "" {
+			netInterface.Duplex = setting.Duplex
+		}
+
+		netInterface.MaxSpeedMbps = maxSupportedSpeed(setting.SupportedModes)
+		netInterface.LinkDegraded = netInterface.SpeedMbps > 0 && netInterface.SpeedMbps < netInterface.MaxSpeedMbps
 	}
 
 	return netInterface
 }
+
+// readSysfsDriver 从sysfs读取接口绑定的驱动名称和驱动模块版本，内置驱动或虚拟接口没有版本时为空
+func readSysfsDriver(dir string) (driver, version string) {
+	target, err := filepath.EvalSymlinks(filepath.Join(dir, "device", "driver"))
+	if err != nil {
+		return "", ""
+	}
+
+	driver = filepath.Base(target)
+	version, _ = utils.ReadSysfsFile(filepath.Join(utils.HostPath(sysfsModule), driver, "version"))
+	return driver, version
+}
+
+// collectPhyPCI 从sysfs读取物理网卡所在PCI设备的地址、厂商和设备ID、NUMA节点和链路信息，
+// 非PCI设备(如USB网卡)返回空值
+func collectPhyPCI(name string) model.PCI {
+	target, err := filepath.EvalSymlinks(filepath.Join(netSysfsDir(), name, "device"))
+	if err != nil {
+		return model.PCI{}
+	}
+	if subsystem, err := filepath.EvalSymlinks(filepath.Join(target, "subsystem")); err != nil || filepath.Base(subsystem) != "pci" {
+		return model.PCI{}
+	}
+
+	read := func(attr string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(target, attr))
+		return strings.TrimPrefix(v, "0x")
+	}
+
+	addr := filepath.Base(target)
+	return model.PCI{
+		PCIAddr:     addr,
+		VendorID:    read("vendor"),
+		DeviceID:    read("device"),
+		SubVendorID: read("subsystem_vendor"),
+		SubDeviceID: read("subsystem_device"),
+		Numa:        pci.ReadNuma(addr),
+		Link:        pci.ReadLink(addr),
+	}
+}
+
+// formatSpeed 将以Mb/s为单位的速率转换为易读格式，如 10000 为 "10 Gb/s"，2500 为 "2.5 Gb/s"
+func formatSpeed(mbps int) string {
+	if mbps < 1000 {
+		return fmt.Sprintf("%d Mb/s", mbps)
+	}
+	return strconv.FormatFloat(float64(mbps)/1000, 'f', -1, 64) + " Gb/s"
+}
@@ -0,0 +1,341 @@
+package network
+
+import (
+	"context"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCollectNetInterfacesExcluded(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/class/net/lo/address":   "00:00:00:00:00:00\n",
+		"/sys/class/net/eth0/address": "52:54:00:12:34:56\n",
+		"/sys/class/net/eth1/address": "52:54:00:12:34:57\n",
+	})
+	utils.SetExcludedDevices([]string{"eth1"})
+	t.Cleanup(func() { utils.SetExcludedDevices(nil) })
+
+	nics, err := collectNetInterfaces(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nics) != 1 || nics[0].DeviceName != "eth0" {
+		t.Errorf("interfaces = %+v, want only eth0", nics)
+	}
+}
+
+const ethtoolSettings = `Settings for eth0:
+	Supported ports: [ FIBRE ]
+	Supported link modes:   1000baseT/Full
+	                        10000baseT/Full
+	Speed: 1000Mb/s
+	Duplex: Full
+	Port: FIBRE
+	Link detected: yes
+`
+
+func TestCollectNetInterfaceLinkDegraded(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/class/net/eth0/speed":     "1000\n",
+		"/sys/class/net/eth0/operstate": "up\n",
+	})
+	testutil.FakeCommands(t, map[string]string{"ethtool eth0": ethtoolSettings})
+
+	nic := collectNetInterface(context.Background(), "eth0", true)
+	if nic.Speed != "1 Gb/s" || nic.SpeedMbps != 1000 || nic.MaxSpeedMbps != 10000 {
+		t.Errorf("speed = %q (%d Mb/s), max %d Mb/s", nic.Speed, nic.SpeedMbps, nic.MaxSpeedMbps)
+	}
+	if !nic.LinkDegraded {
+		t.Error("10G NIC linked at 1G is not reported as degraded")
+	}
+	if nic.Port != "FIBRE" || nic.LinkDetected != "yes" || nic.Duplex != "Full" {
+		t.Errorf("ethtool settings = %+v", nic)
+	}
+}
+
+func TestCollectNetInterfaceLinkDown(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/sys/class/net/eth0/speed": "-1\n"})
+	testutil.FakeCommands(t, map[string]string{"ethtool eth0": ethtoolSettings})
+
+	nic := collectNetInterface(context.Background(), "eth0", true)
+	if nic.Speed != "" || nic.SpeedMbps != 0 || nic.LinkDegraded {
+		t.Errorf("link down interface = %+v, want no speed and not degraded", nic)
+	}
+}
+
+func TestCollectNetInterfaceUsesContext(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/sys/class/net/eth0/speed": "1000\n"})
+
+	// ethtool 的每次调用都使用采集的 ctx,采集超时或取消时随之结束
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "network")
+	var calls []string
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if ctx.Value(ctxKey{}) != "network" {
+			calls = append(calls, strings.Join(append([]string{name}, args...), " "))
+		}
+		return nil, exec.ErrNotFound
+	})
+	t.Cleanup(func() { executor.SetRunner(nil) })
+
+	collectNetInterface(ctx, "eth0", true)
+	if len(calls) != 0 {
+		t.Errorf("commands run without the collection context: %v", calls)
+	}
+}
+
+func TestFormatSpeed(t *testing.T) {
+	tests := map[int]string{
+		100:    "100 Mb/s",
+		1000:   "1 Gb/s",
+		2500:   "2.5 Gb/s",
+		25000:  "25 Gb/s",
+		100000: "100 Gb/s",
+	}
+	for mbps, want := range tests {
+		if got := formatSpeed(mbps); got != want {
+			t.Errorf("formatSpeed(%d) = %q, want %q", mbps, got, want)
+		}
+	}
+}
+
+func TestMaxSupportedSpeed(t *testing.T) {
+	modes := []string{"10baseT/Half", "1000baseT/Full", "25000baseSR/Full", "Autoneg"}
+	if got := maxSupportedSpeed(modes); got != 25000 {
+		t.Errorf("maxSupportedSpeed() = %d, want 25000", got)
+	}
+	if got := maxSupportedSpeed(nil); got != 0 {
+		t.Errorf("maxSupportedSpeed(nil) = %d, want 0", got)
+	}
+}
+
+func TestCollectNetInterfacesFollowsSymlinks(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		"/sys/devices/pci0000:00/0000:00:03.0/net/eth0/address": "52:54:00:12:34:56\n",
+		"/sys/devices/virtual/net/br0/address":                  "52:54:00:12:34:57\n",
+		"/sys/class/net/bonding_masters":                        "bond0\n",
+	})
+	// /sys/class/net 下的接口是指向 /sys/devices 的符号链接
+	testutil.Symlink(t, root, "../../devices/pci0000:00/0000:00:03.0/net/eth0", "/sys/class/net/eth0")
+	testutil.Symlink(t, root, "../../devices/virtual/net/br0", "/sys/class/net/br0")
+	testutil.Symlink(t, root, "../../devices/virtual/net/gone", "/sys/class/net/gone")
+
+	nics, err := collectNetInterfaces(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, nic := range nics {
+		got[nic.DeviceName] = nic.MACAddress
+	}
+	want := map[string]string{"br0": "52:54:00:12:34:57", "eth0": "52:54:00:12:34:56"}
+	if !maps.Equal(got, want) {
+		t.Errorf("interfaces = %v, want %v", got, want)
+	}
+}
+
+func TestCollectFeatures(t *testing.T) {
+	testutil.FakeCommands(t, map[string]string{
+		"ethtool -k eth0": `Features for eth0:
+tcp-segmentation-offload: on
+generic-receive-offload: off
+large-receive-offload: off [fixed]
+`,
+		"ethtool -k veth0": "Features for veth0:\n",
+	})
+
+	got, err := collectFeatures(context.Background(), "eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]model.NetFeature{
+		"tcp-segmentation-offload": {Enabled: true},
+		"generic-receive-offload":  {},
+		"large-receive-offload":    {Fixed: true},
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("collectFeatures() = %v, want %v", got, want)
+	}
+
+	// 没有任何特性时不输出空映射
+	if got, err := collectFeatures(context.Background(), "veth0"); err != nil || got != nil {
+		t.Errorf("collectFeatures(veth0) = %v, %v, want nil", got, err)
+	}
+	if _, err := collectFeatures(context.Background(), "eth1"); err == nil {
+		t.Error("collectFeatures() succeeded when ethtool failed")
+	}
+}
+
+func TestCollectSysfsOnly(t *testing.T) {
+	const dev = "/sys/devices/pci0000:00/0000:3b:00.0"
+	root := testutil.FakeRoot(t, map[string]string{
+		dev + "/vendor":                    "0x8086\n",
+		dev + "/device":                    "0x10fb\n",
+		dev + "/net/eth0/address":          "52:54:00:12:34:56\n",
+		dev + "/net/eth0/mtu":              "9000\n",
+		dev + "/net/eth0/operstate":        "up\n",
+		dev + "/net/eth0/speed":            "10000\n",
+		"/sys/bus/pci/drivers/ixgbe/bind":  "",
+		"/sys/module/ixgbe/version":        "5.1.0-k\n",
+		"/sys/devices/virtual/net/br0/mtu": "1500\n",
+		"/proc/net/arp":                    procNetARPFixture,
+	})
+	testutil.Symlink(t, root, "../../devices/pci0000:00/0000:3b:00.0/net/eth0", "/sys/class/net/eth0")
+	testutil.Symlink(t, root, "../../devices/virtual/net/br0", "/sys/class/net/br0")
+	testutil.Symlink(t, root, "../..", dev+"/net/eth0/device")
+	testutil.Symlink(t, root, "../../../bus/pci", dev+"/subsystem")
+	testutil.Symlink(t, root, "../../../bus/pci/drivers/ixgbe", dev+"/driver")
+
+	// 所有外部命令都不存在
+	var ran []string
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		ran = append(ran, name)
+		return nil, exec.ErrNotFound
+	})
+	t.Cleanup(func() { executor.SetRunner(nil) })
+
+	SetSysfsOnly(true)
+	t.Cleanup(func() { SetSysfsOnly(false) })
+
+	warnings := &utils.Warnings{}
+	network, err := Collect(utils.WithWarnings(context.Background(), warnings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("ran %v in sysfs-only mode", ran)
+	}
+
+	if len(network.NetInterfaces) != 2 {
+		t.Fatalf("interfaces = %+v, want br0 and eth0", network.NetInterfaces)
+	}
+	eth0 := network.NetInterfaces[1]
+	if eth0.DeviceName != "eth0" || eth0.MACAddress != "52:54:00:12:34:56" || eth0.MTU != "9000" ||
+		eth0.Status != "up" || eth0.SpeedMbps != 10000 || eth0.Driver != "ixgbe" || eth0.DriverVersion != "5.1.0-k" || eth0.DriverSource != "sysfs" {
+		t.Errorf("eth0 = %+v", eth0)
+	}
+
+	if len(network.PhyInterfaces) != 1 {
+		t.Fatalf("physical interfaces = %+v, want eth0", network.PhyInterfaces)
+	}
+	phy := network.PhyInterfaces[0]
+	if phy.PCI.PCIAddr != "0000:3b:00.0" || phy.PCI.VendorID != "8086" || phy.PCI.DeviceID != "10fb" {
+		t.Errorf("PCI = %+v", phy.PCI)
+	}
+	if phy.LLDP != (model.LLDP{}) || phy.RingBuffer != (model.RingBuffer{}) || phy.Channel != (model.Channel{}) || phy.Features != nil || phy.Statistics != nil {
+		t.Errorf("tool-derived fields = %+v, want them empty", phy)
+	}
+
+	if len(network.Neighbors) == 0 {
+		t.Error("no ARP neighbors read from /proc")
+	}
+	list := warnings.List()
+	if len(list) != 1 || !strings.Contains(list[0].Message, "sysfs-only") {
+		t.Errorf("warnings = %v, want the sysfs-only notice", list)
+	}
+}
+
+func TestCollectNetInterfacesIgnored(t *testing.T) {
+	files := make(map[string]string)
+	for _, name := range []string{"lo", "loom0", "logical0", "eth0", "veth1a2b", "docker0", "docker0x"} {
+		files["/sys/class/net/"+name+"/address"] = "52:54:00:12:34:56\n"
+	}
+	testutil.FakeRoot(t, files)
+
+	names := func() []string {
+		t.Helper()
+		nics, err := collectNetInterfaces(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, nic := range nics {
+			got = append(got, nic.DeviceName)
+		}
+		return got
+	}
+
+	// 只按名称精确跳过 lo
+	if got, want := names(), []string{"docker0", "docker0x", "eth0", "logical0", "loom0", "veth1a2b"}; !slices.Equal(got, want) {
+		t.Errorf("interfaces = %q, want %q", got, want)
+	}
+
+	if err := SetIgnoredInterfaces([]string{"^veth", `^docker\d+$`}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetIgnoredInterfaces(nil) })
+	if got, want := names(), []string{"docker0x", "eth0", "logical0", "loom0"}; !slices.Equal(got, want) {
+		t.Errorf("interfaces with ignore patterns = %q, want %q", got, want)
+	}
+
+	// 表达式无效时保留已有设置
+	if err := SetIgnoredInterfaces([]string{"^eth", "("}); err == nil {
+		t.Error("SetIgnoredInterfaces() with an invalid pattern succeeded")
+	}
+	if got := names(); !slices.Contains(got, "eth0") || slices.Contains(got, "veth1a2b") {
+		t.Errorf("interfaces after a rejected pattern = %q, want the previous patterns kept", got)
+	}
+}
+
+func TestCollectStatistics(t *testing.T) {
+	testutil.FakeCommands(t, map[string]string{
+		"ethtool -S eth0":  "NIC statistics:\n     rx_packets: 1385769\n     rx_missed_errors: 4\n",
+		"ethtool -S veth0": "NIC statistics:\n",
+	})
+
+	got, err := collectStatistics(context.Background(), "eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]uint64{"rx_packets": 1385769, "rx_missed_errors": 4}; !maps.Equal(got, want) {
+		t.Errorf("collectStatistics() = %v, want %v", got, want)
+	}
+
+	// 没有计数器或驱动不支持时跳过
+	if got, err := collectStatistics(context.Background(), "veth0"); err != nil || got != nil {
+		t.Errorf("collectStatistics(veth0) = %v, %v, want nil", got, err)
+	}
+	if got, err := collectStatistics(context.Background(), "eth1"); err == nil || got != nil {
+		t.Errorf("collectStatistics(eth1) = %v, %v, want an error", got, err)
+	}
+}
+
+func TestCollectNetInterfaceDriverSource(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		"/sys/class/net/eth0/address":     "52:54:00:12:34:56\n",
+		"/sys/bus/pci/drivers/ixgbe/bind": "",
+		"/sys/module/ixgbe/version":       "5.1.0-k\n",
+	})
+	testutil.Symlink(t, root, "../../../../bus/pci/drivers/ixgbe", "/sys/class/net/eth0/device/driver")
+
+	// ethtool -i 失败时退回sysfs
+	testutil.FakeCommands(t, nil)
+	nic := collectNetInterface(context.Background(), "eth0", true)
+	if nic.Driver != "ixgbe" || nic.DriverVersion != "5.1.0-k" || nic.FirmwareVersion != "" || nic.DriverSource != "sysfs" {
+		t.Errorf("driver = %s %s %s from %q, want ixgbe 5.1.0-k from sysfs", nic.Driver, nic.DriverVersion, nic.FirmwareVersion, nic.DriverSource)
+	}
+
+	testutil.FakeCommands(t, map[string]string{
+		"ethtool -i eth0": "driver: ixgbe\nversion: 5.1.0-k\nfirmware-version: 0x800007b8\nbus-info: 0000:3b:00.0\n",
+	})
+	nic = collectNetInterface(context.Background(), "eth0", true)
+	if nic.Driver != "ixgbe" || nic.FirmwareVersion != "0x800007b8" || nic.DriverSource != "ethtool" {
+		t.Errorf("driver = %s %s %s from %q, want the ethtool result", nic.Driver, nic.DriverVersion, nic.FirmwareVersion, nic.DriverSource)
+	}
+
+	// 两者都取不到时不记录来源
+	testutil.FakeRoot(t, map[string]string{"/sys/class/net/eth0/address": "52:54:00:12:34:56\n"})
+	testutil.FakeCommands(t, nil)
+	if nic := collectNetInterface(context.Background(), "eth0", true); nic.Driver != "" || nic.DriverSource != "" {
+		t.Errorf("driver = %q from %q, want none", nic.Driver, nic.DriverSource)
+	}
+}
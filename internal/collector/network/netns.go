@@ -0,0 +1,58 @@
+package network
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	nsenterCmd string = "nsenter"
+	hostNetns  string = "/proc/1/ns/net" // 宿主机 init 进程的网络命名空间
+)
+
+var (
+	hostNamespace bool
+	hostSysfs     string
+)
+
+// SetHostNamespace 设置在容器中运行时是否采集宿主机网络命名空间的接口，而不是容器自身的接口。
+// 开启后：
+//   - 接口从 sysfsDir/class/net 读取，sysfsDir 为以只读方式挂载到容器中的宿主机 /sys(如 -v /sys:/host/sys:ro)，
+//     容器内挂载的 /sys 只显示容器网络命名空间中的接口；sysfsDir 为空时读取 /sys，适用于 hostNetwork 的容器
+//   - ARP 表和监听端口从 /proc/1/net 读取，需要与宿主机共享 PID 命名空间(--pid=host)
+//   - ethtool、ip 通过 nsenter --net=/proc/1/ns/net 在宿主机网络命名空间中执行，需要 CAP_SYS_ADMIN，
+//     配置了命令白名单时需要加入 nsenter
+//
+// It is not safe to call concurrently with collection.
+func SetHostNamespace(enabled bool, sysfsDir string) {
+	hostNamespace = enabled
+	hostSysfs = sysfsDir
+}
+
+// netSysfsDir 返回网络接口所在的 sysfs 目录
+func netSysfsDir() string {
+	if hostNamespace && hostSysfs != "" {
+		return filepath.Join(hostSysfs, "class", "net")
+	}
+	return utils.HostPath(sysfsNet)
+}
+
+// procNetPath 返回 /proc/net 下文件的路径，宿主机模式下改为读取宿主机 init 进程的 /proc/1/net
+func procNetPath(path string) string {
+	if hostNamespace {
+		path = filepath.Join("/proc/1/net", strings.TrimPrefix(path, "/proc/net/"))
+	}
+	return utils.HostPath(path)
+}
+
+// runNetCommand 执行与网络命名空间相关的命令，宿主机模式下通过 nsenter 在宿主机网络命名空间中执行
+func runNetCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if hostNamespace {
+		return executor.ExecuteWithContext(ctx, nsenterCmd, append([]string{"--net=" + hostNetns, "--", name}, args...)...)
+	}
+	return executor.ExecuteWithContext(ctx, name, args...)
+}
@@ -0,0 +1,88 @@
+package network
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+// setHostNamespace 在测试期间开启宿主机网络命名空间模式
+func setHostNamespace(t *testing.T, sysfsDir string) {
+	t.Helper()
+	SetHostNamespace(true, sysfsDir)
+	t.Cleanup(func() { SetHostNamespace(false, "") })
+}
+
+func TestCollectNetInterfacesHostNamespace(t *testing.T) {
+	// 容器内的 /sys 只有容器自身的 veth 对端
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/class/net/eth0/address":   "0a:58:0a:f4:00:05\n",
+		"/sys/class/net/eth0/operstate": "up\n",
+	})
+	// 以只读方式挂载到容器中的宿主机 /sys
+	hostSys := t.TempDir()
+	for path, content := range map[string]string{
+		"class/net/eno1/address":   "b4:96:91:aa:bb:01\n",
+		"class/net/eno1/mtu":       "9000\n",
+		"class/net/eno1/operstate": "up\n",
+		"class/net/eno2/address":   "b4:96:91:aa:bb:02\n",
+		"class/net/eno2/operstate": "down\n",
+	} {
+		testutil.WriteFile(t, hostSys, path, content)
+	}
+	if err := os.Mkdir(filepath.Join(hostSys, "class/net/eno1/device"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := collectNetInterfaces(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].DeviceName != "eth0" {
+		t.Fatalf("container interfaces = %+v, want eth0", got)
+	}
+
+	setHostNamespace(t, hostSys)
+	got, err = collectNetInterfaces(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].DeviceName != "eno1" || got[1].DeviceName != "eno2" {
+		t.Fatalf("host interfaces = %+v, want eno1 and eno2", got)
+	}
+	if got[0].MACAddress != "b4:96:91:aa:bb:01" || got[0].MTU != "9000" || got[1].Status != "down" {
+		t.Errorf("host interfaces = %+v", got)
+	}
+	if !isPhysical("eno1") || isPhysical("eth0") {
+		t.Error("isPhysical() does not read the host sysfs")
+	}
+
+	// 未配置宿主机 sysfs 时读取 /sys,适用于 hostNetwork 的容器
+	SetHostNamespace(true, "")
+	if got, err := collectNetInterfaces(context.Background(), false); err != nil || len(got) != 1 || got[0].DeviceName != "eth0" {
+		t.Errorf("collectNetInterfaces() = %+v, %v, want eth0 from /sys", got, err)
+	}
+}
+
+func TestCollectNeighborsHostNamespace(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/proc/net/arp":   "IP address       HW type     Flags       HW address            Mask     Device\n",
+		"/proc/1/net/arp": procNetARPFixture,
+	})
+	// ip 命令通过 nsenter 在宿主机网络命名空间中执行
+	testutil.FakeCommands(t, map[string]string{
+		"nsenter --net=/proc/1/ns/net -- ip -6 neigh show": "fe80::1 dev eno1 lladdr 00:1c:73:aa:bb:cc router REACHABLE\n",
+	})
+	setHostNamespace(t, "")
+
+	got, err := collectNeighbors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got[0].IP != "192.0.2.1" || got[3].Interface != "eno1" {
+		t.Errorf("collectNeighbors() = %+v, want the host ARP and IPv6 neighbors", got)
+	}
+}
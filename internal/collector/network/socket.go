@@ -0,0 +1,176 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const procDir string = "/proc"
+
+// 内核 include/net/tcp_states.h 中的套接字状态
+const (
+	tcpListen = 0x0A // TCP_LISTEN
+	udpUnconn = 0x07 // TCP_CLOSE，未connect的UDP套接字即为监听状态
+)
+
+// socketTables 需要解析的套接字表及其中表示监听的状态
+var socketTables = []struct {
+	protocol string
+	path     string
+	state    uint64
+}{
+	{"tcp", "/proc/net/tcp", tcpListen},
+	{"tcp6", "/proc/net/tcp6", tcpListen},
+	{"udp", "/proc/net/udp", udpUnconn},
+	{"udp6", "/proc/net/udp6", udpUnconn},
+}
+
+// collectListeningSockets 采集监听的TCP/UDP套接字，并尽量关联所属进程。
+// 读取其他进程的 /proc/<pid>/fd 需要root权限，无权限时进程信息为空
+func collectListeningSockets() ([]model.Socket, error) {
+	var sockets []model.Socket
+	for _, table := range socketTables {
+		content, err := utils.ReadSysfsFile(procNetPath(table.path))
+		if err != nil {
+			// 内核未启用IPv6时没有 tcp6/udp6
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s failed: %w", table.path, err)
+		}
+		sockets = append(sockets, parseSocketTable(content, table.protocol, table.state)...)
+	}
+
+	if len(sockets) == 0 {
+		return sockets, nil
+	}
+
+	owners := socketOwners()
+	for i := range sockets {
+		if owner, ok := owners[sockets[i].Inode]; ok {
+			sockets[i].PID = owner.pid
+			sockets[i].Process = owner.comm
+		}
+	}
+
+	return sockets, nil
+}
+
+// parseSocketTable 解析 /proc/net/{tcp,tcp6,udp,udp6}，只返回处于 state 状态的套接字。
+// 地址为十六进制的网络字节序按32位字以主机字节序(小端)打印的结果，端口为十六进制的主机序：
+//
+//	sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+//	 0: 0100007F:0035 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1057 1 ...
+func parseSocketTable(content, protocol string, state uint64) []model.Socket {
+	var sockets []model.Socket
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] == "sl" {
+			continue
+		}
+
+		st, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil || st != state {
+			continue
+		}
+
+		addr, port, err := decodeSocketAddr(fields[1])
+		if err != nil {
+			continue
+		}
+
+		sockets = append(sockets, model.Socket{
+			Protocol: protocol,
+			Address:  addr,
+			Port:     port,
+			UID:      fields[7],
+			Inode:    fields[9],
+		})
+	}
+
+	return sockets
+}
+
+// decodeSocketAddr 解码 "0100007F:0035" 形式的地址，IPv4为8位、IPv6为32位十六进制
+func decodeSocketAddr(s string) (string, int, error) {
+	hexIP, hexPort, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid socket address: %s", s)
+	}
+
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid socket port: %s", s)
+	}
+
+	raw, err := hex.DecodeString(hexIP)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", 0, fmt.Errorf("invalid socket ip: %s", s)
+	}
+
+	// 每个32位字是以小端打印的，逐字翻转得到网络字节序
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], binary.LittleEndian.Uint32(raw[i:]))
+	}
+
+	return ip.String(), int(port), nil
+}
+
+type socketOwner struct {
+	pid  string
+	comm string
+}
+
+// socketOwners 遍历 /proc/<pid>/fd，返回套接字inode到所属进程的映射，无法读取的进程被跳过
+func socketOwners() map[string]socketOwner {
+	owners := make(map[string]socketOwner)
+
+	root := utils.HostPath(procDir)
+	procs, err := os.ReadDir(root)
+	if err != nil {
+		return owners
+	}
+
+	for _, proc := range procs {
+		pid := proc.Name()
+		if _, err := strconv.Atoi(pid); err != nil {
+			continue
+		}
+
+		fds, err := os.ReadDir(filepath.Join(root, pid, "fd"))
+		if err != nil {
+			continue
+		}
+
+		var comm string
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(root, pid, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+
+			if comm == "" {
+				comm, _ = utils.ReadSysfsFile(filepath.Join(root, pid, "comm"))
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			owners[inode] = socketOwner{pid: pid, comm: comm}
+		}
+	}
+
+	return owners
+}
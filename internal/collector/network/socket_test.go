@@ -0,0 +1,81 @@
+package network
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestDecodeSocketAddr(t *testing.T) {
+	tests := []struct {
+		in   string
+		addr string
+		port int
+	}{
+		{"0100007F:0035", "127.0.0.1", 53},
+		{"00000000:0016", "0.0.0.0", 22},
+		{"0A01A8C0:1F90", "192.168.1.10", 8080},
+		{"00000000000000000000000001000000:0016", "::1", 22},
+		{"00000000000000000000000000000000:01BB", "::", 443},
+		{"000080FE000000000000000001000000:0222", "fe80::1", 546},
+		{"0000000000000000FFFF00000100007F:0019", "127.0.0.1", 25},
+	}
+	for _, tt := range tests {
+		addr, port, err := decodeSocketAddr(tt.in)
+		if err != nil || addr != tt.addr || port != tt.port {
+			t.Errorf("decodeSocketAddr(%q) = %q, %d, %v, want %q, %d", tt.in, addr, port, err, tt.addr, tt.port)
+		}
+	}
+
+	for _, in := range []string{"0100007F", "0100007F:ZZZZ", "0100007F:10000", "01007F:0035", "000080FE00000000000000000100000:0222"} {
+		if _, _, err := decodeSocketAddr(in); err == nil {
+			t.Errorf("decodeSocketAddr(%q) succeeded", in)
+		}
+	}
+}
+
+const procNetTCPFixture = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0035 00000000:0000 0A 00000000:00000000 00:00000000 00000000   101        0 1057 1 0000000000000000 100 0 0 10 0
+   1: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2211 1 0000000000000000 100 0 0 10 0
+   2: 0A01A8C0:0016 0B01A8C0:C350 01 00000000:00000000 02:000A7B14 00000000     0        0 9012 4 0000000000000000 20 4 30 10 -1
+`
+
+func TestParseSocketTable(t *testing.T) {
+	got := parseSocketTable(procNetTCPFixture, "tcp", tcpListen)
+	want := []model.Socket{
+		{Protocol: "tcp", Address: "127.0.0.1", Port: 53, UID: "101", Inode: "1057"},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, UID: "0", Inode: "2211"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseSocketTable() = %+v, want the listening sockets %+v", got, want)
+	}
+}
+
+func TestCollectListeningSockets(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		"/proc/net/tcp": procNetTCPFixture,
+		"/proc/net/udp": `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  412: 00000000:0044 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 3301 2 0000000000000000 0
+`,
+		// 没有 tcp6、udp6:内核未启用IPv6
+		"/proc/812/comm":   "sshd\n",
+		"/proc/self/.keep": "",
+	})
+	testutil.Symlink(t, root, "socket:[2211]", "/proc/812/fd/3")
+	testutil.Symlink(t, root, "/dev/null", "/proc/812/fd/0")
+
+	got, err := collectListeningSockets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Socket{
+		{Protocol: "tcp", Address: "127.0.0.1", Port: 53, UID: "101", Inode: "1057"},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, UID: "0", Inode: "2211", PID: "812", Process: "sshd"},
+		{Protocol: "udp", Address: "0.0.0.0", Port: 68, UID: "0", Inode: "3301"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("collectListeningSockets() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package pci
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// AER 计数文件及其中的汇总项
+var aerFiles = []struct {
+	file  string
+	total string
+}{
+	{file: "aer_dev_correctable", total: "TOTAL_ERR_COR"},
+	{file: "aer_dev_fatal", total: "TOTAL_ERR_FATAL"},
+	{file: "aer_dev_nonfatal", total: "TOTAL_ERR_NONFATAL"},
+}
+
+// ReadAER 从sysfs读取PCIe设备的AER(Advanced Error Reporting)错误计数，
+// 设备或内核不支持AER时返回空值
+func ReadAER(addr string) model.PCIAER {
+	dir := filepath.Join(utils.HostPath(sysfsPCIDevices), addr)
+
+	var totals [3]string
+	for i, f := range aerFiles {
+		content, err := utils.ReadSysfsFile(filepath.Join(dir, f.file))
+		if err != nil {
+			continue
+		}
+		totals[i] = parseAERTotal(content, f.total)
+	}
+
+	return model.PCIAER{
+		Correctable: totals[0],
+		Fatal:       totals[1],
+		NonFatal:    totals[2],
+	}
+}
+
+// parseAERTotal 解析 aer_dev_* 文件，每行为 "错误类型 计数"，返回汇总项的计数：
+//
+//	RxErr 0
+//	BadTLP 2
+//	TOTAL_ERR_COR 2
+func parseAERTotal(content, total string) string {
+	return utils.ParseKeyValue(content, " ")[total]
+}
+
+// AERDegraded 判断设备是否出现过不可纠正(致命或非致命)的AER错误，
+// 可纠正错误由链路层重传恢复，不视为降级
+func AERDegraded(aer model.PCIAER) bool {
+	fatal, _ := strconv.ParseUint(aer.Fatal, 10, 64)
+	nonFatal, _ := strconv.ParseUint(aer.NonFatal, 10, 64)
+	return fatal > 0 || nonFatal > 0
+}
+
+// ScanAER 遍历全部PCI设备，返回支持AER的设备及其错误计数，Degraded 标记出现过不可纠正错误的设备
+func ScanAER() ([]model.PCI, error) {
+	entries, err := os.ReadDir(utils.HostPath(sysfsPCIDevices))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read directory %s failed: %w", sysfsPCIDevices, err)
+	}
+
+	var devices []model.PCI
+	for _, entry := range entries {
+		addr := entry.Name()
+		if utils.DeviceExcluded(addr) {
+			continue
+		}
+
+		aer := ReadAER(addr)
+		if aer == (model.PCIAER{}) {
+			continue
+		}
+		devices = append(devices, model.PCI{
+			PCIAddr:  addr,
+			AER:      aer,
+			Degraded: AERDegraded(aer),
+		})
+	}
+
+	return devices, nil
+}
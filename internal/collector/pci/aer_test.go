@@ -0,0 +1,89 @@
+package pci
+
+import (
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	aerCorrectable = "RxErr 0\nBadTLP 2\nBadDLLP 1\nRollover 0\nTimeout 0\nNonFatalErr 0\nCorrIntErr 0\nHeaderOF 0\nTOTAL_ERR_COR 3\n"
+	aerFatalClean  = "Undefined 0\nDLP 0\nSDES 0\nTLP 0\nFCP 0\nCmpltTO 0\nTOTAL_ERR_FATAL 0\n"
+	aerNonFatal    = "Undefined 0\nCmpltTO 0\nUnxCmplt 1\nTOTAL_ERR_NONFATAL 1\n"
+)
+
+func TestScanAER(t *testing.T) {
+	const dir = "/sys/bus/pci/devices/"
+	testutil.FakeRoot(t, map[string]string{
+		// 出现过可纠正错误的网卡
+		dir + "0000:3b:00.0/aer_dev_correctable": aerCorrectable,
+		dir + "0000:3b:00.0/aer_dev_fatal":       aerFatalClean,
+		dir + "0000:3b:00.0/aer_dev_nonfatal":    "TOTAL_ERR_NONFATAL 0\n",
+		// 出现过不可纠正错误的 NVMe
+		dir + "0000:5e:00.0/aer_dev_correctable": "TOTAL_ERR_COR 0\n",
+		dir + "0000:5e:00.0/aer_dev_fatal":       aerFatalClean,
+		dir + "0000:5e:00.0/aer_dev_nonfatal":    aerNonFatal,
+		// 不支持 AER 的桥
+		dir + "0000:00:00.0/vendor": "0x8086\n",
+	})
+
+	devices, err := ScanAER()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.PCI{
+		{PCIAddr: "0000:3b:00.0", AER: model.PCIAER{Correctable: "3", Fatal: "0", NonFatal: "0"}},
+		{PCIAddr: "0000:5e:00.0", AER: model.PCIAER{Correctable: "0", Fatal: "0", NonFatal: "1"}, Degraded: true},
+	}
+	if len(devices) != len(want) {
+		t.Fatalf("ScanAER() = %+v, want %+v", devices, want)
+	}
+	for i := range want {
+		if devices[i].PCIAddr != want[i].PCIAddr || devices[i].AER != want[i].AER || devices[i].Degraded != want[i].Degraded {
+			t.Errorf("device %d = %+v, want %+v", i, devices[i], want[i])
+		}
+	}
+
+	// 排除的设备不读取
+	utils.SetExcludedDevices([]string{"0000:5e:00.0"})
+	t.Cleanup(func() { utils.SetExcludedDevices(nil) })
+	devices, err = ScanAER()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].PCIAddr != "0000:3b:00.0" {
+		t.Errorf("ScanAER() with an excluded device = %+v", devices)
+	}
+}
+
+func TestScanAERNoSysfs(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	devices, err := ScanAER()
+	if err != nil || devices != nil {
+		t.Errorf("ScanAER() without sysfs = %+v, %v, want nothing", devices, err)
+	}
+	if got := ReadAER("0000:3b:00.0"); got != (model.PCIAER{}) {
+		t.Errorf("ReadAER() of a missing device = %+v, want empty", got)
+	}
+}
+
+func TestAERDegraded(t *testing.T) {
+	tests := []struct {
+		aer  model.PCIAER
+		want bool
+	}{
+		{model.PCIAER{Correctable: "120", Fatal: "0", NonFatal: "0"}, false},
+		{model.PCIAER{Fatal: "1"}, true},
+		{model.PCIAER{NonFatal: "4"}, true},
+		{model.PCIAER{}, false},
+		{model.PCIAER{Fatal: "n/a"}, false},
+	}
+	for _, tt := range tests {
+		if got := AERDegraded(tt.aer); got != tt.want {
+			t.Errorf("AERDegraded(%+v) = %v, want %v", tt.aer, got, tt.want)
+		}
+	}
+}
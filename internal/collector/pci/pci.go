@@ -0,0 +1,76 @@
+package pci
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysfsPCIDevices string = "/sys/bus/pci/devices"
+
+// ReadLink 从sysfs读取PCI设备的最大和当前链路速率、宽度
+func ReadLink(addr string) model.PCILink {
+	dir := filepath.Join(utils.HostPath(sysfsPCIDevices), addr)
+	read := func(name string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(dir, name))
+		return v
+	}
+
+	return model.PCILink{
+		MaxSpeed:  read("max_link_speed"),
+		MaxWidth:  read("max_link_width"),
+		CurrSpeed: read("current_link_speed"),
+		CurrWidth: read("current_link_width"),
+	}
+}
+
+// ReadNuma 从sysfs读取PCI设备所属的NUMA节点，-1表示设备未绑定NUMA节点
+func ReadNuma(addr string) string {
+	numa, _ := utils.ReadSysfsFile(filepath.Join(utils.HostPath(sysfsPCIDevices), addr, "numa_node"))
+	return numa
+}
+
+// LinkDegraded 判断PCIe链路是否降级，即当前速率或宽度低于设备支持的最大值
+func LinkDegraded(link model.PCILink) bool {
+	maxSpeed, ok1 := parseLinkSpeed(link.MaxSpeed)
+	curSpeed, ok2 := parseLinkSpeed(link.CurrSpeed)
+	if ok1 && ok2 && curSpeed < maxSpeed {
+		return true
+	}
+
+	maxWidth, err1 := strconv.Atoi(link.MaxWidth)
+	curWidth, err2 := strconv.Atoi(link.CurrWidth)
+	return err1 == nil && err2 == nil && curWidth < maxWidth
+}
+
+// parseLinkSpeed 解析形如 "8.0 GT/s PCIe" 的链路速率，返回 GT/s 数值
+func parseLinkSpeed(s string) (float64, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// NormalizeBusID 将 nvidia-smi 等工具输出的 "00000000:3B:00.0" 格式总线地址
+// 转换为sysfs使用的 "0000:3b:00.0" 格式
+func NormalizeBusID(busID string) string {
+	busID = strings.ToLower(strings.TrimSpace(busID))
+
+	domain, rest, ok := strings.Cut(busID, ":")
+	if !ok {
+		return busID
+	}
+	if len(domain) > 4 {
+		domain = domain[len(domain)-4:]
+	}
+	return domain + ":" + rest
+}
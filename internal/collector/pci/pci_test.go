@@ -0,0 +1,63 @@
+package pci
+
+import (
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestNormalizeBusID(t *testing.T) {
+	tests := map[string]string{
+		"00000000:3B:00.0": "0000:3b:00.0",
+		"0000:af:00.1":     "0000:af:00.1",
+		" 0001:01:00.0 ":   "0001:01:00.0",
+		"3b:00.0":          "3b:00.0",
+	}
+	for in, want := range tests {
+		if got := NormalizeBusID(in); got != want {
+			t.Errorf("NormalizeBusID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLinkDegraded(t *testing.T) {
+	tests := []struct {
+		name string
+		link model.PCILink
+		want bool
+	}{
+		{"full", model.PCILink{MaxSpeed: "16.0 GT/s PCIe", CurrSpeed: "16.0 GT/s PCIe", MaxWidth: "16", CurrWidth: "16"}, false},
+		{"slow", model.PCILink{MaxSpeed: "16.0 GT/s PCIe", CurrSpeed: "2.5 GT/s PCIe", MaxWidth: "16", CurrWidth: "16"}, true},
+		{"narrow", model.PCILink{MaxSpeed: "8.0 GT/s", CurrSpeed: "8.0 GT/s", MaxWidth: "16", CurrWidth: "8"}, true},
+		{"unknown", model.PCILink{MaxSpeed: "Unknown", CurrSpeed: "2.5 GT/s"}, false},
+		{"empty", model.PCILink{}, false},
+	}
+	for _, tt := range tests {
+		if got := LinkDegraded(tt.link); got != tt.want {
+			t.Errorf("%s: LinkDegraded() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadLinkAndNuma(t *testing.T) {
+	dev := "/sys/bus/pci/devices/0000:3b:00.0/"
+	testutil.FakeRoot(t, map[string]string{
+		dev + "max_link_speed":     "16.0 GT/s PCIe\n",
+		dev + "max_link_width":     "16\n",
+		dev + "current_link_speed": "8.0 GT/s PCIe\n",
+		dev + "current_link_width": "16\n",
+		dev + "numa_node":          "1\n",
+	})
+
+	want := model.PCILink{MaxSpeed: "16.0 GT/s PCIe", MaxWidth: "16", CurrSpeed: "8.0 GT/s PCIe", CurrWidth: "16"}
+	if got := ReadLink("0000:3b:00.0"); got != want {
+		t.Errorf("ReadLink() = %+v, want %+v", got, want)
+	}
+	if got := ReadNuma("0000:3b:00.0"); got != "1" {
+		t.Errorf("ReadNuma() = %q, want 1", got)
+	}
+	if got := ReadNuma("0000:00:00.0"); got != "" {
+		t.Errorf("ReadNuma() of a missing device = %q, want empty", got)
+	}
+}
@@ -0,0 +1,55 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysfsPowerSupply string = "/sys/class/power_supply"
+
+// Collect 采集/sys/class/power_supply下的电源和电池状态，没有任何电源设备时返回空结果
+func Collect() ([]model.PowerSupply, error) {
+	dirs, err := os.ReadDir(utils.HostPath(sysfsPowerSupply))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read directory %s failed: %w", sysfsPowerSupply, err)
+	}
+
+	supplies := make([]model.PowerSupply, 0, len(dirs))
+	for _, dir := range dirs {
+		if utils.DeviceExcluded(dir.Name()) {
+			continue
+		}
+		supplies = append(supplies, collectPowerSupply(dir.Name()))
+	}
+
+	return supplies, nil
+}
+
+func collectPowerSupply(name string) model.PowerSupply {
+	dir := filepath.Join(utils.HostPath(sysfsPowerSupply), name)
+	read := func(attr string) string {
+		v, _ := utils.ReadSysfsFile(filepath.Join(dir, attr))
+		return v
+	}
+
+	return model.PowerSupply{
+		Name:         name,
+		Type:         read("type"),
+		Online:       read("online"),
+		Present:      read("present"),
+		Status:       read("status"),
+		Capacity:     read("capacity"),
+		Health:       read("health"),
+		Manufacturer: read("manufacturer"),
+		ModelName:    read("model_name"),
+	}
+}
@@ -0,0 +1,53 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCollect(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/class/power_supply/AC/type":              "Mains\n",
+		"/sys/class/power_supply/AC/online":            "1\n",
+		"/sys/class/power_supply/BAT0/type":            "Battery\n",
+		"/sys/class/power_supply/BAT0/present":         "1\n",
+		"/sys/class/power_supply/BAT0/status":          "Charging\n",
+		"/sys/class/power_supply/BAT0/capacity":        "87\n",
+		"/sys/class/power_supply/BAT0/health":          "Good\n",
+		"/sys/class/power_supply/BAT0/manufacturer":    "SMP\n",
+		"/sys/class/power_supply/BAT0/model_name":      "5B10W13975\n",
+		"/sys/class/power_supply/hidpp_battery_0/type": "Battery\n",
+	})
+	utils.SetExcludedDevices([]string{"hidpp_battery_0"})
+	t.Cleanup(func() { utils.SetExcludedDevices(nil) })
+
+	supplies, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []model.PowerSupply{
+		{Name: "AC", Type: "Mains", Online: "1"},
+		{Name: "BAT0", Type: "Battery", Present: "1", Status: "Charging", Capacity: "87", Health: "Good", Manufacturer: "SMP", ModelName: "5B10W13975"},
+	}
+	if len(supplies) != len(want) {
+		t.Fatalf("got %d power supplies, want %d: %+v", len(supplies), len(want), supplies)
+	}
+	for i := range want {
+		if supplies[i] != want[i] {
+			t.Errorf("supplies[%d] = %+v, want %+v", i, supplies[i], want[i])
+		}
+	}
+}
+
+func TestCollectWithoutPowerSupply(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	supplies, err := Collect()
+	if err != nil || len(supplies) != 0 {
+		t.Fatalf("Collect() = %v, %v, want an empty result", supplies, err)
+	}
+}
@@ -0,0 +1,48 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysfsDMI string = "/sys/class/dmi/id"
+
+// Collect 从/sys/class/dmi/id读取产品、主板和BIOS信息，
+// 需要root权限的文件(如product_serial、chassis_serial)读取失败时跳过，并记录到权限警告中
+func Collect() (model.Product, error) {
+	if _, err := os.Stat(utils.HostPath(sysfsDMI)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return model.Product{}, nil
+		}
+		return model.Product{}, fmt.Errorf("stat %s failed: %w", sysfsDMI, err)
+	}
+
+	return model.Product{
+		SysVendor:       readDMI("sys_vendor"),
+		ProductName:     readDMI("product_name"),
+		ProductSerial:   readDMI("product_serial"),
+		ProductUUID:     readDMI("product_uuid"),
+		BoardVendor:     readDMI("board_vendor"),
+		BoardName:       readDMI("board_name"),
+		ChassisType:     readDMI("chassis_type"),
+		ChassisSerial:   readDMI("chassis_serial"),
+		ChassisAssetTag: readDMI("chassis_asset_tag"),
+		BIOS: model.BIOS{
+			Vendor:  readDMI("bios_vendor"),
+			Version: readDMI("bios_version"),
+			Date:    readDMI("bios_date"),
+		},
+	}, nil
+}
+
+// readDMI 尽力读取DMI属性，文件不存在或无权限时返回空字符串，无权限时由 utils.ReadSysfsFile 记录权限警告
+func readDMI(name string) string {
+	v, _ := utils.ReadSysfsFile(filepath.Join(utils.HostPath(sysfsDMI), name))
+	return v
+}
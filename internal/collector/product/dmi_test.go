@@ -0,0 +1,115 @@
+package product
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCollect(t *testing.T) {
+	root := testutil.FakeRoot(t, map[string]string{
+		"/sys/class/dmi/id/sys_vendor":   "Dell Inc.\n",
+		"/sys/class/dmi/id/product_name": "PowerEdge R750\n",
+		"/sys/class/dmi/id/board_name":   "0PJ3VX\n",
+		"/sys/class/dmi/id/chassis_type": "23\n",
+		"/sys/class/dmi/id/bios_vendor":  "Dell Inc.\n",
+		"/sys/class/dmi/id/bios_version": "1.10.2\n",
+		"/sys/class/dmi/id/bios_date":    "04/12/2023\n",
+	})
+	// 无法读取的序列号文件
+	if err := os.Mkdir(filepath.Join(root, "/sys/class/dmi/id/product_serial"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := model.Product{
+		SysVendor:   "Dell Inc.",
+		ProductName: "PowerEdge R750",
+		BoardName:   "0PJ3VX",
+		ChassisType: "23",
+		BIOS:        model.BIOS{Vendor: "Dell Inc.", Version: "1.10.2", Date: "04/12/2023"},
+	}
+	if got != want {
+		t.Errorf("Collect() = %+v, want %+v", got, want)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "product_serial") {
+		t.Errorf("unreadable serial present in output: %s", data)
+	}
+}
+
+func TestCollectWithoutDMI(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	got, err := Collect()
+	if err != nil || got != (model.Product{}) {
+		t.Fatalf("Collect() = %+v, %v, want an empty product", got, err)
+	}
+}
+
+func TestCollectSerials(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		"/sys/class/dmi/id/product_name":      "PowerEdge R750\n",
+		"/sys/class/dmi/id/product_serial":    "7XK4LM3\n",
+		"/sys/class/dmi/id/product_uuid":      "4c4c4544-0058-4b10-8034-b7c04f4c4d33\n",
+		"/sys/class/dmi/id/chassis_serial":    "7XK4LM3\n",
+		"/sys/class/dmi/id/chassis_asset_tag": "IT-000123\n",
+	})
+
+	got, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ProductSerial != "7XK4LM3" || got.ProductUUID != "4c4c4544-0058-4b10-8034-b7c04f4c4d33" ||
+		got.ChassisSerial != "7XK4LM3" || got.ChassisAssetTag != "IT-000123" {
+		t.Errorf("Collect() = %+v, want the serials and asset tag", got)
+	}
+}
+
+func TestCollectSerialsPermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files regardless of their mode")
+	}
+	root := testutil.FakeRoot(t, map[string]string{
+		"/sys/class/dmi/id/product_name":   "PowerEdge R750\n",
+		"/sys/class/dmi/id/product_serial": "7XK4LM3\n",
+		"/sys/class/dmi/id/chassis_serial": "7XK4LM3\n",
+	})
+	// 与真实sysfs相同,序列号文件只有root可读
+	for _, name := range []string{"product_serial", "chassis_serial"} {
+		if err := os.Chmod(filepath.Join(root, "sys/class/dmi/id", name), 0o000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = utils.TakePermissionWarnings()
+
+	got, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ProductName != "PowerEdge R750" || got.ProductSerial != "" || got.ChassisSerial != "" {
+		t.Errorf("Collect() = %+v, want the readable fields only", got)
+	}
+	want := []string{
+		filepath.Join(root, "sys/class/dmi/id/chassis_serial"),
+		filepath.Join(root, "sys/class/dmi/id/product_serial"),
+	}
+	if got := utils.TakePermissionWarnings(); !slices.Equal(got, want) {
+		t.Errorf("permission warnings = %q, want %q", got, want)
+	}
+}
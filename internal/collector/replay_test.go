@@ -0,0 +1,180 @@
+package collector
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/replay"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// testdata/host 是一台带GPU的主机的采集目录，命令输出和sysfs文件的命名见 replay 包文档
+func TestCollectReplaysCapturedHost(t *testing.T) {
+	if err := replay.Enable("testdata/host"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(replay.Disable)
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := c.LastStats().Errors; len(errs) != 0 {
+		t.Errorf("module errors: %v", errs)
+	}
+	if w := c.Warnings(); len(w) != 0 {
+		t.Errorf("warnings: %v", w)
+	}
+
+	if info.Product == nil || info.Product.ProductName != "PowerEdge R750" {
+		t.Errorf("product = %+v", info.Product)
+	}
+	if info.System == nil || info.System.Kernel.Release != "6.8.0-45-generic" || info.System.LoadAverage.Load1 != "0.52" {
+		t.Errorf("system = %+v", info.System)
+	}
+	if info.Memory == nil || info.Memory.Total != 65843212*1024 || len(info.Memory.DIMMs) != 1 {
+		t.Errorf("memory = %+v", info.Memory)
+	}
+	if info.Disk == nil || len(info.Disk.BlockDevices) != 1 || info.Disk.BlockDevices[0].Model != "ST1000NM0033" {
+		t.Errorf("disk = %+v", info.Disk)
+	}
+	if info.Network == nil || len(info.Network.NetInterfaces) != 1 {
+		t.Fatalf("network = %+v", info.Network)
+	}
+	if eth0 := info.Network.NetInterfaces[0]; eth0.MACAddress != "52:54:00:12:34:56" || eth0.Driver != "virtio_net" || eth0.SpeedMbps != 10000 {
+		t.Errorf("eth0 = %+v", eth0)
+	}
+	if len(info.GPU) != 1 || info.GPU[0].PCI.PCIAddr != "0000:3b:00.0" || !info.GPU[0].NVLink {
+		t.Errorf("gpu = %+v", info.GPU)
+	}
+	if len(info.Sensors) != 1 || info.Sensors[0].Value != 45 {
+		t.Errorf("sensors = %+v", info.Sensors)
+	}
+}
+
+func TestCollectMinimalProfile(t *testing.T) {
+	if err := replay.Enable("testdata/host"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(replay.Disable)
+
+	// 记录回放期间执行的命令
+	var commands []string
+	next := executor.Runner()
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		return next(ctx, name, args...)
+	})
+
+	c := newTestCollector(t)
+	c.SetProfile(utils.ProfileMinimal)
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(c.LastStats().Modules, []string{"system", "memory", "network"}) {
+		t.Errorf("modules = %v, want only the minimal ones", c.LastStats().Modules)
+	}
+	if info.Product != nil || info.Disk != nil || info.GPU != nil {
+		t.Errorf("collected modules outside the minimal profile: %+v", info)
+	}
+	if info.System == nil || info.System.LoadAverage.Load1 != "0.52" || info.Memory == nil || info.Memory.Total == 0 {
+		t.Errorf("system = %+v, memory = %+v", info.System, info.Memory)
+	}
+	if len(info.Memory.DIMMs) != 0 {
+		t.Errorf("DIMMs = %+v, want dmidecode skipped", info.Memory.DIMMs)
+	}
+	if info.Network == nil || len(info.Network.NetInterfaces) != 1 || info.Network.PhyInterfaces != nil {
+		t.Errorf("network = %+v, want link status only", info.Network)
+	}
+	if len(commands) != 0 {
+		t.Errorf("ran %q, want no external commands", commands)
+	}
+
+	// 显式指定模块时仍采集,但各模块跳过开销大的探测
+	info, err = c.Collect(context.Background(), []string{"gpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.GPU) != 1 || info.GPU[0].NVLink {
+		t.Errorf("gpu = %+v, want the GPU without NVLink probing", info.GPU)
+	}
+	if slices.ContainsFunc(commands, func(c string) bool { return strings.HasPrefix(c, "nvidia-smi nvlink") }) {
+		t.Errorf("ran %q in the minimal profile", commands)
+	}
+}
+
+// packBundle 将采集目录 dir 打包为 host/ 目录下的 .tar.gz 回放包
+func packBundle(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "host.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: "host/" + filepath.ToSlash(rel), Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCollectReplaysBundle(t *testing.T) {
+	if err := replay.Enable(packBundle(t, "testdata/host")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(replay.Disable)
+	c := newTestCollector(t)
+
+	info, err := c.Collect(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := c.LastStats().Errors; len(errs) != 0 {
+		t.Errorf("module errors: %v", errs)
+	}
+	if info.Product == nil || info.Product.ProductName != "PowerEdge R750" {
+		t.Errorf("product = %+v", info.Product)
+	}
+	if info.Memory == nil || info.Memory.Total != 65843212*1024 || len(info.Memory.DIMMs) != 1 {
+		t.Errorf("memory = %+v", info.Memory)
+	}
+	if len(info.GPU) != 1 || !info.GPU[0].NVLink {
+		t.Errorf("gpu = %+v", info.GPU)
+	}
+}
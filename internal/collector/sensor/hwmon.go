@@ -0,0 +1,107 @@
+package sensor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysfsHwmon string = "/sys/class/hwmon"
+
+// sensorKind 描述一类hwmon输入文件及其换算方式
+type sensorKind struct {
+	prefix string  // 文件名前缀，如 temp、fan
+	typ    string  // 传感器类型
+	unit   string  // 换算后的单位
+	scale  float64 // 原始值除以该系数得到换算后的值
+}
+
+var sensorKinds = []sensorKind{
+	{prefix: "temp", typ: "temperature", unit: "°C", scale: 1000}, // 毫摄氏度
+	{prefix: "fan", typ: "fan", unit: "RPM", scale: 1},
+}
+
+// Collect 遍历/sys/class/hwmon下的所有芯片，采集温度和风扇传感器读数
+func Collect() ([]model.Sensor, error) {
+	dirs, err := os.ReadDir(utils.HostPath(sysfsHwmon))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read directory %s failed: %w", sysfsHwmon, err)
+	}
+
+	var sensors []model.Sensor
+	for _, dir := range dirs {
+		if !strings.HasPrefix(dir.Name(), "hwmon") {
+			continue
+		}
+		sensors = append(sensors, collectChip(filepath.Join(utils.HostPath(sysfsHwmon), dir.Name()))...)
+	}
+
+	return sensors, nil
+}
+
+// collectChip 采集单个hwmon芯片的传感器，部分旧驱动将属性文件放在device子目录下
+func collectChip(dir string) []model.Sensor {
+	chip, _ := utils.ReadSysfsFile(filepath.Join(dir, "name"))
+
+	attrDir := dir
+	inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+	if len(inputs) == 0 {
+		attrDir = filepath.Join(dir, "device")
+		inputs, _ = filepath.Glob(filepath.Join(attrDir, "*_input"))
+		if chip == "" {
+			chip, _ = utils.ReadSysfsFile(filepath.Join(attrDir, "name"))
+		}
+	}
+	slices.Sort(inputs)
+
+	var sensors []model.Sensor
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), "_input")
+
+		kind, ok := matchKind(name)
+		if !ok {
+			continue
+		}
+
+		raw, err := utils.ReadSysfsInt(input)
+		if err != nil {
+			continue
+		}
+
+		label, err := utils.ReadSysfsFile(filepath.Join(attrDir, name+"_label"))
+		if err != nil || label == "" {
+			label = name
+		}
+
+		sensors = append(sensors, model.Sensor{
+			Chip:  chip,
+			Label: label,
+			Type:  kind.typ,
+			Value: float64(raw) / kind.scale,
+			Unit:  kind.unit,
+		})
+	}
+
+	return sensors
+}
+
+// matchKind 根据输入名称(如 temp1、fan2)匹配传感器类型
+func matchKind(name string) (sensorKind, bool) {
+	for _, kind := range sensorKinds {
+		idx, ok := strings.CutPrefix(name, kind.prefix)
+		if ok && idx != "" && strings.Trim(idx, "0123456789") == "" {
+			return kind, true
+		}
+	}
+	return sensorKind{}, false
+}
@@ -0,0 +1,70 @@
+package sensor
+
+import (
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestCollect(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		// 带标签的温度芯片
+		"/sys/class/hwmon/hwmon0/name":        "coretemp\n",
+		"/sys/class/hwmon/hwmon0/temp1_input": "45000\n",
+		"/sys/class/hwmon/hwmon0/temp1_label": "Package id 0\n",
+		"/sys/class/hwmon/hwmon0/temp2_input": "41500\n",
+		"/sys/class/hwmon/hwmon0/temp2_label": "Core 0\n",
+		// 无标签芯片，属性位于device子目录
+		"/sys/class/hwmon/hwmon1/device/name":       "nct6775\n",
+		"/sys/class/hwmon/hwmon1/device/fan1_input": "1200\n",
+		"/sys/class/hwmon/hwmon1/device/in0_input":  "1024\n",
+		// 非hwmon目录应被忽略
+		"/sys/class/hwmon/other/temp1_input": "1\n",
+	})
+
+	sensors, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []model.Sensor{
+		{Chip: "coretemp", Label: "Package id 0", Type: "temperature", Value: 45, Unit: "°C"},
+		{Chip: "coretemp", Label: "Core 0", Type: "temperature", Value: 41.5, Unit: "°C"},
+		{Chip: "nct6775", Label: "fan1", Type: "fan", Value: 1200, Unit: "RPM"},
+	}
+	if len(sensors) != len(want) {
+		t.Fatalf("got %d sensors, want %d: %+v", len(sensors), len(want), sensors)
+	}
+	for i := range want {
+		if sensors[i] != want[i] {
+			t.Errorf("sensors[%d] = %+v, want %+v", i, sensors[i], want[i])
+		}
+	}
+}
+
+func TestCollectWithoutHwmon(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	sensors, err := Collect()
+	if err != nil || sensors != nil {
+		t.Fatalf("Collect() = %v, %v, want no sensors and no error", sensors, err)
+	}
+}
+
+func TestMatchKind(t *testing.T) {
+	tests := map[string]string{
+		"temp1":  "temperature",
+		"temp12": "temperature",
+		"fan3":   "fan",
+		"temp":   "",
+		"tempx":  "",
+		"in0":    "",
+	}
+	for name, want := range tests {
+		kind, _ := matchKind(name)
+		if kind.typ != want {
+			t.Errorf("matchKind(%q) = %q, want %q", name, kind.typ, want)
+		}
+	}
+}
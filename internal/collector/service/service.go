@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	systemctl      string = "systemctl"
+	systemdRunDir  string = "/run/systemd/system" // 与 sd_booted(3) 相同，存在即表示 systemd 为 init 系统
+	systemdTimeout        = 10 * time.Second
+)
+
+// Collect 采集失败的 systemd unit，init 系统不是 systemd 时返回空值
+func Collect(ctx context.Context) (model.Services, error) {
+	if _, err := os.Stat(utils.HostPath(systemdRunDir)); err != nil {
+		return model.Services{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, systemdTimeout)
+	defer cancel()
+
+	out, err := executor.ExecuteWithContext(ctx, systemctl, "--failed", "--no-legend", "--plain")
+	if err != nil {
+		return model.Services{}, fmt.Errorf("run %s --failed failed: %w", systemctl, err)
+	}
+
+	units := parseFailedUnits(string(out))
+	return model.Services{
+		Manager:     "systemd",
+		FailedCount: len(units),
+		FailedUnits: units,
+	}, nil
+}
+
+// parseFailedUnits 解析 systemctl --failed --no-legend --plain 输出，每行第一列为 unit 名称：
+//
+//	nginx.service      loaded failed failed A high performance web server
+//	systemd-networkd-wait-online.service loaded failed failed Wait for Network to be Configured
+//
+// 旧版本 systemd 即使指定 --plain 仍可能在行首输出 "●" 标记，需要去掉
+func parseFailedUnits(out string) []string {
+	var units []string
+	for line := range strings.Lines(out) {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "●" {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		units = append(units, fields[0])
+	}
+	return units
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+const failedUnits = `nginx.service                        loaded failed failed A high performance web server
+systemd-networkd-wait-online.service loaded failed failed Wait for Network to be Configured
+`
+
+func TestParseFailedUnits(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []string
+	}{
+		{"plain", failedUnits, []string{"nginx.service", "systemd-networkd-wait-online.service"}},
+		{"bullet", "● backup.timer loaded failed failed Nightly backup\n●   kdump.service loaded failed failed Crash recovery\n", []string{"backup.timer", "kdump.service"}},
+		{"none failed", "", nil},
+		{"blank lines", "\n  \n", nil},
+	}
+	for _, tt := range tests {
+		if got := parseFailedUnits(tt.out); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: parseFailedUnits() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{"/run/systemd/system/.keep": ""})
+	testutil.FakeCommands(t, map[string]string{"systemctl --failed --no-legend --plain": failedUnits})
+
+	got, err := Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.Services{
+		Manager:     "systemd",
+		FailedCount: 2,
+		FailedUnits: []string{"nginx.service", "systemd-networkd-wait-online.service"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Collect() = %+v, want %+v", got, want)
+	}
+
+	// systemctl 执行失败时返回错误
+	testutil.FakeCommands(t, nil)
+	if _, err := Collect(context.Background()); err == nil {
+		t.Error("Collect() succeeded when systemctl failed")
+	}
+}
+
+func TestCollectWithoutSystemd(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+	testutil.FakeCommands(t, nil)
+
+	got, err := Collect(context.Background())
+	if err != nil || !reflect.DeepEqual(got, model.Services{}) {
+		t.Errorf("Collect() without systemd = %+v, %v, want empty", got, err)
+	}
+}
@@ -0,0 +1,29 @@
+package system
+
+import (
+	"path/filepath"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	procRandomDir string = "/proc/sys/kernel/random"
+	sysHWRandom   string = "/sys/class/misc/hw_random/rng_current"
+)
+
+// collectEntropy 采集内核熵池和硬件随机数源。没有 hw_random 设备(未加载驱动或无硬件RNG)时
+// 该文件不存在，内容为 none 时同样视为没有硬件随机数源
+func collectEntropy() model.Entropy {
+	var entropy model.Entropy
+	entropy.Available, _ = utils.ReadSysfsFile(utils.HostPath(filepath.Join(procRandomDir, "entropy_avail")))
+	entropy.PoolSize, _ = utils.ReadSysfsFile(utils.HostPath(filepath.Join(procRandomDir, "poolsize")))
+
+	entropy.HWRNGPresent = "false"
+	if rng, err := utils.ReadSysfsFile(utils.HostPath(sysHWRandom)); err == nil && rng != "" && rng != "none" {
+		entropy.HWRNG = rng
+		entropy.HWRNGPresent = "true"
+	}
+
+	return entropy
+}
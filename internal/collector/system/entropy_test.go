@@ -0,0 +1,49 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestCollectEntropy(t *testing.T) {
+	const (
+		entropyAvail = procRandomDir + "/entropy_avail"
+		poolSize     = procRandomDir + "/poolsize"
+	)
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  model.Entropy
+	}{
+		{
+			name:  "hardware rng",
+			files: map[string]string{entropyAvail: "3754\n", poolSize: "4096\n", sysHWRandom: "tpm-rng-0\n"},
+			want:  model.Entropy{Available: "3754", PoolSize: "4096", HWRNG: "tpm-rng-0", HWRNGPresent: "true"},
+		},
+		{
+			// 没有 hw_random 设备时该文件不存在
+			name:  "no hw_random",
+			files: map[string]string{entropyAvail: "256\n", poolSize: "256\n"},
+			want:  model.Entropy{Available: "256", PoolSize: "256", HWRNGPresent: "false"},
+		},
+		{
+			name:  "rng none",
+			files: map[string]string{entropyAvail: "180\n", poolSize: "4096\n", sysHWRandom: "none\n"},
+			want:  model.Entropy{Available: "180", PoolSize: "4096", HWRNGPresent: "false"},
+		},
+		{
+			name: "nothing readable",
+			want: model.Entropy{HWRNGPresent: "false"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.FakeRoot(t, tt.files)
+			if got := collectEntropy(); got != tt.want {
+				t.Errorf("collectEntropy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
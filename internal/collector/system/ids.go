@@ -0,0 +1,25 @@
+package system
+
+import "github.com/zenithax-cc/diting/pkg/utils"
+
+const procBootID string = "/proc/sys/kernel/random/boot_id"
+
+// machineIDFiles machine-id 的位置，/etc/machine-id 不存在时(如未使用 systemd 的旧系统)使用 dbus 的副本
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// collectBootID 读取本次启动的随机标识，每次重启后变化，读取失败返回空字符串
+func collectBootID() string {
+	id, _ := utils.ReadSysfsFile(utils.HostPath(procBootID))
+	return id
+}
+
+// collectMachineID 读取机器标识，主机名变更后保持不变。文件都不存在或为空时返回空字符串，
+// 首次启动尚未生成时 systemd 写入的 "uninitialized" 同样视为没有
+func collectMachineID() string {
+	for _, path := range machineIDFiles {
+		if id, err := utils.ReadSysfsFile(utils.HostPath(path)); err == nil && id != "" && id != "uninitialized" {
+			return id
+		}
+	}
+	return ""
+}
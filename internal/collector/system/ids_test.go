@@ -0,0 +1,58 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestCollectBootID(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{procBootID: "0e5c0f8e-3f1a-4d6b-9c2e-7a1b2c3d4e5f\n"})
+	if got := collectBootID(); got != "0e5c0f8e-3f1a-4d6b-9c2e-7a1b2c3d4e5f" {
+		t.Errorf("collectBootID() = %q", got)
+	}
+
+	testutil.FakeRoot(t, nil)
+	if got := collectBootID(); got != "" {
+		t.Errorf("collectBootID() without the file = %q, want empty", got)
+	}
+}
+
+func TestCollectMachineID(t *testing.T) {
+	const (
+		etcID  = "/etc/machine-id"
+		dbusID = "/var/lib/dbus/machine-id"
+	)
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "etc",
+			files: map[string]string{etcID: "4c4c4544004e3510804bc4c04f4d4d32\n", dbusID: "ffffffffffffffffffffffffffffffff\n"},
+			want:  "4c4c4544004e3510804bc4c04f4d4d32",
+		},
+		{
+			name:  "dbus fallback",
+			files: map[string]string{dbusID: "a1b2c3d4e5f60718293a4b5c6d7e8f90\n"},
+			want:  "a1b2c3d4e5f60718293a4b5c6d7e8f90",
+		},
+		{
+			// 首次启动尚未生成
+			name:  "uninitialized",
+			files: map[string]string{etcID: "uninitialized\n", dbusID: "a1b2c3d4e5f60718293a4b5c6d7e8f90\n"},
+			want:  "a1b2c3d4e5f60718293a4b5c6d7e8f90",
+		},
+		{name: "empty", files: map[string]string{etcID: "\n"}},
+		{name: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.FakeRoot(t, tt.files)
+			if got := collectMachineID(); got != tt.want {
+				t.Errorf("collectMachineID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	procOSRelease string = "/proc/sys/kernel/osrelease"
+	procVersion   string = "/proc/version"
+	procCmdline   string = "/proc/cmdline"
+	procModules   string = "/proc/modules"
+)
+
+// collectKernel 采集内核版本、启动参数，withModules 为 true 时同时采集已加载模块
+func collectKernel(withModules bool) (model.Kernel, error) {
+	var kernel model.Kernel
+
+	release, err := utils.ReadSysfsFile(utils.HostPath(procOSRelease))
+	if err != nil {
+		return kernel, fmt.Errorf("read %s failed: %w", procOSRelease, err)
+	}
+	kernel.Release = release
+
+	kernel.Version, _ = utils.ReadSysfsFile(utils.HostPath(procVersion))
+	kernel.CmdLine, _ = utils.ReadSysfsFile(utils.HostPath(procCmdline))
+
+	if !withModules {
+		return kernel, nil
+	}
+
+	// 未启用模块支持的内核没有/proc/modules
+	if modules, err := utils.ReadSysfsFile(utils.HostPath(procModules)); err == nil {
+		kernel.Modules = parseModules(modules)
+	}
+
+	return kernel, nil
+}
+
+// parseModules 解析/proc/modules，每行格式为：
+//
+//	name size refcount deps state offset
+//	nf_tables 249856 183 nft_chain_nat,nft_compat, Live 0x0000000000000000
+func parseModules(text string) []model.KernelModule {
+	var modules []model.KernelModule
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		module := model.KernelModule{
+			Name:     fields[0],
+			Size:     fields[1],
+			RefCount: fields[2],
+			State:    fields[4],
+		}
+		if fields[3] != "-" {
+			for dep := range strings.SplitSeq(fields[3], ",") {
+				if dep != "" {
+					module.UsedBy = append(module.UsedBy, dep)
+				}
+			}
+		}
+
+		modules = append(modules, module)
+	}
+
+	return modules
+}
@@ -0,0 +1,67 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+const procModulesFixture = `nf_tables 249856 183 nft_chain_nat,nft_compat, Live 0x0000000000000000
+ixgbe 385024 0 - Live 0x0000000000000000
+mlx5_core 1486848 1 mlx5_ib, Loading 0x0000000000000000
+broken line
+`
+
+func TestCollectKernel(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		procOSRelease: "6.8.0-45-generic\n",
+		procVersion:   "Linux version 6.8.0-45-generic (buildd@lcy02-amd64-075) #45-Ubuntu SMP\n",
+		procCmdline:   "BOOT_IMAGE=/vmlinuz-6.8.0-45-generic root=UUID=1234 ro quiet iommu=pt\n",
+		procModules:   procModulesFixture,
+	})
+
+	kernel, err := collectKernel(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kernel.Release != "6.8.0-45-generic" {
+		t.Errorf("Release = %q", kernel.Release)
+	}
+	if kernel.CmdLine != "BOOT_IMAGE=/vmlinuz-6.8.0-45-generic root=UUID=1234 ro quiet iommu=pt" {
+		t.Errorf("CmdLine = %q", kernel.CmdLine)
+	}
+
+	want := []model.KernelModule{
+		{Name: "nf_tables", Size: "249856", RefCount: "183", UsedBy: []string{"nft_chain_nat", "nft_compat"}, State: "Live"},
+		{Name: "ixgbe", Size: "385024", RefCount: "0", State: "Live"},
+		{Name: "mlx5_core", Size: "1486848", RefCount: "1", UsedBy: []string{"mlx5_ib"}, State: "Loading"},
+	}
+	if !reflect.DeepEqual(kernel.Modules, want) {
+		t.Errorf("Modules = %+v, want %+v", kernel.Modules, want)
+	}
+
+	// 不采集模块时跳过/proc/modules
+	kernel, err = collectKernel(false)
+	if err != nil || kernel.Modules != nil {
+		t.Errorf("collectKernel(false) = %+v, %v, want no modules", kernel, err)
+	}
+}
+
+func TestCollectKernelWithoutModuleSupport(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{procOSRelease: "6.8.0\n"})
+
+	kernel, err := collectKernel(true)
+	if err != nil || kernel.Release != "6.8.0" || kernel.Modules != nil {
+		t.Errorf("collectKernel() = %+v, %v", kernel, err)
+	}
+}
+
+func TestCollectKernelWithoutRelease(t *testing.T) {
+	testutil.FakeRoot(t, nil)
+
+	if _, err := collectKernel(true); err == nil {
+		t.Error("collectKernel() without osrelease succeeded")
+	}
+}
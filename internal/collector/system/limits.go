@@ -0,0 +1,75 @@
+package system
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	procDir    string = "/proc"
+	procPIDMax string = "/proc/sys/kernel/pid_max"
+	procFileNr string = "/proc/sys/fs/file-nr"
+)
+
+// rlimInfinity 表示不限制的 rlimit 值(RLIM_INFINITY)
+const rlimInfinity = ^uint64(0)
+
+// collectLimits 采集进程数、文件句柄的使用量和上限，以及采集进程自身的打开文件数限制，读取失败的项保持为空。
+// countPIDs 为 false 时不遍历/proc统计进程数
+func collectLimits(countPIDs bool) model.Limits {
+	var limits model.Limits
+
+	limits.PIDMax, _ = utils.ReadSysfsFile(utils.HostPath(procPIDMax))
+	if countPIDs {
+		if count, err := countProcesses(); err == nil {
+			limits.PIDCount = strconv.Itoa(count)
+		}
+	}
+
+	if content, err := utils.ReadSysfsFile(utils.HostPath(procFileNr)); err == nil {
+		limits.FileHandles, limits.FileHandlesMax = parseFileNr(content)
+	}
+
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err == nil {
+		limits.NoFileSoftLimit = formatRlimit(rlim.Cur)
+		limits.NoFileHardLimit = formatRlimit(rlim.Max)
+	}
+
+	return limits
+}
+
+// parseFileNr 解析/proc/sys/fs/file-nr，三列依次为已分配、已分配但未使用(2.6内核后恒为0)和上限：
+//
+//	289	0	612769
+func parseFileNr(content string) (allocated, max string) {
+	line := utils.FirstLine(content)
+	return utils.Field(line, 1), utils.Field(line, 3)
+}
+
+// countProcesses 统计/proc下以数字命名的进程目录数
+func countProcesses() (int, error) {
+	entries, err := os.ReadDir(utils.HostPath(procDir))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func formatRlimit(v uint64) string {
+	if v == rlimInfinity {
+		return "unlimited"
+	}
+	return strconv.FormatUint(v, 10)
+}
@@ -0,0 +1,66 @@
+package system
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestParseFileNr(t *testing.T) {
+	tests := []struct {
+		content, allocated, max string
+	}{
+		{"289\t0\t612769\n", "289", "612769"},
+		{"12768  0  9223372036854775807", "12768", "9223372036854775807"},
+		{"", "", ""},
+		{"289\n", "289", ""},
+	}
+	for _, tt := range tests {
+		allocated, max := parseFileNr(tt.content)
+		if allocated != tt.allocated || max != tt.max {
+			t.Errorf("parseFileNr(%q) = %q, %q, want %q, %q", tt.content, allocated, max, tt.allocated, tt.max)
+		}
+	}
+}
+
+func TestCollectLimits(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		procPIDMax:          "4194304\n",
+		procFileNr:          "289\t0\t612769\n",
+		"/proc/1/status":    "",
+		"/proc/812/status":  "",
+		"/proc/self/status": "",
+		"/proc/12345678":    "not a process directory",
+	})
+
+	limits := collectLimits(true)
+	if limits.PIDMax != "4194304" || limits.PIDCount != "2" {
+		t.Errorf("PIDMax = %q, PIDCount = %q, want 4194304 and 2", limits.PIDMax, limits.PIDCount)
+	}
+	if limits.FileHandles != "289" || limits.FileHandlesMax != "612769" {
+		t.Errorf("file handles = %q / %q", limits.FileHandles, limits.FileHandlesMax)
+	}
+
+	// 采集进程自身的限制
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		t.Fatal(err)
+	}
+	if limits.NoFileSoftLimit != formatRlimit(rlim.Cur) || limits.NoFileHardLimit != formatRlimit(rlim.Max) {
+		t.Errorf("nofile = %q / %q, want %d / %d", limits.NoFileSoftLimit, limits.NoFileHardLimit, rlim.Cur, rlim.Max)
+	}
+
+	if limits := collectLimits(false); limits.PIDCount != "" {
+		t.Errorf("PIDCount = %q without counting", limits.PIDCount)
+	}
+}
+
+func TestFormatRlimit(t *testing.T) {
+	if got := formatRlimit(rlimInfinity); got != "unlimited" {
+		t.Errorf("formatRlimit(RLIM_INFINITY) = %q", got)
+	}
+	if got := formatRlimit(1048576); got != "1048576" {
+		t.Errorf("formatRlimit(1048576) = %q", got)
+	}
+}
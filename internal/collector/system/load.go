@@ -0,0 +1,91 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	procLoadavg string = "/proc/loadavg"
+	procUptime  string = "/proc/uptime"
+	procStat    string = "/proc/stat"
+)
+
+// collectLoadAverage 读取/proc/loadavg，格式为：
+//
+//	0.52 0.58 0.59 2/1234 56789
+//
+// 前三列为1、5、15分钟平均负载，第四列为正在运行/总的进程(线程)数
+func collectLoadAverage() (model.LoadAverage, error) {
+	content, err := utils.ReadSysfsFile(utils.HostPath(procLoadavg))
+	if err != nil {
+		return model.LoadAverage{}, fmt.Errorf("read %s failed: %w", procLoadavg, err)
+	}
+
+	return parseLoadAverage(content), nil
+}
+
+func parseLoadAverage(content string) model.LoadAverage {
+	line := utils.FirstLine(content)
+	running, total, _ := strings.Cut(utils.Field(line, 4), "/")
+
+	return model.LoadAverage{
+		Load1:            utils.Field(line, 1),
+		Load5:            utils.Field(line, 2),
+		Load15:           utils.Field(line, 3),
+		RunningProcesses: running,
+		TotalProcesses:   total,
+	}
+}
+
+// collectUptime 读取/proc/uptime，第一列为启动以来的秒数。启动时间优先取/proc/stat
+// 的 btime 行，它由内核固定记录，不随采集时刻抖动；读取不到时再用 now 减去运行秒数推算
+func collectUptime(now time.Time) (model.Uptime, error) {
+	content, err := utils.ReadSysfsFile(utils.HostPath(procUptime))
+	if err != nil {
+		return model.Uptime{}, fmt.Errorf("read %s failed: %w", procUptime, err)
+	}
+
+	uptime := parseUptime(content, now)
+	if stat, err := utils.ReadSysfsFile(utils.HostPath(procStat)); err == nil {
+		if boot, ok := parseBootTime(stat); ok {
+			uptime.BootTime = boot.In(now.Location()).Format(time.RFC3339)
+		}
+	}
+
+	return uptime, nil
+}
+
+func parseUptime(content string, now time.Time) model.Uptime {
+	seconds := utils.Field(utils.FirstLine(content), 1)
+
+	uptime := model.Uptime{Seconds: seconds}
+	if v, err := strconv.ParseFloat(seconds, 64); err == nil {
+		boot := now.Add(-time.Duration(v * float64(time.Second)))
+		uptime.BootTime = boot.Truncate(time.Second).Format(time.RFC3339)
+	}
+
+	return uptime
+}
+
+// parseBootTime 从/proc/stat 中取 btime 行，值为系统启动时刻的 Unix 秒数
+func parseBootTime(content string) (time.Time, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || key != "btime" {
+			continue
+		}
+		sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(sec, 0), true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,83 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+func TestCollectLoadAverage(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{procLoadavg: "0.52 0.58 0.59 2/1234 56789\n"})
+
+	got, err := collectLoadAverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.LoadAverage{Load1: "0.52", Load5: "0.58", Load15: "0.59", RunningProcesses: "2", TotalProcesses: "1234"}
+	if got != want {
+		t.Errorf("collectLoadAverage() = %+v, want %+v", got, want)
+	}
+
+	testutil.FakeRoot(t, nil)
+	if _, err := collectLoadAverage(); err == nil {
+		t.Error("collectLoadAverage() succeeded without /proc/loadavg")
+	}
+}
+
+func TestCollectUptime(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{procUptime: "350735.47 1396816.75\n"})
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	got, err := collectUptime(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 350735.47 秒为 4天1小时25分35.47秒
+	want := model.Uptime{Seconds: "350735.47", BootTime: "2024-02-26T10:34:24Z"}
+	if got != want {
+		t.Errorf("collectUptime() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCollectUptimeBootTime(t *testing.T) {
+	testutil.FakeRoot(t, map[string]string{
+		procUptime: "350735.47 1396816.75\n",
+		procStat:   "cpu  10132153 290696 3084719 46828483\nbtime 1708943664\nprocesses 54863\n",
+	})
+
+	// 启动时间取自 btime，与采集时刻无关，相隔一秒的两次采集结果一致
+	now := time.Date(2024, 3, 1, 12, 0, 0, 900_000_000, time.UTC)
+	for _, at := range []time.Time{now, now.Add(time.Second)} {
+		got, err := collectUptime(at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.BootTime != "2024-02-26T10:34:24Z" {
+			t.Errorf("collectUptime(%v).BootTime = %q, want the btime", at, got.BootTime)
+		}
+	}
+}
+
+func TestParseBootTime(t *testing.T) {
+	got, ok := parseBootTime("cpu  1 2 3\nbtime 1708943664\n")
+	if !ok || got.Unix() != 1708943664 {
+		t.Errorf("parseBootTime() = %v, %v", got, ok)
+	}
+	for _, content := range []string{"", "cpu  1 2 3\n", "btime garbage\n"} {
+		if _, ok := parseBootTime(content); ok {
+			t.Errorf("parseBootTime(%q) succeeded", content)
+		}
+	}
+}
+
+func TestParseUptimeInvalid(t *testing.T) {
+	got := parseUptime("garbage\n", time.Now())
+	if got.Seconds != "garbage" || got.BootTime != "" {
+		t.Errorf("parseUptime() = %+v, want no boot time", got)
+	}
+	if got := parseUptime("", time.Now()); got != (model.Uptime{}) {
+		t.Errorf("parseUptime(\"\") = %+v", got)
+	}
+}
@@ -0,0 +1,59 @@
+package system
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const procCPUInfo string = "/proc/cpuinfo"
+
+// collectMicrocode 采集CPU微码版本。优先读取 cpu0 的 sysfs 版本，没有时解析/proc/cpuinfo；
+// ARM、部分虚拟机不提供微码信息，返回空值
+func collectMicrocode() model.Microcode {
+	var mc model.Microcode
+
+	var revisions []string
+	if content, err := utils.ReadSysfsFile(utils.HostPath(procCPUInfo)); err == nil {
+		revisions = parseCPUInfoMicrocode(content)
+	}
+
+	mc.Version, mc.Source, _ = utils.FirstOf(
+		utils.Source[string]{Name: "sysfs", Get: func() (string, error) {
+			return utils.ReadSysfsFile(utils.HostPath(filepath.Join(sysCPUDir, "cpu0", "microcode", "version")))
+		}},
+		utils.Source[string]{Name: "cpuinfo", Get: func() (string, error) {
+			if len(revisions) == 0 {
+				return "", nil
+			}
+			return revisions[0], nil
+		}},
+	)
+
+	// 后期加载(late load)只更新部分CPU或加载中途失败时各CPU的版本不一致
+	if distinct := slices.Compact(slices.Sorted(slices.Values(revisions))); len(distinct) > 1 {
+		mc.Mismatch = true
+		mc.Revisions = distinct
+	}
+
+	return mc
+}
+
+// parseCPUInfoMicrocode 按CPU顺序返回/proc/cpuinfo中各CPU的 microcode 字段：
+//
+//	processor	: 0
+//	microcode	: 0xf0
+func parseCPUInfoMicrocode(content string) []string {
+	var revisions []string
+	for line := range strings.Lines(content) {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "microcode" {
+			continue
+		}
+		revisions = append(revisions, strings.TrimSpace(value))
+	}
+	return revisions
+}
@@ -0,0 +1,71 @@
+package system
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+// cpuinfo 返回各CPU的 microcode 字段依次为 revisions 的/proc/cpuinfo
+func cpuinfo(revisions ...string) string {
+	var s string
+	for i, rev := range revisions {
+		s += "processor\t: " + strconv.Itoa(i) + "\nvendor_id\t: GenuineIntel\nmodel name\t: Intel(R) Xeon(R) Gold 6330\n"
+		if rev != "" {
+			s += "microcode\t: " + rev + "\n"
+		}
+		s += "flags\t\t: fpu vme de pse\n\n"
+	}
+	return s
+}
+
+func TestParseCPUInfoMicrocode(t *testing.T) {
+	if got := parseCPUInfoMicrocode(cpuinfo("0xd0003a5", "0xd0003a5")); !reflect.DeepEqual(got, []string{"0xd0003a5", "0xd0003a5"}) {
+		t.Errorf("parseCPUInfoMicrocode() = %q", got)
+	}
+	// ARM 的 cpuinfo 没有 microcode 字段
+	arm := "processor\t: 0\nBogoMIPS\t: 50.00\nFeatures\t: fp asimd evtstrm\nCPU implementer\t: 0x41\n"
+	if got := parseCPUInfoMicrocode(arm); got != nil {
+		t.Errorf("parseCPUInfoMicrocode(arm) = %q, want none", got)
+	}
+}
+
+func TestCollectMicrocode(t *testing.T) {
+	const sysfsVersion = sysCPUDir + "/cpu0/microcode/version"
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  model.Microcode
+	}{
+		{
+			name:  "sysfs",
+			files: map[string]string{sysfsVersion: "0xd0003a5\n", "/proc/cpuinfo": cpuinfo("0xd0003a5", "0xd0003a5")},
+			want:  model.Microcode{Version: "0xd0003a5", Source: "sysfs"},
+		},
+		{
+			name:  "cpuinfo",
+			files: map[string]string{"/proc/cpuinfo": cpuinfo("0xf0", "0xf0")},
+			want:  model.Microcode{Version: "0xf0", Source: "cpuinfo"},
+		},
+		{
+			name:  "late load mismatch",
+			files: map[string]string{"/proc/cpuinfo": cpuinfo("0xf4", "0xf0", "0xf4")},
+			want:  model.Microcode{Version: "0xf4", Source: "cpuinfo", Mismatch: true, Revisions: []string{"0xf0", "0xf4"}},
+		},
+		{
+			name:  "virtual machine",
+			files: map[string]string{"/proc/cpuinfo": cpuinfo("", "")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.FakeRoot(t, tt.files)
+			if got := collectMicrocode(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collectMicrocode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package system
+
+import (
+	"context"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// Collect 采集操作系统层面的信息，minimal 采集配置下跳过内核模块列表、进程数统计和降频计数
+func Collect(ctx context.Context) (model.System, error) {
+	var sys model.System
+	sys.BootID = collectBootID()
+	sys.MachineID = collectMachineID()
+
+	minimal := utils.IsMinimal(ctx)
+	kernel, err := collectKernel(!minimal)
+	if err != nil {
+		return sys, err
+	}
+	sys.Kernel = kernel
+
+	if sys.LoadAverage, err = collectLoadAverage(); err != nil {
+		return sys, err
+	}
+
+	if sys.Uptime, err = collectUptime(utils.ClockFrom(ctx).Now()); err != nil {
+		return sys, err
+	}
+
+	sys.Limits = collectLimits(!minimal)
+	if !minimal {
+		sys.ThermalThrottle = collectThermalThrottle()
+	}
+	sys.Microcode = collectMicrocode()
+	sys.Entropy = collectEntropy()
+
+	return sys, nil
+}
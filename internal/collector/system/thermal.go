@@ -0,0 +1,96 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const sysCPUDir string = "/sys/devices/system/cpu"
+
+// collectThermalThrottle 读取各逻辑CPU thermal_throttle 目录下的降频计数，
+// 计数非零且持续增长说明散热存在问题。没有该目录的CPU(非Intel、虚拟机等)跳过，全部没有时返回空值
+func collectThermalThrottle() model.ThermalThrottle {
+	entries, err := os.ReadDir(utils.HostPath(sysCPUDir))
+	if err != nil {
+		return model.ThermalThrottle{}
+	}
+
+	var cpus []model.CPUThrottle
+	for _, entry := range entries {
+		name := entry.Name()
+		if !isCPUDir(name) {
+			continue
+		}
+
+		cpuDir := filepath.Join(sysCPUDir, name)
+		core, err := utils.ReadSysfsFile(utils.HostPath(filepath.Join(cpuDir, "thermal_throttle", "core_throttle_count")))
+		if err != nil {
+			continue
+		}
+		pkg, _ := utils.ReadSysfsFile(utils.HostPath(filepath.Join(cpuDir, "thermal_throttle", "package_throttle_count")))
+		pkgID, _ := utils.ReadSysfsFile(utils.HostPath(filepath.Join(cpuDir, "topology", "physical_package_id")))
+
+		cpus = append(cpus, model.CPUThrottle{
+			CPU:                  strings.TrimPrefix(name, "cpu"),
+			PackageID:            pkgID,
+			CoreThrottleCount:    core,
+			PackageThrottleCount: pkg,
+		})
+	}
+
+	return summarizeThrottle(cpus)
+}
+
+// summarizeThrottle 汇总各逻辑CPU的降频计数。同一物理CPU封装内各逻辑CPU的
+// package_throttle_count 相同，每个封装只计一次；结果中只保留计数非零的CPU
+func summarizeThrottle(cpus []model.CPUThrottle) model.ThermalThrottle {
+	if len(cpus) == 0 {
+		return model.ThermalThrottle{}
+	}
+
+	var coreTotal, pkgTotal uint64
+	packages := make(map[string]bool)
+	var throttled []model.CPUThrottle
+	for _, cpu := range cpus {
+		core, _ := strconv.ParseUint(cpu.CoreThrottleCount, 10, 64)
+		pkg, _ := strconv.ParseUint(cpu.PackageThrottleCount, 10, 64)
+
+		coreTotal += core
+		if !packages[cpu.PackageID] {
+			packages[cpu.PackageID] = true
+			pkgTotal += pkg
+		}
+		if core > 0 || pkg > 0 {
+			throttled = append(throttled, cpu)
+		}
+	}
+
+	sort.Slice(throttled, func(i, j int) bool {
+		a, _ := strconv.Atoi(throttled[i].CPU)
+		b, _ := strconv.Atoi(throttled[j].CPU)
+		return a < b
+	})
+
+	return model.ThermalThrottle{
+		Throttled:            strconv.FormatBool(coreTotal > 0 || pkgTotal > 0),
+		CoreThrottleCount:    strconv.FormatUint(coreTotal, 10),
+		PackageThrottleCount: strconv.FormatUint(pkgTotal, 10),
+		CPUs:                 throttled,
+	}
+}
+
+// isCPUDir 判断是否为逻辑CPU目录(cpu0、cpu1...)，排除 cpufreq、cpuidle 等
+func isCPUDir(name string) bool {
+	id, ok := strings.CutPrefix(name, "cpu")
+	if !ok || id == "" {
+		return false
+	}
+	_, err := strconv.Atoi(id)
+	return err == nil
+}
@@ -0,0 +1,76 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/internal/testutil"
+)
+
+// throttleFiles 在 files 中添加逻辑CPU cpu 的降频计数和物理封装ID文件
+func throttleFiles(files map[string]string, cpu, pkgID, core, pkg string) {
+	dir := sysCPUDir + "/cpu" + cpu
+	files[dir+"/thermal_throttle/core_throttle_count"] = core + "\n"
+	files[dir+"/thermal_throttle/package_throttle_count"] = pkg + "\n"
+	files[dir+"/topology/physical_package_id"] = pkgID + "\n"
+}
+
+func TestCollectThermalThrottle(t *testing.T) {
+	files := map[string]string{
+		sysCPUDir + "/cpufreq/boost": "1\n",
+		sysCPUDir + "/online":        "0-3,10\n",
+	}
+	// 两个封装:封装0内的 cpu0、cpu1 共享封装计数 7,封装1没有降频
+	throttleFiles(files, "0", "0", "3", "7")
+	throttleFiles(files, "1", "0", "0", "7")
+	throttleFiles(files, "2", "1", "0", "0")
+	throttleFiles(files, "10", "1", "12", "0")
+	// 没有 thermal_throttle 目录的CPU跳过
+	files[sysCPUDir+"/cpu3/topology/physical_package_id"] = "1\n"
+	testutil.FakeRoot(t, files)
+
+	got := collectThermalThrottle()
+	want := model.ThermalThrottle{
+		Throttled:            "true",
+		CoreThrottleCount:    "15",
+		PackageThrottleCount: "7",
+		CPUs: []model.CPUThrottle{
+			{CPU: "0", PackageID: "0", CoreThrottleCount: "3", PackageThrottleCount: "7"},
+			{CPU: "1", PackageID: "0", CoreThrottleCount: "0", PackageThrottleCount: "7"},
+			{CPU: "10", PackageID: "1", CoreThrottleCount: "12", PackageThrottleCount: "0"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectThermalThrottle() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCollectThermalThrottleNone(t *testing.T) {
+	files := map[string]string{}
+	throttleFiles(files, "0", "0", "0", "0")
+	testutil.FakeRoot(t, files)
+
+	got := collectThermalThrottle()
+	if got.Throttled != "false" || got.CoreThrottleCount != "0" || got.CPUs != nil {
+		t.Errorf("collectThermalThrottle() = %+v, want not throttled", got)
+	}
+
+	// 虚拟机等没有 thermal_throttle 的主机
+	testutil.FakeRoot(t, map[string]string{sysCPUDir + "/cpu0/topology/physical_package_id": "0\n"})
+	if got := collectThermalThrottle(); !reflect.DeepEqual(got, model.ThermalThrottle{}) {
+		t.Errorf("collectThermalThrottle() = %+v, want empty", got)
+	}
+}
+
+func TestIsCPUDir(t *testing.T) {
+	tests := map[string]bool{
+		"cpu0": true, "cpu127": true,
+		"cpu": false, "cpufreq": false, "cpuidle": false, "online": false,
+	}
+	for name, want := range tests {
+		if got := isCPUDir(name); got != want {
+			t.Errorf("isCPUDir(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 客户端配置,字段与 configs/config.yaml 对应
+type Config struct {
+	Client struct {
+		Interval time.Duration `yaml:"interval"`
+		CacheDir string        `yaml:"cache_dir"`
+		Modules  []string      `yaml:"modules"`
+		Profile  string        `yaml:"profile"` // full 或 minimal
+
+		RequireCache bool `yaml:"require_cache"`
+		Identity     struct {
+			Source string `yaml:"source"`
+			Value  string `yaml:"value"`
+		} `yaml:"identity"`
+
+		CommandTimeout  time.Duration `yaml:"command_timeout"`
+		AllowedCommands []string      `yaml:"allowed_commands"`
+
+		AuditCommands    bool     `yaml:"audit_commands"`
+		AuditRedactFlags []string `yaml:"audit_redact_flags"`
+
+		ExcludeDevices []string `yaml:"exclude_devices"`
+	} `yaml:"client"`
+
+	Collector struct {
+		// 各模块的采集超时,值为时长字符串,如 disk: 10s
+		ModuleTimeouts map[string]string `yaml:"module_timeouts"`
+
+		// 网络模块只读取 /sys、/proc,不执行 ethtool、lldpctl、ip 等外部命令
+		NetworkSysfsOnly bool `yaml:"network_sysfs_only"`
+
+		// 不采集的网络接口名称的正则表达式,回环接口 lo 始终不采集
+		NetworkIgnoreInterfaces []string `yaml:"network_ignore_interfaces"`
+
+		// 在容器中运行时采集宿主机网络命名空间的接口,network_host_sysfs 为挂载到容器中的宿主机 /sys
+		NetworkHostNamespace bool   `yaml:"network_host_namespace"`
+		NetworkHostSysfs     string `yaml:"network_host_sysfs"`
+	} `yaml:"collector"`
+
+	Publisher struct {
+		Type       string `yaml:"type"`
+		Serializer string `yaml:"serializer"`
+
+		Delta       bool `yaml:"delta"`
+		OnlyChanged bool `yaml:"only_changed"` // 只在采集结果变化时推送
+
+		// 每 N 个周期强制推送一次全量快照,供丢失消息的消费端重建状态:
+		// only_changed 时即使没有变化也推送,增量模式下推送全量快照而不是增量
+		FullSnapshotEvery int `yaml:"full_snapshot_every"`
+
+		// 附加到每条推送消息中的静态标签,如 datacenter、rack、environment
+		Labels map[string]string `yaml:"labels"`
+
+		// 推送失败后的重试,max_attempts 不大于1时不重试
+		Retry struct {
+			MaxAttempts int           `yaml:"max_attempts"`
+			BaseBackoff time.Duration `yaml:"base_backoff"`
+			MaxBackoff  time.Duration `yaml:"max_backoff"`
+			Jitter      float64       `yaml:"jitter"`
+		} `yaml:"retry"`
+
+		// 网络推送器(kafka、influx)连续失败 threshold 次后熔断,冷却期间直接失败,threshold 为0时不启用
+		Breaker struct {
+			Threshold int           `yaml:"threshold"`
+			Cooldown  time.Duration `yaml:"cooldown"`
+		} `yaml:"breaker"`
+	} `yaml:"publisher"`
+
+	Kafka struct {
+		Brokers []string      `yaml:"brokers"`
+		Topic   string        `yaml:"topic"`
+		Timeout time.Duration `yaml:"timeout"`
+
+		// 按模块路由到不同的 topic,如 memory: hw-metrics,未配置的模块推送到 topic
+		TopicRouting map[string]string `yaml:"topic_routing"`
+	} `yaml:"kafka"`
+
+	// Prometheus 指标,listen_addr 为空时不上报
+	Metrics struct {
+		ListenAddr string `yaml:"listen_addr"` // 如 :9100,在 /metrics 上暴露采集和推送指标
+	} `yaml:"metrics"`
+
+	Influx struct {
+		Addr     string `yaml:"addr"`     // InfluxDB 地址,如 http://localhost:8086
+		Database string `yaml:"database"` // 写入的数据库
+	} `yaml:"influx"`
+
+	Redact struct {
+		Mode   string   `yaml:"mode"`
+		Fields []string `yaml:"fields"`
+		// hash 模式下 HMAC-SHA256 的密钥,各部署使用不同的值,持有密钥者可以通过字典反查摘要
+		HashKey string `yaml:"hash_key"`
+
+		// 推送前将主机名替换为以 hostname_salt 为密钥的 HMAC-SHA256 摘要
+		AnonymizeHostname bool   `yaml:"anonymize_hostname"`
+		HostnameSalt      string `yaml:"hostname_salt"`
+	} `yaml:"redact"`
+
+	Logger struct {
+		LogFile    string `yaml:"log_file"`
+		Level      string `yaml:"level"`
+		Format     string `yaml:"format"`      // 日志格式:text(默认)、json、logfmt
+		NoColor    bool   `yaml:"no_color"`    // 禁用终端彩色输出
+		MaxSize    int    `yaml:"max_size"`    // 已不使用,日志文件按天切分
+		MaxBackups int    `yaml:"max_backups"` // 日志文件保留天数
+
+		// 日志文件写缓冲,两者都为0时每条日志直接写入文件
+		BufferSizeKB  int           `yaml:"buffer_size_kb"`
+		FlushInterval time.Duration `yaml:"flush_interval"`
+
+		// 相同级别和消息的日志在该窗口内只输出一次,之后附带被抑制的条数,0表示不限流
+		RateLimitWindow time.Duration `yaml:"rate_limit_window"`
+
+		// Warn 以下级别日志的采样:每秒前 sample_burst 条全部输出,之后每 sample_rate 条输出一条,两者都为0时不采样
+		SampleRate  int `yaml:"sample_rate"`
+		SampleBurst int `yaml:"sample_burst"`
+	} `yaml:"logger"`
+
+	// 基线比对(hardware-collector-cli -baseline)时忽略的字段,值为以 "." 连接的JSON字段路径
+	Baseline struct {
+		IgnoreFields []string `yaml:"ignore_fields"`
+	} `yaml:"baseline"`
+
+	Resource struct {
+		MaxMemoryMB int `yaml:"max_memory_mb"`
+		CPUCores    int `yaml:"cpu_cores"`
+	} `yaml:"resource"`
+}
+
+// LoadConfig 加载配置文件,按扩展名识别格式:.json、.toml,其他(.yaml、.yml 或无扩展名)按 YAML 解析
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file failed: %w", err)
+	}
+
+	data, err = toYAML(filepath.Ext(path), data)
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s failed: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config file %s failed: %w", path, err)
+	}
+
+	if _, err := cfg.ModuleTimeouts(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if cfg.Logger.BufferSizeKB < 0 || cfg.Logger.FlushInterval < 0 {
+		return nil, fmt.Errorf("invalid config file %s: logger.buffer_size_kb and logger.flush_interval must not be negative", path)
+	}
+	if cfg.Logger.RateLimitWindow < 0 {
+		return nil, fmt.Errorf("invalid config file %s: logger.rate_limit_window must not be negative", path)
+	}
+	if cfg.Logger.SampleRate < 0 || cfg.Logger.SampleBurst < 0 {
+		return nil, fmt.Errorf("invalid config file %s: logger.sample_rate and logger.sample_burst must not be negative", path)
+	}
+	if len(cfg.Redact.Fields) > 0 && (cfg.Redact.Mode == "" || cfg.Redact.Mode == "hash") && cfg.Redact.HashKey == "" {
+		return nil, fmt.Errorf("invalid config file %s: redact.hash_key is required when redact.mode is hash", path)
+	}
+	if cfg.Redact.AnonymizeHostname && cfg.Redact.HostnameSalt == "" {
+		return nil, fmt.Errorf("invalid config file %s: redact.hostname_salt is required when redact.anonymize_hostname is enabled", path)
+	}
+
+	return &cfg, nil
+}
+
+// ModuleTimeouts 解析 collector.module_timeouts 中各模块的超时
+func (c *Config) ModuleTimeouts() (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(c.Collector.ModuleTimeouts))
+	for module, value := range c.Collector.ModuleTimeouts {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("collector.module_timeouts.%s: invalid duration %q", module, value)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("collector.module_timeouts.%s: duration must be positive, got %q", module, value)
+		}
+		timeouts[module] = d
+	}
+	return timeouts, nil
+}
+
+// defaultLogBufferKB 只配置了 logger.flush_interval 时的写缓冲大小
+const defaultLogBufferKB = 64
+
+// LogBuffer 返回日志文件的写缓冲大小(字节)和刷新间隔,对应 logger.LogConfig 的 BufferSize 和 FlushInterval。
+// 两者都为0时返回0,保持每条日志直接写入;只配置了 flush_interval 时使用 64KB 缓冲,
+// 只配置了 buffer_size_kb 时刷新间隔为0,由日志模块使用默认的1秒
+func (c *Config) LogBuffer() (size int, flushInterval time.Duration) {
+	kb, interval := c.Logger.BufferSizeKB, c.Logger.FlushInterval
+	if kb == 0 && interval == 0 {
+		return 0, 0
+	}
+	if kb == 0 {
+		kb = defaultLogBufferKB
+	}
+	return kb * 1024, interval
+}
+
+// toYAML 将 JSON/TOML 配置转换为 YAML 再统一解码,
+// 各格式共用同一组字段名和 time.Duration 等类型的解析规则
+func toYAML(ext string, data []byte) ([]byte, error) {
+	var (
+		tree map[string]any
+		err  error
+	)
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		err = json.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(tree)
+}
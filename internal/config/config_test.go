@@ -0,0 +1,358 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig 在临时目录中写入名为 name 的配置文件并返回其路径
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigModules(t *testing.T) {
+	path := writeConfig(t, "config.yaml", `
+client:
+  interval: 5m
+  modules: [system, memory, network]
+`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.Client.Modules, []string{"system", "memory", "network"}) {
+		t.Errorf("Client.Modules = %q", cfg.Client.Modules)
+	}
+
+	// 未配置时为空,采集全部模块
+	cfg, err = LoadConfig(writeConfig(t, "config.yaml", "client:\n  interval: 5m\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Client.Modules != nil {
+		t.Errorf("Client.Modules = %q, want nil", cfg.Client.Modules)
+	}
+}
+
+const yamlConfig = `
+client:
+  interval: 5m
+  cache_dir: /var/cache/diting
+  modules: [system, disk]
+collector:
+  module_timeouts:
+    disk: 10s
+publisher:
+  type: kafka
+  labels:
+    rack: r12
+  retry:
+    max_attempts: 3
+    base_backoff: 1s
+    jitter: 0.2
+  breaker:
+    threshold: 5
+    cooldown: 1m
+kafka:
+  brokers: ["kafka-1:9092", "kafka-2:9092"]
+  topic: hw
+  timeout: 30s
+`
+
+const jsonConfig = `{
+  "client": {"interval": "5m", "cache_dir": "/var/cache/diting", "modules": ["system", "disk"]},
+  "collector": {"module_timeouts": {"disk": "10s"}},
+  "publisher": {
+    "type": "kafka",
+    "labels": {"rack": "r12"},
+    "retry": {"max_attempts": 3, "base_backoff": "1s", "jitter": 0.2},
+    "breaker": {"threshold": 5, "cooldown": "1m"}
+  },
+  "kafka": {"brokers": ["kafka-1:9092", "kafka-2:9092"], "topic": "hw", "timeout": "30s"}
+}`
+
+const tomlConfig = `
+[client]
+interval = "5m"
+cache_dir = "/var/cache/diting"
+modules = ["system", "disk"]
+
+[collector.module_timeouts]
+disk = "10s"
+
+[publisher]
+type = "kafka"
+
+[publisher.labels]
+rack = "r12"
+
+[publisher.retry]
+max_attempts = 3
+base_backoff = "1s"
+jitter = 0.2
+
+[publisher.breaker]
+threshold = 5
+cooldown = "1m"
+
+[kafka]
+brokers = ["kafka-1:9092", "kafka-2:9092"]
+topic = "hw"
+timeout = "30s"
+`
+
+func TestLoadConfigFormats(t *testing.T) {
+	want, err := LoadConfig(writeConfig(t, "config.yaml", yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.Client.Interval != 5*time.Minute || want.Kafka.Timeout != 30*time.Second ||
+		want.Publisher.Retry.BaseBackoff != time.Second || want.Publisher.Retry.MaxAttempts != 3 ||
+		want.Publisher.Breaker.Threshold != 5 || want.Publisher.Breaker.Cooldown != time.Minute {
+		t.Fatalf("YAML config = %+v", want)
+	}
+
+	tests := map[string]string{
+		"config.yml":  yamlConfig,
+		"config":      yamlConfig, // 无扩展名按 YAML 解析
+		"config.json": jsonConfig,
+		"config.JSON": jsonConfig,
+		"config.toml": tomlConfig,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadConfig(writeConfig(t, name, content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadConfig(%s) =\n%+v\nwant\n%+v", name, got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"config.json": `{"client": `,
+		"config.toml": `[client`,
+		"config.yaml": "client: [",
+		// 时长格式错误
+		"timeouts.yaml": "collector:\n  module_timeouts:\n    disk: soon\n",
+	}
+	for name, content := range tests {
+		if _, err := LoadConfig(writeConfig(t, name, content)); err == nil {
+			t.Errorf("LoadConfig(%s) succeeded", name)
+		}
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadConfig() of a missing file succeeded")
+	}
+}
+
+func TestModuleTimeouts(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", `
+collector:
+  module_timeouts:
+    disk: 10s
+    network: 1m30s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.ModuleTimeouts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Duration{"disk": 10 * time.Second, "network": 90 * time.Second}
+	if !maps.Equal(got, want) {
+		t.Errorf("ModuleTimeouts() = %v, want %v", got, want)
+	}
+
+	for _, value := range []string{"soon", "0s", "-5s"} {
+		_, err := LoadConfig(writeConfig(t, "config.yaml", "collector:\n  module_timeouts:\n    disk: "+value+"\n"))
+		if err == nil || !strings.Contains(err.Error(), "collector.module_timeouts.disk") {
+			t.Errorf("module timeout %q: err = %v, want an error naming the option", value, err)
+		}
+	}
+}
+
+func TestLoadConfigLabels(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", `
+publisher:
+  labels:
+    datacenter: sh1
+    rack: r12
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"datacenter": "sh1", "rack": "r12"}; !maps.Equal(cfg.Publisher.Labels, want) {
+		t.Errorf("Publisher.Labels = %v, want %v", cfg.Publisher.Labels, want)
+	}
+}
+
+func TestLoadConfigNetworkSysfsOnly(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "collector:\n  network_sysfs_only: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Collector.NetworkSysfsOnly {
+		t.Error("Collector.NetworkSysfsOnly = false, want true")
+	}
+}
+
+func TestLoadConfigFullSnapshotEvery(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "publisher:\n  full_snapshot_every: 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Publisher.OnlyChanged || cfg.Publisher.FullSnapshotEvery != 3 {
+		t.Errorf("Publisher.OnlyChanged = %v, FullSnapshotEvery = %d, want false and 3", cfg.Publisher.OnlyChanged, cfg.Publisher.FullSnapshotEvery)
+	}
+}
+
+func TestLoadConfigNetworkIgnoreInterfaces(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", `
+collector:
+  network_ignore_interfaces: ["^veth", '^docker\d+$']
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"^veth", `^docker\d+$`}; !slices.Equal(cfg.Collector.NetworkIgnoreInterfaces, want) {
+		t.Errorf("Collector.NetworkIgnoreInterfaces = %q, want %q", cfg.Collector.NetworkIgnoreInterfaces, want)
+	}
+}
+
+func TestLoadConfigAnonymizeHostname(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "redact:\n  anonymize_hostname: true\n  hostname_salt: s3cret\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Redact.AnonymizeHostname || cfg.Redact.HostnameSalt != "s3cret" {
+		t.Errorf("Redact = %+v", cfg.Redact)
+	}
+
+	// 未配置盐值时拒绝启动,否则摘要可以通过字典反查
+	if _, err := LoadConfig(writeConfig(t, "config.yaml", "redact:\n  anonymize_hostname: true\n")); err == nil || !strings.Contains(err.Error(), "hostname_salt") {
+		t.Errorf("LoadConfig() without a salt error = %v", err)
+	}
+}
+
+func TestLoadConfigRedactHashKey(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "redact:\n  fields: [product_serial]\n  hash_key: k3y\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Redact.HashKey != "k3y" {
+		t.Errorf("Redact.HashKey = %q, want k3y", cfg.Redact.HashKey)
+	}
+
+	// hash 模式(默认)没有密钥时拒绝启动,token 模式不需要密钥
+	for _, content := range []string{"redact:\n  fields: [product_serial]\n", "redact:\n  mode: hash\n  fields: [product_serial]\n"} {
+		if _, err := LoadConfig(writeConfig(t, "config.yaml", content)); err == nil || !strings.Contains(err.Error(), "hash_key") {
+			t.Errorf("LoadConfig(%q) error = %v, want hash_key required", content, err)
+		}
+	}
+	if _, err := LoadConfig(writeConfig(t, "config.yaml", "redact:\n  mode: token\n  fields: [product_serial]\n")); err != nil {
+		t.Errorf("LoadConfig() in token mode without a key: %v", err)
+	}
+}
+
+func TestLoadConfigTopicRouting(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", `
+kafka:
+  topic: hw
+  topic_routing:
+    memory: hw-metrics
+    gpu: hw-metrics
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"memory": "hw-metrics", "gpu": "hw-metrics"}; cfg.Kafka.Topic != "hw" || !maps.Equal(cfg.Kafka.TopicRouting, want) {
+		t.Errorf("Kafka.Topic = %q, TopicRouting = %v, want hw and %v", cfg.Kafka.Topic, cfg.Kafka.TopicRouting, want)
+	}
+}
+
+func TestLoadConfigNetworkHostNamespace(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "collector:\n  network_host_namespace: true\n  network_host_sysfs: /host/sys\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Collector.NetworkHostNamespace || cfg.Collector.NetworkHostSysfs != "/host/sys" {
+		t.Errorf("NetworkHostNamespace = %v, NetworkHostSysfs = %q, want true and /host/sys", cfg.Collector.NetworkHostNamespace, cfg.Collector.NetworkHostSysfs)
+	}
+}
+
+func TestLogBuffer(t *testing.T) {
+	tests := []struct {
+		content      string
+		wantSize     int
+		wantInterval time.Duration
+	}{
+		// 默认不缓冲,每条日志直接写入
+		{content: "logger:\n  level: info\n"},
+		{content: "logger:\n  buffer_size_kb: 256\n  flush_interval: 2s\n", wantSize: 256 << 10, wantInterval: 2 * time.Second},
+		{content: "logger:\n  flush_interval: 500ms\n", wantSize: defaultLogBufferKB << 10, wantInterval: 500 * time.Millisecond},
+		{content: "logger:\n  buffer_size_kb: 32\n", wantSize: 32 << 10},
+	}
+	for _, tt := range tests {
+		cfg, err := LoadConfig(writeConfig(t, "config.yaml", tt.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size, interval := cfg.LogBuffer(); size != tt.wantSize || interval != tt.wantInterval {
+			t.Errorf("%q: LogBuffer() = %d, %v, want %d, %v", tt.content, size, interval, tt.wantSize, tt.wantInterval)
+		}
+	}
+
+	for _, content := range []string{"logger:\n  buffer_size_kb: -1\n", "logger:\n  flush_interval: -1s\n"} {
+		if _, err := LoadConfig(writeConfig(t, "config.yaml", content)); err == nil {
+			t.Errorf("LoadConfig(%q) succeeded", content)
+		}
+	}
+}
+
+func TestLoadConfigLogRateLimit(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "logger:\n  rate_limit_window: 1h\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logger.RateLimitWindow != time.Hour {
+		t.Errorf("RateLimitWindow = %v, want 1h", cfg.Logger.RateLimitWindow)
+	}
+
+	if _, err := LoadConfig(writeConfig(t, "config.yaml", "logger:\n  rate_limit_window: -1m\n")); err == nil {
+		t.Error("LoadConfig() with a negative rate_limit_window succeeded")
+	}
+}
+
+func TestLoadConfigLogSampling(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "config.yaml", "logger:\n  sample_rate: 10\n  sample_burst: 100\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logger.SampleRate != 10 || cfg.Logger.SampleBurst != 100 {
+		t.Errorf("SampleRate = %d, SampleBurst = %d, want 10, 100", cfg.Logger.SampleRate, cfg.Logger.SampleBurst)
+	}
+
+	for _, content := range []string{"logger:\n  sample_rate: -1\n", "logger:\n  sample_burst: -1\n"} {
+		if _, err := LoadConfig(writeConfig(t, "config.yaml", content)); err == nil {
+			t.Errorf("LoadConfig(%q) succeeded", content)
+		}
+	}
+}
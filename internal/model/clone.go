@@ -0,0 +1,54 @@
+package model
+
+import "reflect"
+
+// Clone 返回 h 的深拷贝，指针、切片和 map 指向的数据都重新分配，修改副本不影响 h；h 为 nil 时返回 nil
+func (h *HardwareInfo) Clone() *HardwareInfo {
+	if h == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(h)).Interface().(*HardwareInfo)
+}
+
+// cloneValue 递归复制 v，结构体的未导出字段(如 time.Time 内部字段)按值复制
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return c
+	default:
+		return v
+	}
+}
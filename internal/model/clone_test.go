@@ -0,0 +1,57 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+// rewriteStrings 将 v 中所有可写的字符串改为 s，包括指针、切片和 map 指向的数据
+func rewriteStrings(v reflect.Value, s string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			rewriteStrings(v.Elem(), s)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				rewriteStrings(v.Field(i), s)
+			}
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			rewriteStrings(v.Index(i), s)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			rewriteStrings(elem, s)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		v.SetString(s)
+	}
+}
+
+func TestHardwareInfoClone(t *testing.T) {
+	if (*HardwareInfo)(nil).Clone() != nil {
+		t.Error("nil Clone() != nil")
+	}
+
+	info := fullHardwareInfo()
+	clone := info.Clone()
+	if !reflect.DeepEqual(clone, info) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, info)
+	}
+
+	// 修改副本中任意层级的数据都不影响原值
+	rewriteStrings(reflect.ValueOf(clone), "changed")
+	if !reflect.DeepEqual(info, fullHardwareInfo()) {
+		t.Error("modifying the clone changed the original")
+	}
+	if clone.System.Kernel.Release != "changed" || clone.Labels["x"] != "changed" {
+		t.Errorf("clone not rewritten: release=%q labels=%v", clone.System.Kernel.Release, clone.Labels)
+	}
+}
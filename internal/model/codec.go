@@ -0,0 +1,35 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Unmarshal 将JSON解码为模型 T(如 Network、Storage)，用于消费端或回放时读取采集结果。
+// 模型未定义的字段被忽略，可以直接读取 CLI -j 输出(附带 errors、warnings)或更新版本采集端的数据
+func Unmarshal[T any](data []byte) (*T, error) {
+	return unmarshal[T](data, false)
+}
+
+// UnmarshalStrict 与 Unmarshal 相同，但输入中出现模型未定义的字段时返回错误，
+// 用于校验数据与模型一致，字段被重命名时能及时发现，而不是静默丢弃数据
+func UnmarshalStrict[T any](data []byte) (*T, error) {
+	return unmarshal[T](data, true)
+}
+
+func unmarshal[T any](data []byte, strict bool) (*T, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	v := new(T)
+	if err := dec.Decode(v); err != nil {
+		return nil, fmt.Errorf("unmarshal %T failed: %w", *v, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unmarshal %T failed: unexpected data after JSON value", *v)
+	}
+	return v, nil
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "重新生成 testdata 下的 golden 文件")
+
+const goldenHardware = "testdata/hardware.golden.json"
+
+// populate 填充 v 的每个导出字段(列表和映射各一个元素),使 omitzero 字段全部出现在输出中
+func populate(v reflect.Value, depth int) {
+	if depth > 8 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		populate(v.Elem(), depth+1)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				populate(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		populate(v.Index(0), depth+1)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		elem := reflect.New(v.Type().Elem()).Elem()
+		populate(key, depth+1)
+		populate(elem, depth+1)
+		v.SetMapIndex(key, elem)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	}
+}
+
+func fullHardwareInfo() *HardwareInfo {
+	info := &HardwareInfo{}
+	populate(reflect.ValueOf(info).Elem(), 0)
+	return info
+}
+
+// TestHardwareInfoGolden 字段重命名或删除会改变输出,下游依赖这些字段名,
+// 有意修改时使用 go test ./internal/model -update 重新生成
+func TestHardwareInfoGolden(t *testing.T) {
+	got, err := json.MarshalIndent(fullHardwareInfo(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenHardware), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenHardware, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(goldenHardware)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("JSON output differs from %s (run with -update if the change is intended):\n%s", goldenHardware, got)
+	}
+}
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	want := fullHardwareInfo()
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal[HardwareInfo](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal(Marshal(v)) = %+v, want %+v", got, want)
+	}
+
+	network, err := Unmarshal[Network]([]byte(`{"net_interfaces":[{"device_name":"eth0"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(network.NetInterfaces) != 1 || network.NetInterfaces[0].DeviceName != "eth0" {
+		t.Errorf("Unmarshal[Network]() = %+v", network)
+	}
+}
+
+func TestUnmarshalRejects(t *testing.T) {
+	tests := map[string]string{
+		"trailing data": `{"hostname":"node-1"} {}`,
+		"wrong type":    `{"hostname":1}`,
+		"not JSON":      `hostname: node-1`,
+	}
+	for name, data := range tests {
+		for _, unmarshal := range []func([]byte) (*HardwareInfo, error){Unmarshal[HardwareInfo], UnmarshalStrict[HardwareInfo]} {
+			if _, err := unmarshal([]byte(data)); err == nil {
+				t.Errorf("%s: Unmarshal(%s) succeeded", name, data)
+			} else if !strings.Contains(err.Error(), "HardwareInfo") {
+				t.Errorf("%s: error %q does not name the model", name, err)
+			}
+		}
+	}
+}
+
+func TestUnmarshalUnknownFields(t *testing.T) {
+	data := []byte(`{"hostname":"node-1","host_name":"node-1","errors":[{"module":"gpu"}]}`)
+
+	// 默认忽略模型未定义的字段
+	got, err := Unmarshal[HardwareInfo](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Hostname != "node-1" {
+		t.Errorf("Hostname = %q, want node-1", got.Hostname)
+	}
+
+	// 严格模式下字段被重命名时返回错误
+	if _, err := UnmarshalStrict[HardwareInfo](data); err == nil || !strings.Contains(err.Error(), "host_name") {
+		t.Errorf("UnmarshalStrict() = %v, want an unknown field error", err)
+	}
+}
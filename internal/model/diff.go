@@ -0,0 +1,292 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeType 表示变化类型
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"   // 新增
+	ChangeRemoved ChangeType = "removed" // 移除
+	ChangeChanged ChangeType = "changed" // 字段值变化
+)
+
+// HardwareDiff 表示两次采集结果之间的差异，按顶层模块(section)和设备组织
+type HardwareDiff struct {
+	Sections []SectionDiff `json:"sections,omitzero"` // 发生变化的模块，按名称排序
+}
+
+// SectionDiff 表示一个模块的差异。设备列表类的模块按设备给出差异，其他模块给出字段差异
+type SectionDiff struct {
+	Section string       `json:"section"`          // 模块名称，即顶层JSON字段名
+	Change  ChangeType   `json:"change"`           // 变化类型
+	Devices []DeviceDiff `json:"devices,omitzero"` // 设备差异
+	Fields  []FieldDiff  `json:"fields,omitzero"`  // 字段差异
+}
+
+// DeviceDiff 表示设备列表中一个设备的差异
+type DeviceDiff struct {
+	Key    string      `json:"key"`             // 设备标识，如设备名、PCI地址，没有标识字段时为 #序号
+	Change ChangeType  `json:"change"`          // 变化类型
+	Fields []FieldDiff `json:"fields,omitzero"` // 变化的字段，只在 Change 为 changed 时有值
+}
+
+// FieldDiff 表示一个字段的新旧值，Path 为以 "." 连接的JSON字段路径
+type FieldDiff struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitzero"`
+	New  any    `json:"new,omitzero"`
+}
+
+// deviceKeys 识别设备列表元素的字段，按顺序取第一个非空的字段作为设备标识
+var deviceKeys = []string{"device_name", "name", "pci_address", "serial_number", "serial", "uuid", "index", "id"}
+
+// VolatileFields 每次采集都会变化、不代表硬件或配置发生变化的字段，如运行时间、负载、内存用量、
+// 传感器读数和网卡统计计数器。判断采集结果是否变化、计算增量和比对基线时都忽略这些字段
+var VolatileFields = []string{
+	"system.uptime",
+	"system.load_average",
+	"system.limits",
+	"system.entropy.available",
+	"memory.used",
+	"memory.free",
+	"memory.available",
+	"memory.buffers",
+	"memory.cached",
+	"memory.swap_used",
+	"memory.used_percent",
+	"sensors.value",
+	"network.phy_interfaces.statistics",
+	"gpu.processes",
+}
+
+// Diff 比较两次采集结果(任意可JSON编码的模型，如 HardwareInfo、Network)，
+// ignore 中的字段不参与比较：顶层字段如 timestamp，或以 "." 连接的字段路径如 memory.used，
+// 路径经过数组时作用于每个元素，如 gpu.processes 忽略所有GPU的进程列表；没有差异时返回的 HardwareDiff 为空
+func Diff(prev, cur any, ignore ...string) (*HardwareDiff, error) {
+	oldSections, err := toSections(prev)
+	if err != nil {
+		return nil, err
+	}
+	newSections, err := toSections(cur)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range ignore {
+		removePath(oldSections, strings.Split(path, "."))
+		removePath(newSections, strings.Split(path, "."))
+	}
+
+	names := make([]string, 0, len(oldSections)+len(newSections))
+	for name := range oldSections {
+		names = append(names, name)
+	}
+	for name := range newSections {
+		if _, ok := oldSections[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	diff := &HardwareDiff{}
+	for _, name := range names {
+		o, inOld := oldSections[name]
+		n, inNew := newSections[name]
+		switch {
+		case !inOld:
+			diff.Sections = append(diff.Sections, SectionDiff{Section: name, Change: ChangeAdded})
+		case !inNew:
+			diff.Sections = append(diff.Sections, SectionDiff{Section: name, Change: ChangeRemoved})
+		default:
+			if section, changed := diffSection(name, o, n); changed {
+				diff.Sections = append(diff.Sections, section)
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// Diff 比较 h(上一次的结果)与 other 的硬件状态，采集时间不参与比较，ignore 的含义同包级函数 Diff。
+// 只有读数为 NaN 等无法编码为JSON的情况下无法逐字段比较，此时返回一个 Section 为空的 changed 差异，
+// 调用方按发生变化处理
+func (h *HardwareInfo) Diff(other *HardwareInfo, ignore ...string) *HardwareDiff {
+	diff, err := Diff(h, other, slices.Concat([]string{"timestamp"}, ignore)...)
+	if err != nil {
+		return &HardwareDiff{Sections: []SectionDiff{{Change: ChangeChanged}}}
+	}
+	return diff
+}
+
+// Empty 判断是否没有任何差异
+func (d *HardwareDiff) Empty() bool {
+	return d == nil || len(d.Sections) == 0
+}
+
+// String 返回易读的差异描述，每行一项变化
+func (d *HardwareDiff) String() string {
+	if d.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, s := range d.Sections {
+		fmt.Fprintf(&b, "%s: %s\n", s.Section, s.Change)
+		for _, f := range s.Fields {
+			fmt.Fprintf(&b, "  %s: %v -> %v\n", f.Path, f.Old, f.New)
+		}
+		for _, dev := range s.Devices {
+			fmt.Fprintf(&b, "  [%s] %s\n", dev.Key, dev.Change)
+			for _, f := range dev.Fields {
+				fmt.Fprintf(&b, "    %s: %v -> %v\n", f.Path, f.Old, f.New)
+			}
+		}
+	}
+	return b.String()
+}
+
+// toSections 将模型编码为JSON后按顶层字段拆分
+func toSections(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %T failed: %w", v, err)
+	}
+
+	var sections map[string]any
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("%T is not a JSON object: %w", v, err)
+	}
+	return sections, nil
+}
+
+// removePath 删除JSON值中路径为 path 的字段，遇到数组时对每个元素删除剩余路径
+func removePath(v any, path []string) {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(t, path[0])
+			return
+		}
+		removePath(t[path[0]], path[1:])
+	case []any:
+		for _, item := range t {
+			removePath(item, path)
+		}
+	}
+}
+
+func diffSection(name string, prev, cur any) (SectionDiff, bool) {
+	section := SectionDiff{Section: name, Change: ChangeChanged}
+
+	oldDevices, ok1 := deviceList(prev)
+	newDevices, ok2 := deviceList(cur)
+	if ok1 && ok2 {
+		section.Devices = diffDevices(oldDevices, newDevices)
+		return section, len(section.Devices) > 0
+	}
+
+	section.Fields = diffFields("", prev, cur)
+	return section, len(section.Fields) > 0
+}
+
+// deviceList 判断值是否为设备列表(元素均为对象的数组)，并按设备标识索引
+func deviceList(v any) ([]keyedDevice, bool) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	devices := make([]keyedDevice, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		devices = append(devices, keyedDevice{key: deviceKey(obj, i), value: obj})
+	}
+	return devices, true
+}
+
+type keyedDevice struct {
+	key   string
+	value map[string]any
+}
+
+func deviceKey(obj map[string]any, index int) string {
+	for _, k := range deviceKeys {
+		if v, ok := obj[k]; ok && v != "" && v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+func diffDevices(prev, cur []keyedDevice) []DeviceDiff {
+	var diffs []DeviceDiff
+
+	findDevice := func(list []keyedDevice, key string) (map[string]any, bool) {
+		for _, d := range list {
+			if d.key == key {
+				return d.value, true
+			}
+		}
+		return nil, false
+	}
+
+	for _, o := range prev {
+		n, ok := findDevice(cur, o.key)
+		if !ok {
+			diffs = append(diffs, DeviceDiff{Key: o.key, Change: ChangeRemoved})
+			continue
+		}
+		if fields := diffFields("", o.value, n); len(fields) > 0 {
+			diffs = append(diffs, DeviceDiff{Key: o.key, Change: ChangeChanged, Fields: fields})
+		}
+	}
+	for _, n := range cur {
+		if _, ok := findDevice(prev, n.key); !ok {
+			diffs = append(diffs, DeviceDiff{Key: n.key, Change: ChangeAdded})
+		}
+	}
+
+	return diffs
+}
+
+// diffFields 递归比较两个JSON值，对象展开为字段路径，数组和标量整体比较
+func diffFields(path string, prev, cur any) []FieldDiff {
+	oldObj, ok1 := prev.(map[string]any)
+	newObj, ok2 := cur.(map[string]any)
+	if !ok1 || !ok2 {
+		if reflect.DeepEqual(prev, cur) {
+			return nil
+		}
+		return []FieldDiff{{Path: path, Old: prev, New: cur}}
+	}
+
+	keys := make([]string, 0, len(oldObj)+len(newObj))
+	for k := range oldObj {
+		keys = append(keys, k)
+	}
+	for k := range newObj {
+		if _, ok := oldObj[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var diffs []FieldDiff
+	for _, k := range keys {
+		sub := k
+		if path != "" {
+			sub = path + "." + k
+		}
+		diffs = append(diffs, diffFields(sub, oldObj[k], newObj[k])...)
+	}
+	return diffs
+}
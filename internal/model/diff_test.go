@@ -0,0 +1,153 @@
+package model
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	prev := &HardwareInfo{
+		Timestamp: time.Unix(1700000000, 0),
+		Hostname:  "node-1",
+		Memory:    &Memory{Total: 64 << 30, Used: 1 << 30},
+		Power:     []PowerSupply{{}},
+		PCI: []PCI{
+			{PCIAddr: "0000:3b:00.0", AER: PCIAER{Correctable: "0"}},
+			{PCIAddr: "0000:5e:00.0"},
+		},
+	}
+	cur := &HardwareInfo{
+		Timestamp: time.Unix(1700000300, 0),
+		Hostname:  "node-1",
+		Memory:    &Memory{Total: 128 << 30, Used: 2 << 30},
+		GPU:       []GPU{{}},
+		PCI: []PCI{
+			{PCIAddr: "0000:3b:00.0", AER: PCIAER{Correctable: "3"}},
+			{PCIAddr: "0000:af:00.0"},
+		},
+	}
+
+	diff, err := Diff(prev, cur, "timestamp", "memory.used")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &HardwareDiff{Sections: []SectionDiff{
+		{Section: "gpu", Change: ChangeAdded},
+		{Section: "memory", Change: ChangeChanged, Fields: []FieldDiff{
+			{Path: "total", Old: float64(64 << 30), New: float64(128 << 30)},
+		}},
+		{Section: "pci", Change: ChangeChanged, Devices: []DeviceDiff{
+			{Key: "0000:3b:00.0", Change: ChangeChanged, Fields: []FieldDiff{{Path: "aer.correctable", Old: "0", New: "3"}}},
+			{Key: "0000:5e:00.0", Change: ChangeRemoved},
+			{Key: "0000:af:00.0", Change: ChangeAdded},
+		}},
+		{Section: "power", Change: ChangeRemoved},
+	}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() =\n%s\nwant\n%s", diff, want)
+	}
+
+	wantText := "pci: changed\n  [0000:3b:00.0] changed\n    aer.correctable: 0 -> 3\n  [0000:5e:00.0] removed\n"
+	if !strings.Contains(diff.String(), wantText) {
+		t.Errorf("String() =\n%s\nwant it to contain\n%s", diff, wantText)
+	}
+}
+
+func TestDiffIgnoreThroughLists(t *testing.T) {
+	prev := &HardwareInfo{PCI: []PCI{{PCIAddr: "0000:3b:00.0", AER: PCIAER{Correctable: "0"}}}}
+	cur := &HardwareInfo{PCI: []PCI{{PCIAddr: "0000:3b:00.0", AER: PCIAER{Correctable: "9"}}}}
+
+	diff, err := Diff(prev, cur, "pci.aer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() || diff.String() != "" {
+		t.Errorf("Diff() ignoring pci.aer = %+v, want empty", diff)
+	}
+}
+
+func TestDiffDevicesWithoutKey(t *testing.T) {
+	prev := []Sensor{{Chip: "coretemp", Value: 40}}
+	cur := []Sensor{{Chip: "coretemp", Value: 45}, {Chip: "nct6775", Value: 1200}}
+
+	diff, err := Diff(map[string]any{"sensors": prev}, map[string]any{"sensors": cur})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DeviceDiff{
+		{Key: "#0", Change: ChangeChanged, Fields: []FieldDiff{{Path: "value", Old: float64(40), New: float64(45)}}},
+		{Key: "#1", Change: ChangeAdded},
+	}
+	if len(diff.Sections) != 1 || !reflect.DeepEqual(diff.Sections[0].Devices, want) {
+		t.Errorf("Diff() = %+v, want devices keyed by index %+v", diff.Sections, want)
+	}
+}
+
+func TestDiffInvalid(t *testing.T) {
+	if _, err := Diff([]int{1}, &HardwareInfo{}); err == nil {
+		t.Error("Diff() of a non-object succeeded")
+	}
+	if _, err := Diff(make(chan int), &HardwareInfo{}); err == nil {
+		t.Error("Diff() of an unencodable value succeeded")
+	}
+	var empty *HardwareDiff
+	if !empty.Empty() {
+		t.Error("nil HardwareDiff is not empty")
+	}
+}
+
+func TestHardwareInfoDiff(t *testing.T) {
+	prev := &HardwareInfo{Timestamp: time.Unix(1700000000, 0), Memory: &Memory{Total: 64 << 30, Used: 1 << 30}}
+	cur := &HardwareInfo{Timestamp: time.Unix(1700000300, 0), Memory: &Memory{Total: 64 << 30, Used: 2 << 30}}
+
+	// 采集时间不参与比较
+	diff := prev.Diff(cur)
+	want := []SectionDiff{{Section: "memory", Change: ChangeChanged, Fields: []FieldDiff{
+		{Path: "used", Old: float64(1 << 30), New: float64(2 << 30)},
+	}}}
+	if !reflect.DeepEqual(diff.Sections, want) {
+		t.Errorf("Diff() = %+v, want %+v", diff.Sections, want)
+	}
+	if diff := prev.Diff(cur, "memory.used"); !diff.Empty() {
+		t.Errorf("Diff() ignoring memory.used = %s", diff)
+	}
+
+	// 没有上一次结果时全部模块为新增
+	added := []SectionDiff{{Section: "hostname", Change: ChangeAdded}, {Section: "memory", Change: ChangeAdded}}
+	if diff := (*HardwareInfo)(nil).Diff(cur); !reflect.DeepEqual(diff.Sections, added) {
+		t.Errorf("nil Diff() = %+v", diff.Sections)
+	}
+
+	// 无法编码时按发生变化处理
+	nan := &HardwareInfo{Sensors: []Sensor{{Value: math.NaN()}}}
+	if diff := prev.Diff(nan); diff.Empty() {
+		t.Error("Diff() with an unencodable reading is empty")
+	}
+}
+
+// TestVolatileFieldsExist 字段被重命名后忽略列表会静默失效,要求每个路径都出现在完整的输出中
+func TestVolatileFieldsExist(t *testing.T) {
+	sections, err := toSections(fullHardwareInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exists func(v any, path []string) bool
+	exists = func(v any, path []string) bool {
+		switch t := v.(type) {
+		case map[string]any:
+			child, ok := t[path[0]]
+			return ok && (len(path) == 1 || exists(child, path[1:]))
+		case []any:
+			return len(t) > 0 && exists(t[0], path)
+		}
+		return false
+	}
+	for _, path := range VolatileFields {
+		if !exists(sections, strings.Split(path, ".")) {
+			t.Errorf("VolatileFields contains %q, which is not in the HardwareInfo output", path)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+// Storage 表示存储信息，包括块设备树和软RAID
+type Storage struct {
+	BlockDevices []BlockDevice `json:"block_devices,omitzero"` // 块设备
+	MDRaids      []MDRaid      `json:"md_raids,omitzero"`      // MD软RAID
+	NVMes        []NVMe        `json:"nvmes,omitzero"`         // NVMe盘健康信息
+}
+
+// BlockDevice 表示块设备信息，包括物理磁盘、MD RAID、device-mapper(LVM)设备，从/sys/block目录获取
+type BlockDevice struct {
+	Name       string      `json:"name,omitzero"`       // 设备名称，如 sda、nvme0n1、md0、dm-0
+	Type       string      `json:"type,omitzero"`       // 设备类型：disk、md、lvm、dm
+	DMName     string      `json:"dm_name,omitzero"`    // device-mapper名称，如 vg0-root
+	Size       string      `json:"size,omitzero"`       // 容量，单位字节
+	Model      string      `json:"model,omitzero"`      // 磁盘型号
+	Serial     string      `json:"serial,omitzero"`     // 磁盘序列号
+	WWN        string      `json:"wwn,omitzero"`        // 全球唯一标识(World Wide Name)
+	ByID       []string    `json:"by_id,omitzero"`      // /dev/disk/by-id 下指向该设备的链接名称
+	Partitions []Partition `json:"partitions,omitzero"` // 分区
+	Slaves     []string    `json:"slaves,omitzero"`     // 下层设备，如md0的成员盘
+	Holders    []string    `json:"holders,omitzero"`    // 上层设备，如使用该盘的md0、dm-0
+	Queue      BlockQueue  `json:"queue,omitzero"`      // I/O调度器和队列参数
+}
+
+// BlockQueue 表示块设备的I/O调度器和队列参数，从/sys/block/<dev>/queue目录获取
+type BlockQueue struct {
+	Scheduler           string   `json:"scheduler,omitzero"`            // 当前I/O调度器，如 none、mq-deadline、bfq
+	AvailableSchedulers []string `json:"available_schedulers,omitzero"` // 可用的I/O调度器
+	NrRequests          string   `json:"nr_requests,omitzero"`          // 请求队列深度
+	ReadAheadKB         string   `json:"read_ahead_kb,omitzero"`        // 预读大小，单位KB
+	Rotational          string   `json:"rotational,omitzero"`           // 是否为机械盘：1 机械盘，0 SSD/NVMe
+	NrHWQueues          string   `json:"nr_hw_queues,omitzero"`         // 硬件队列数(blk-mq)
+}
+
+// Partition 表示磁盘分区信息
+type Partition struct {
+	Name    string   `json:"name,omitzero"`    // 分区名称，如 sda1
+	Size    string   `json:"size,omitzero"`    // 容量，单位字节
+	Holders []string `json:"holders,omitzero"` // 上层设备
+}
+
+// NVMe 表示NVMe盘的控制器和健康信息，通过 nvme id-ctrl 和 nvme smart-log 获取
+type NVMe struct {
+	Device           string   `json:"device,omitzero"`            // 控制器设备，如 /dev/nvme0
+	Model            string   `json:"model,omitzero"`             // 型号
+	Serial           string   `json:"serial,omitzero"`            // 序列号
+	Firmware         string   `json:"firmware,omitzero"`          // 固件版本
+	PercentUsed      string   `json:"percent_used,omitzero"`      // 寿命已使用百分比
+	AvailableSpare   string   `json:"available_spare,omitzero"`   // 剩余备用空间百分比
+	SpareThreshold   string   `json:"spare_threshold,omitzero"`   // 备用空间告警阈值百分比
+	MediaErrors      string   `json:"media_errors,omitzero"`      // 介质错误次数
+	Temperature      string   `json:"temperature,omitzero"`       // 温度，单位开尔文
+	CriticalWarning  string   `json:"critical_warning,omitzero"`  // 严重告警位图原始值
+	CriticalWarnings []string `json:"critical_warnings,omitzero"` // 严重告警位图解码后的告警项
+}
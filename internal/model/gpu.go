@@ -1 +1,21 @@
 package model
+
+// GPU 表示GPU信息，通过nvidia-smi获取，并关联到sysfs中的PCI设备
+type GPU struct {
+	Index        string `json:"index,omitzero"`         // GPU序号
+	Name         string `json:"name,omitzero"`          // 型号名称
+	UUID         string `json:"uuid,omitzero"`          // GPU UUID
+	BusID        string `json:"bus_id,omitzero"`        // nvidia-smi报告的总线地址
+	PCI          PCI    `json:"pci,omitzero"`           // PCI信息，包括地址、NUMA节点和链路信息
+	LinkDegraded bool   `json:"link_degraded,omitzero"` // PCIe链路是否降级
+	NVLink       bool   `json:"nvlink,omitzero"`        // 是否存在活动的NVLink链路
+
+	Processes []GPUProcess `json:"processes,omitzero"` // 使用该GPU的计算进程
+}
+
+// GPUProcess 表示使用GPU的计算进程
+type GPUProcess struct {
+	PID        string `json:"pid,omitzero"`         // 进程号
+	Command    string `json:"command,omitzero"`     // 进程名，来自/proc/<pid>/comm
+	UsedMemory string `json:"used_memory,omitzero"` // 占用的显存，单位MiB
+}
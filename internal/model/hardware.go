@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// HardwareInfo 表示一次采集的完整结果，每个采集模块对应一个顶层字段，未采集或采集失败的模块为空
+type HardwareInfo struct {
+	Timestamp time.Time `json:"timestamp"` // 采集时间
+	Hostname  string    `json:"hostname"`  // 主机标识
+
+	Product    *Product      `json:"product,omitzero"`    // 产品、主板、机箱和BIOS信息
+	System     *System       `json:"system,omitzero"`     // 操作系统信息
+	Memory     *Memory       `json:"memory,omitzero"`     // 内存信息
+	Disk       *Storage      `json:"disk,omitzero"`       // 块设备、软RAID和NVMe健康信息
+	Network    *Network      `json:"network,omitzero"`    // 网络接口
+	GPU        []GPU         `json:"gpu,omitzero"`        // GPU
+	Sensors    []Sensor      `json:"sensors,omitzero"`    // 温度和风扇传感器
+	Power      []PowerSupply `json:"power,omitzero"`      // 电源和电池
+	InfiniBand []InfiniBand  `json:"infiniband,omitzero"` // InfiniBand/RDMA设备
+	PCI        []PCI         `json:"pci,omitzero"`        // 支持AER的PCIe设备及其错误计数
+	Service    *Services     `json:"service,omitzero"`    // systemd 服务状态
+
+	Labels map[string]string `json:"labels,omitempty"` // 推送时附加的静态标签(如机房、机架、环境),由推送器设置
+}
@@ -0,0 +1,22 @@
+package model
+
+// InfiniBand 表示InfiniBand/RDMA(含RoCE)设备信息，从/sys/class/infiniband目录获取
+type InfiniBand struct {
+	Name            string   `json:"name,omitzero"`             // 设备名称，如 mlx5_0
+	HCAType         string   `json:"hca_type,omitzero"`         // HCA型号，如 MT4123
+	BoardID         string   `json:"board_id,omitzero"`         // 板卡ID
+	FirmwareVersion string   `json:"firmware_version,omitzero"` // 固件版本
+	NodeGUID        string   `json:"node_guid,omitzero"`        // 节点GUID
+	Ports           []IBPort `json:"ports,omitzero"`            // 端口信息
+	PCI             PCI      `json:"pci,omitzero"`              // PCI信息
+}
+
+// IBPort 表示InfiniBand设备的端口信息
+type IBPort struct {
+	Port      string `json:"port,omitzero"`       // 端口号
+	State     string `json:"state,omitzero"`      // 逻辑状态，如 ACTIVE、DOWN
+	PhysState string `json:"phys_state,omitzero"` // 物理状态，如 LinkUp、Disabled
+	Rate      string `json:"rate,omitzero"`       // 速率，如 100 Gb/sec (4X EDR)
+	LinkLayer string `json:"link_layer,omitzero"` // 链路层：InfiniBand 或 Ethernet(RoCE)
+	LID       string `json:"lid,omitzero"`        // 本地标识符，RoCE端口为0
+}
@@ -1 +1,49 @@
 package model
+
+// Memory 表示内存使用情况，从/proc/meminfo获取，容量单位均为字节
+type Memory struct {
+	Total       uint64  `json:"total,omitzero"`        // 物理内存总量，MemTotal
+	Used        uint64  `json:"used,omitzero"`         // 已用内存，Total - Available
+	Free        uint64  `json:"free,omitzero"`         // 完全未使用的内存，MemFree
+	Available   uint64  `json:"available,omitzero"`    // 可分配给新进程的内存(含可回收的缓存)，MemAvailable
+	Buffers     uint64  `json:"buffers,omitzero"`      // 块设备缓冲，Buffers
+	Cached      uint64  `json:"cached,omitzero"`       // 页缓存，Cached
+	SwapTotal   uint64  `json:"swap_total,omitzero"`   // 交换分区总量，SwapTotal
+	SwapUsed    uint64  `json:"swap_used,omitzero"`    // 已用交换分区，SwapTotal - SwapFree
+	UsedPercent float64 `json:"used_percent,omitzero"` // 内存使用率，(Total - Available) / Total * 100
+
+	DIMMs    []DIMM             `json:"dimms,omitzero"`    // 已插的内存条，通过dmidecode获取，需要root权限
+	EDAC     []MemoryController `json:"edac,omitzero"`     // EDAC内存错误计数，未加载EDAC驱动时为空
+	Diagnose string             `json:"diagnose,omitzero"` // 发现的问题，如容量不一致、降频运行、单通道运行、存在内存错误
+}
+
+// MemoryController 表示EDAC内存控制器的错误计数，从/sys/devices/system/edac/mc/mc*获取
+type MemoryController struct {
+	Name    string     `json:"name,omitzero"`     // 控制器名称，如 mc0
+	CECount string     `json:"ce_count,omitzero"` // 可纠正错误数
+	UECount string     `json:"ue_count,omitzero"` // 不可纠正错误数，非0说明内存条即将故障
+	DIMMs   []EDACDIMM `json:"dimms,omitzero"`    // 各内存条(旧内核为各rank)的错误计数
+}
+
+// EDACDIMM 表示EDAC中一条内存条或一个rank的错误计数
+type EDACDIMM struct {
+	Name     string `json:"name,omitzero"`     // 名称，如 dimm0、csrow0
+	Label    string `json:"label,omitzero"`    // 主板丝印标签，如 CPU_SrcID#0_Ha#0_Chan#0_DIMM#0
+	Location string `json:"location,omitzero"` // 位置，如 channel 0 slot 0
+	CECount  string `json:"ce_count,omitzero"` // 可纠正错误数
+	UECount  string `json:"ue_count,omitzero"` // 不可纠正错误数
+}
+
+// DIMM 表示一条内存条，从 dmidecode -t 17 获取
+type DIMM struct {
+	Locator         string `json:"locator,omitzero"`          // 插槽名称，如 CPU0_DIMM_A1
+	BankLocator     string `json:"bank_locator,omitzero"`     // Bank名称，如 P0_Node0_Channel0_Dimm0
+	Size            string `json:"size,omitzero"`             // 容量，如 32 GB
+	Type            string `json:"type,omitzero"`             // 类型，如 DDR4、DDR5
+	Speed           string `json:"speed,omitzero"`            // 额定速率，如 3200 MT/s
+	ConfiguredSpeed string `json:"configured_speed,omitzero"` // 实际运行速率
+	Manufacturer    string `json:"manufacturer,omitzero"`     // 厂商
+	SerialNumber    string `json:"serial_number,omitzero"`    // 序列号
+	PartNumber      string `json:"part_number,omitzero"`      // 型号
+	Rank            string `json:"rank,omitzero"`             // Rank数
+}
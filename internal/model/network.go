@@ -1,10 +1,77 @@
 package model
 
+import (
+	"slices"
+	"strings"
+)
+
 // NetWork 表示网络信息
 type Network struct {
 	NetInterfaces  []NetInterface  `json:"net_interfaces,omitzero"`
 	PhyInterfaces  []PhyInterface  `json:"phy_interfaces,omitzero"`
 	BondInterfaces []BondInterface `json:"bond_interfaces,omitzero"`
+	DuplicateMACs  []DuplicateMAC  `json:"duplicate_macs,omitzero"`    // 多个接口共用的MAC地址，可能是bond或虚拟接口配置错误
+	Neighbors      []Neighbor      `json:"neighbors,omitzero"`         // IPv4(ARP)和IPv6邻居表
+	Sockets        []Socket        `json:"listening_sockets,omitzero"` // 监听的TCP/UDP套接字
+}
+
+// Socket 表示监听的TCP/UDP套接字，从/proc/net/{tcp,tcp6,udp,udp6}获取
+type Socket struct {
+	Protocol string `json:"protocol,omitzero"` // 协议：tcp、tcp6、udp、udp6
+	Address  string `json:"address,omitzero"`  // 监听地址，0.0.0.0 或 :: 表示所有地址
+	Port     int    `json:"port,omitzero"`     // 监听端口
+	UID      string `json:"uid,omitzero"`      // 所属用户ID
+	Inode    string `json:"inode,omitzero"`    // 套接字inode
+	PID      string `json:"pid,omitzero"`      // 所属进程ID，无权限读取时为空
+	Process  string `json:"process,omitzero"`  // 所属进程名称
+}
+
+// Neighbor 表示邻居表项，IPv4从/proc/net/arp获取，IPv6从ip -6 neigh获取
+type Neighbor struct {
+	Interface  string `json:"interface,omitzero"`   // 接口名称
+	IP         string `json:"ip,omitzero"`          // IP地址
+	MACAddress string `json:"mac_address,omitzero"` // MAC地址，未解析时为空
+	State      string `json:"state,omitzero"`       // 状态，如 REACHABLE、STALE、FAILED
+	Router     bool   `json:"router,omitzero"`      // 邻居是否为路由器(仅IPv6)
+}
+
+// DuplicateMAC 表示被多个接口共用的MAC地址
+type DuplicateMAC struct {
+	MACAddress string   `json:"mac_address,omitzero"` // MAC地址
+	Interfaces []string `json:"interfaces,omitzero"`  // 使用该MAC地址的接口名称
+}
+
+// InterfacesByMAC 按MAC地址(小写)索引网络接口，没有MAC地址或MAC地址全为0的接口不参与索引
+func (n *Network) InterfacesByMAC() map[string][]NetInterface {
+	index := make(map[string][]NetInterface, len(n.NetInterfaces))
+	for _, iface := range n.NetInterfaces {
+		mac := strings.ToLower(iface.MACAddress)
+		if mac == "" || mac == "00:00:00:00:00:00" {
+			continue
+		}
+		index[mac] = append(index[mac], iface)
+	}
+	return index
+}
+
+// DetectDuplicateMACs 找出被多个接口共用的MAC地址并记录到 DuplicateMACs，按MAC地址排序
+func (n *Network) DetectDuplicateMACs() {
+	n.DuplicateMACs = nil
+	for mac, ifaces := range n.InterfacesByMAC() {
+		if len(ifaces) < 2 {
+			continue
+		}
+
+		names := make([]string, 0, len(ifaces))
+		for _, iface := range ifaces {
+			names = append(names, iface.DeviceName)
+		}
+		n.DuplicateMACs = append(n.DuplicateMACs, DuplicateMAC{MACAddress: mac, Interfaces: names})
+	}
+
+	slices.SortFunc(n.DuplicateMACs, func(a, b DuplicateMAC) int {
+		return strings.Compare(a.MACAddress, b.MACAddress)
+	})
 }
 
 // NetInterface 表示网络接口信息，包括物理接口、虚拟接口等，从/sys/class/net目录获取
@@ -13,9 +80,13 @@ type NetInterface struct {
 	MACAddress      string `json:"mac_address,omitzero"`      // MAC地址
 	Driver          string `json:"driver,omitzero"`           // 驱动名称
 	DriverVersion   string `json:"driver_version,omitzero"`   // 驱动版本
+	DriverSource    string `json:"driver_source,omitzero"`    // 驱动信息来源：ethtool、sysfs
 	FirmwareVersion string `json:"firmware_version,omitzero"` // 固件版本
 	Status          string `json:"status,omitzero"`           // 状态
-	Speed           string `json:"speed,omitzero"`            // 速率
+	Speed           string `json:"speed,omitzero"`            // 速率，如 10 Gb/s
+	SpeedMbps       int    `json:"speed_mbps,omitzero"`       // 速率，单位Mb/s
+	MaxSpeedMbps    int    `json:"max_speed_mbps,omitzero"`   // 网卡支持的最大速率，单位Mb/s
+	LinkDegraded    bool   `json:"link_degraded,omitzero"`    // 协商速率是否低于网卡支持的最大速率
 	Duplex          string `json:"duplex,omitzero"`           // 双工模式
 	MTU             string `json:"mtu,omitzero"`              // 最大传输单元
 	Port            string `json:"port,omitzero"`             // 端口
@@ -24,10 +95,14 @@ type NetInterface struct {
 
 // PhyInterface 表示物理接口信息，包括网卡、交换机等
 type PhyInterface struct {
-	RingBuffer RingBuffer `json:"ring_buffer,omitzero"` // 环形缓冲区
-	Channel    Channel    `json:"channel,omitzero"`     // 通道
-	LLDP       LLDP       `json:"lldp,omitzero"`        // LLDP信息
-	PCI        PCI        `json:"pci,omitzero"`         // PCI信息
+	DeviceName string                `json:"device_name,omitzero"` // 设备名称
+	RingBuffer RingBuffer            `json:"ring_buffer,omitzero"` // 环形缓冲区
+	Channel    Channel               `json:"channel,omitzero"`     // 通道
+	Features   map[string]NetFeature `json:"features,omitzero"`    // 网卡特性(卸载功能)，如 tcp-segmentation-offload
+	Statistics map[string]uint64     `json:"statistics,omitzero"`  // 驱动统计计数器(ethtool -S)，名称由驱动决定，如 rx_missed_errors
+	IRQs       []IRQ                 `json:"irqs,omitzero"`        // 网卡中断及CPU亲和性
+	LLDP       LLDP                  `json:"lldp,omitzero"`        // LLDP信息
+	PCI        PCI                   `json:"pci,omitzero"`         // PCI信息
 }
 
 // RingBuffer 表示环形缓冲区信息
@@ -48,6 +123,19 @@ type Channel struct {
 	CurrentCombined string `json:"current_combined,omitzero"` // 当前组合通道数
 }
 
+// NetFeature 表示网卡特性的状态，从 ethtool -k 获取
+type NetFeature struct {
+	Enabled bool `json:"enabled"`        // 是否开启
+	Fixed   bool `json:"fixed,omitzero"` // 是否固定，不能修改
+}
+
+// IRQ 表示网卡中断(收发队列)及其CPU亲和性
+type IRQ struct {
+	Number   string `json:"number,omitzero"`   // 中断号
+	Name     string `json:"name,omitzero"`     // 中断名称，如 ens1f0-TxRx-0
+	Affinity string `json:"affinity,omitzero"` // 中断绑定的CPU列表，如 0-3
+}
+
 // LLDP 表示LLDP信息，上联tor端口信息
 type LLDP struct {
 	Interface    string `json:"interface,omitzero"`          // 接口名称
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectDuplicateMACs(t *testing.T) {
+	n := &Network{NetInterfaces: []NetInterface{
+		{DeviceName: "eth0", MACAddress: "52:54:00:12:34:56"},
+		{DeviceName: "eth1", MACAddress: "52:54:00:AA:BB:CC"},
+		{DeviceName: "bond0", MACAddress: "52:54:00:12:34:56"},
+		{DeviceName: "vlan100", MACAddress: "52:54:00:aa:bb:cc"},
+		{DeviceName: "ib0", MACAddress: "52:54:00:00:00:01"},
+		{DeviceName: "tun0"},
+		{DeviceName: "dummy0", MACAddress: "00:00:00:00:00:00"},
+		{DeviceName: "dummy1", MACAddress: "00:00:00:00:00:00"},
+	}}
+
+	n.DetectDuplicateMACs()
+
+	want := []DuplicateMAC{
+		{MACAddress: "52:54:00:12:34:56", Interfaces: []string{"eth0", "bond0"}},
+		{MACAddress: "52:54:00:aa:bb:cc", Interfaces: []string{"eth1", "vlan100"}},
+	}
+	if !reflect.DeepEqual(n.DuplicateMACs, want) {
+		t.Errorf("DuplicateMACs = %+v, want %+v", n.DuplicateMACs, want)
+	}
+
+	index := n.InterfacesByMAC()
+	if len(index) != 3 || len(index["52:54:00:00:00:01"]) != 1 || index["52:54:00:00:00:01"][0].DeviceName != "ib0" {
+		t.Errorf("InterfacesByMAC() = %+v", index)
+	}
+
+	// 重复检测可以重复执行
+	n.NetInterfaces = n.NetInterfaces[:2]
+	n.DetectDuplicateMACs()
+	if n.DuplicateMACs != nil {
+		t.Errorf("DuplicateMACs = %+v after removing the duplicates", n.DuplicateMACs)
+	}
+}
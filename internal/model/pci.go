@@ -21,6 +21,16 @@ type PCI struct {
 	Revision    string    `json:"revision,omitzero"`          // 修订版本
 	Driver      PCIDriver `json:"driver,omitzero"`            // 驱动信息
 	Link        PCILink   `json:"link,omitzero"`              // 链接信息
+	AER         PCIAER    `json:"aer,omitzero"`               // AER错误计数
+	Degraded    bool      `json:"degraded,omitzero"`          // 是否出现过不可纠正的AER错误
+}
+
+// PCIAER 表示PCIe设备启动以来的AER(Advanced Error Reporting)错误计数，
+// 从/sys/bus/pci/devices/<addr>/aer_dev_*获取，设备不支持AER时为空
+type PCIAER struct {
+	Correctable string `json:"correctable,omitzero"` // 可纠正错误数
+	Fatal       string `json:"fatal,omitzero"`       // 致命错误数
+	NonFatal    string `json:"non_fatal,omitzero"`   // 非致命(不可纠正)错误数
 }
 
 // PCIDriver 表示PCI设备的驱动信息
@@ -0,0 +1,14 @@
+package model
+
+// PowerSupply 表示电源或电池信息，从/sys/class/power_supply目录获取
+type PowerSupply struct {
+	Name         string `json:"name,omitzero"`         // 设备名称，如 AC、BAT0
+	Type         string `json:"type,omitzero"`         // 类型：Mains、Battery、UPS、USB
+	Online       string `json:"online,omitzero"`       // 是否接通电源(交流适配器/PSU)
+	Present      string `json:"present,omitzero"`      // 是否在位
+	Status       string `json:"status,omitzero"`       // 状态：Charging、Discharging、Full 等
+	Capacity     string `json:"capacity,omitzero"`     // 电池剩余电量百分比
+	Health       string `json:"health,omitzero"`       // 健康状态
+	Manufacturer string `json:"manufacturer,omitzero"` // 厂商
+	ModelName    string `json:"model_name,omitzero"`   // 型号
+}
@@ -1 +1,22 @@
 package model
+
+// Product 表示服务器产品和固件信息，从/sys/class/dmi/id目录获取，无需root权限和dmidecode
+type Product struct {
+	SysVendor       string `json:"sys_vendor,omitzero"`        // 系统厂商
+	ProductName     string `json:"product_name,omitzero"`      // 产品名称
+	ProductSerial   string `json:"product_serial,omitzero"`    // 产品序列号，需要root权限
+	ProductUUID     string `json:"product_uuid,omitzero"`      // 产品UUID(SMBIOS System UUID)，需要root权限
+	BoardVendor     string `json:"board_vendor,omitzero"`      // 主板厂商
+	BoardName       string `json:"board_name,omitzero"`        // 主板名称
+	ChassisType     string `json:"chassis_type,omitzero"`      // 机箱类型，SMBIOS定义的类型编号
+	ChassisSerial   string `json:"chassis_serial,omitzero"`    // 机箱序列号，需要root权限
+	ChassisAssetTag string `json:"chassis_asset_tag,omitzero"` // 机箱资产标签，通常与CMDB中的资产编号对应
+	BIOS            BIOS   `json:"bios,omitzero"`              // BIOS信息
+}
+
+// BIOS 表示BIOS固件信息
+type BIOS struct {
+	Vendor  string `json:"vendor,omitzero"`  // BIOS厂商
+	Version string `json:"version,omitzero"` // BIOS版本
+	Date    string `json:"date,omitzero"`    // BIOS发布日期
+}
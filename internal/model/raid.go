@@ -1 +1,22 @@
 package model
+
+// MDRaid 表示MD软RAID信息，从/proc/mdstat获取
+type MDRaid struct {
+	Name         string     `json:"name,omitzero"`          // 阵列名称，如 md0
+	Level        string     `json:"level,omitzero"`         // RAID级别，如 raid1、raid5
+	State        string     `json:"state,omitzero"`         // 阵列状态：active、inactive
+	Health       string     `json:"health,omitzero"`        // 健康状态：clean、degraded
+	Devices      string     `json:"devices,omitzero"`       // 成员数/在线成员数，如 2/1
+	Status       string     `json:"status,omitzero"`        // 成员状态，如 U_
+	Blocks       string     `json:"blocks,omitzero"`        // 容量，单位KiB
+	SyncAction   string     `json:"sync_action,omitzero"`   // 正在进行的同步操作：resync、recovery、reshape、check
+	SyncProgress string     `json:"sync_progress,omitzero"` // 同步进度，如 12.6%
+	Members      []MDMember `json:"members,omitzero"`       // 成员盘
+}
+
+// MDMember 表示MD软RAID成员盘信息
+type MDMember struct {
+	Name  string `json:"name,omitzero"`  // 成员设备名称，如 sda1
+	Role  string `json:"role,omitzero"`  // 成员序号
+	State string `json:"state,omitzero"` // 成员状态：active、faulty、spare、write-mostly、replacement
+}
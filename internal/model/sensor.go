@@ -0,0 +1,10 @@
+package model
+
+// Sensor 表示硬件传感器读数，从/sys/class/hwmon目录获取
+type Sensor struct {
+	Chip  string  `json:"chip,omitzero"`  // 芯片名称，如 coretemp、nct6775
+	Label string  `json:"label,omitzero"` // 传感器标签，无标签时为输入名称，如 temp1
+	Type  string  `json:"type,omitzero"`  // 传感器类型：temperature、fan
+	Value float64 `json:"value"`          // 读数，温度单位为摄氏度，风扇单位为RPM
+	Unit  string  `json:"unit,omitzero"`  // 读数单位
+}
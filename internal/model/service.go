@@ -0,0 +1,8 @@
+package model
+
+// Services 表示服务管理器状态，目前只支持 systemd
+type Services struct {
+	Manager     string   `json:"manager,omitzero"`      // 服务管理器，systemd；非 systemd 主机为空
+	FailedCount int      `json:"failed_count,omitzero"` // 失败的 unit 数量
+	FailedUnits []string `json:"failed_units,omitzero"` // 失败的 unit 名称，如 nginx.service
+}
@@ -0,0 +1,92 @@
+package model
+
+// System 表示操作系统层面的信息
+type System struct {
+	BootID      string      `json:"boot_id,omitzero"`      // 本次启动的随机标识，每次重启后变化
+	MachineID   string      `json:"machine_id,omitzero"`   // 机器标识，主机名变更后保持不变
+	Kernel      Kernel      `json:"kernel,omitzero"`       // 内核信息
+	LoadAverage LoadAverage `json:"load_average,omitzero"` // 系统负载
+	Uptime      Uptime      `json:"uptime,omitzero"`       // 运行时间
+	Limits      Limits      `json:"limits,omitzero"`       // 进程数和文件句柄的使用量与上限
+
+	ThermalThrottle ThermalThrottle `json:"thermal_throttle,omitzero"` // CPU过热降频计数
+	Microcode       Microcode       `json:"microcode,omitzero"`        // CPU微码版本
+	Entropy         Entropy         `json:"entropy,omitzero"`          // 内核熵池和硬件随机数源
+}
+
+// Entropy 表示内核熵池和硬件随机数源，从/proc/sys/kernel/random和/sys/class/misc/hw_random获取。
+// 5.18 及以后的内核 entropy_avail 恒为 256，熵不足的问题主要出现在旧内核上
+type Entropy struct {
+	Available    string `json:"available,omitzero"`     // 熵池中可用的熵，单位bit
+	PoolSize     string `json:"pool_size,omitzero"`     // 熵池大小，单位bit
+	HWRNGPresent string `json:"hwrng_present,omitzero"` // 是否有硬件随机数源：true、false
+	HWRNG        string `json:"hwrng,omitzero"`         // 当前使用的硬件随机数源，如 tpm-rng-0、virtio_rng.0
+}
+
+// Microcode 表示运行中的CPU微码版本，从/sys/devices/system/cpu/cpu0/microcode/version
+// 或/proc/cpuinfo获取，ARM和部分虚拟机没有微码信息
+type Microcode struct {
+	Version   string   `json:"version,omitzero"`   // 微码版本，如 0xf0
+	Source    string   `json:"source,omitzero"`    // 版本来源：sysfs、cpuinfo
+	Mismatch  bool     `json:"mismatch,omitzero"`  // 各CPU的微码版本是否不一致，后期加载未全部完成时出现
+	Revisions []string `json:"revisions,omitzero"` // 版本不一致时各CPU上出现的全部版本
+}
+
+// ThermalThrottle 表示CPU过热降频计数，从/sys/devices/system/cpu/cpu*/thermal_throttle获取，
+// 计数为启动以来的累计值，持续增长说明散热存在问题
+type ThermalThrottle struct {
+	Throttled            string        `json:"throttled,omitzero"`              // 启动以来是否发生过降频：true、false
+	CoreThrottleCount    string        `json:"core_throttle_count,omitzero"`    // 所有逻辑CPU的核心降频次数之和
+	PackageThrottleCount string        `json:"package_throttle_count,omitzero"` // 所有物理CPU封装的降频次数之和，每个封装只计一次
+	CPUs                 []CPUThrottle `json:"cpus,omitzero"`                   // 降频次数非零的逻辑CPU
+}
+
+// CPUThrottle 表示单个逻辑CPU的降频计数
+type CPUThrottle struct {
+	CPU                  string `json:"cpu,omitzero"`                    // 逻辑CPU编号
+	PackageID            string `json:"package_id,omitzero"`             // 所属物理CPU封装编号
+	CoreThrottleCount    string `json:"core_throttle_count,omitzero"`    // 核心降频次数
+	PackageThrottleCount string `json:"package_throttle_count,omitzero"` // 所属封装的降频次数
+}
+
+// Limits 表示进程数和文件句柄的使用量与上限，用于排查 too many open files 等问题
+type Limits struct {
+	PIDCount        string `json:"pid_count,omitzero"`         // 当前进程数，统计/proc下的进程目录
+	PIDMax          string `json:"pid_max,omitzero"`           // 进程号上限，/proc/sys/kernel/pid_max
+	FileHandles     string `json:"file_handles,omitzero"`      // 已分配的文件句柄数，/proc/sys/fs/file-nr
+	FileHandlesMax  string `json:"file_handles_max,omitzero"`  // 系统文件句柄上限
+	NoFileSoftLimit string `json:"nofile_soft_limit,omitzero"` // 采集进程自身的打开文件数软限制
+	NoFileHardLimit string `json:"nofile_hard_limit,omitzero"` // 采集进程自身的打开文件数硬限制
+}
+
+// LoadAverage 表示系统负载，从/proc/loadavg获取
+type LoadAverage struct {
+	Load1            string `json:"load1,omitzero"`             // 1分钟平均负载
+	Load5            string `json:"load5,omitzero"`             // 5分钟平均负载
+	Load15           string `json:"load15,omitzero"`            // 15分钟平均负载
+	RunningProcesses string `json:"running_processes,omitzero"` // 正在运行的进程(线程)数
+	TotalProcesses   string `json:"total_processes,omitzero"`   // 进程(线程)总数
+}
+
+// Uptime 表示系统运行时间，从/proc/uptime获取
+type Uptime struct {
+	Seconds  string `json:"seconds,omitzero"`   // 启动以来的秒数
+	BootTime string `json:"boot_time,omitzero"` // 启动时间，RFC3339格式，取自/proc/stat的btime
+}
+
+// Kernel 表示运行中的内核信息，从/proc目录获取
+type Kernel struct {
+	Release string         `json:"release,omitzero"` // 内核版本号，等同于 uname -r
+	Version string         `json:"version,omitzero"` // /proc/version 完整内容
+	CmdLine string         `json:"cmdline,omitzero"` // 内核启动参数
+	Modules []KernelModule `json:"modules,omitzero"` // 已加载的内核模块
+}
+
+// KernelModule 表示已加载的内核模块，从/proc/modules获取
+type KernelModule struct {
+	Name     string   `json:"name,omitzero"`      // 模块名称
+	Size     string   `json:"size,omitzero"`      // 模块占用内存大小，单位字节
+	RefCount string   `json:"ref_count,omitzero"` // 引用计数
+	UsedBy   []string `json:"used_by,omitzero"`   // 依赖该模块的模块
+	State    string   `json:"state,omitzero"`     // 状态：Live、Loading、Unloading
+}
@@ -0,0 +1,35 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/redact"
+)
+
+// AnonymizingPublisher 在推送前将主机名替换为加盐的 HMAC-SHA256 摘要,同一主机的摘要保持不变,不修改原始数据
+type AnonymizingPublisher struct {
+	next Publisher
+	salt string
+}
+
+var _ Publisher = (*AnonymizingPublisher)(nil)
+
+// NewAnonymizingPublisher 创建主机名匿名化推送器
+func NewAnonymizingPublisher(next Publisher, salt string) *AnonymizingPublisher {
+	return &AnonymizingPublisher{next: next, salt: salt}
+}
+
+func (p *AnonymizingPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	if info == nil {
+		return p.next.Publish(ctx, info)
+	}
+
+	anonymized := *info
+	anonymized.Hostname = redact.HashHostname(info.Hostname, p.salt)
+	return p.next.Publish(ctx, &anonymized)
+}
+
+func (p *AnonymizingPublisher) Close() error {
+	return p.next.Close()
+}
@@ -0,0 +1,40 @@
+package publisher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/redact"
+)
+
+func TestAnonymizingPublisher(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewAnonymizingPublisher(next, "salt")
+
+	hosts := []string{"node-1", "node-2", "node-1"}
+	for _, host := range hosts {
+		info := &model.HardwareInfo{Hostname: host, Memory: &model.Memory{Total: 1}}
+		if err := p.Publish(context.Background(), info); err != nil {
+			t.Fatal(err)
+		}
+		if info.Hostname != host {
+			t.Errorf("Publish() modified the caller's data: Hostname = %q", info.Hostname)
+		}
+	}
+
+	got := next.published
+	if len(got) != 3 {
+		t.Fatalf("published %d, want 3", len(got))
+	}
+	if got[0].Hostname != redact.HashHostname("node-1", "salt") || got[0].Memory == nil {
+		t.Errorf("published %+v, want the hashed hostname and the rest unchanged", got[0])
+	}
+	if got[0].Hostname != got[2].Hostname || got[0].Hostname == got[1].Hostname {
+		t.Errorf("hostnames = %q, %q, %q, want stable per host and distinct between hosts", got[0].Hostname, got[1].Hostname, got[2].Hostname)
+	}
+
+	if err := p.Close(); err != nil || !next.closed {
+		t.Errorf("Close() = %v, closed = %v", err, next.closed)
+	}
+}
@@ -0,0 +1,154 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// ErrCircuitOpen 熔断器处于打开状态,推送被直接拒绝
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	defaultCooldown    = 30 * time.Second
+	defaultMaxCooldown = 30 * time.Minute
+)
+
+// BreakerState 表示熔断器状态
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // 正常推送
+	BreakerOpen                         // 熔断中,直接失败
+	BreakerHalfOpen                     // 冷却结束,放行一次探测
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker 包装一个 Publisher,连续失败 threshold 次后打开熔断,
+// 冷却期内 Publish 直接返回 ErrCircuitOpen;冷却结束后半开放行一次探测,
+// 探测成功则恢复,失败则冷却时间翻倍(指数退避,上限 MaxCooldown)后再次打开
+type CircuitBreaker struct {
+	next        Publisher
+	threshold   int
+	cooldown    time.Duration
+	MaxCooldown time.Duration // 冷却时间上限
+
+	mu          sync.Mutex
+	state       BreakerState
+	failures    int
+	curCooldown time.Duration
+	openedAt    time.Time
+	now         func() time.Time
+}
+
+var _ Publisher = (*CircuitBreaker)(nil)
+
+// NewCircuitBreaker 创建熔断器,threshold 为触发熔断的连续失败次数,cooldown 为初始冷却时间,
+// 不大于0时使用30秒(冷却时间为0时熔断打开后立即半开,等同于从不熔断)
+func NewCircuitBreaker(next Publisher, threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	return &CircuitBreaker{
+		next:        next,
+		threshold:   threshold,
+		cooldown:    cooldown,
+		MaxCooldown: defaultMaxCooldown,
+		curCooldown: cooldown,
+		now:         time.Now,
+	}
+}
+
+// State 返回熔断器当前状态
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refreshLocked()
+	return b.state
+}
+
+func (b *CircuitBreaker) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	b.mu.Lock()
+	b.refreshLocked()
+	if b.state == BreakerOpen {
+		b.mu.Unlock()
+		return ErrCircuitOpen
+	}
+	if b.state == BreakerHalfOpen {
+		// 半开状态下只放行一次探测,其余请求继续快速失败
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.mu.Unlock()
+		return b.probe(ctx, info)
+	}
+	b.mu.Unlock()
+
+	err := b.next.Publish(ctx, info)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		return nil
+	}
+
+	b.failures++
+	if b.state == BreakerClosed && b.failures >= b.threshold {
+		b.openLocked()
+	}
+	return err
+}
+
+func (b *CircuitBreaker) Close() error {
+	return b.next.Close()
+}
+
+func (b *CircuitBreaker) probe(ctx context.Context, info *model.HardwareInfo) error {
+	err := b.next.Publish(ctx, info)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		b.curCooldown = b.cooldown
+		return nil
+	}
+
+	b.curCooldown = min(b.curCooldown*2, max(b.MaxCooldown, b.cooldown))
+	b.openLocked()
+	return err
+}
+
+func (b *CircuitBreaker) openLocked() {
+	b.state = BreakerOpen
+	b.openedAt = b.now()
+}
+
+// refreshLocked 冷却时间结束后将打开状态切换为半开
+func (b *CircuitBreaker) refreshLocked() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.curCooldown {
+		b.state = BreakerHalfOpen
+	}
+}
@@ -0,0 +1,135 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// fakePublisher 记录收到的硬件信息,errs 依次作为每次推送的结果,用尽后推送成功
+type fakePublisher struct {
+	mu        sync.Mutex
+	errs      []error
+	published []*model.HardwareInfo
+	snapshots []*Snapshot
+	closed    bool
+}
+
+func (p *fakePublisher) nextErr() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	err := p.errs[0]
+	p.errs = p.errs[1:]
+	return err
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = append(p.published, info)
+	return p.nextErr()
+}
+
+func (p *fakePublisher) PublishSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.snapshots = append(p.snapshots, snapshot)
+	return p.nextErr()
+}
+
+func (p *fakePublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+func (p *fakePublisher) calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.published) + len(p.snapshots)
+}
+
+// fakeClock 手动推进的时钟
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestCircuitBreaker(t *testing.T) {
+	errDown := errors.New("broker down")
+	next := &fakePublisher{errs: []error{errDown, errDown, errDown, errDown}}
+	clock := &fakeClock{t: time.Unix(0, 0)}
+
+	b := NewCircuitBreaker(next, 2, time.Minute)
+	b.now = clock.Now
+	ctx := context.Background()
+	info := &model.HardwareInfo{}
+
+	// 连续失败 threshold 次后打开
+	for i := range 2 {
+		if err := b.Publish(ctx, info); !errors.Is(err, errDown) {
+			t.Fatalf("publish %d: err = %v, want %v", i, err, errDown)
+		}
+	}
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s, want open", b.State())
+	}
+
+	// 冷却期内快速失败,不调用下游
+	if err := b.Publish(ctx, info); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if next.calls() != 2 {
+		t.Fatalf("downstream called %d times, want 2", next.calls())
+	}
+
+	// 冷却结束后半开,探测失败则冷却时间翻倍
+	clock.Advance(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state = %s, want half-open", b.State())
+	}
+	if err := b.Publish(ctx, info); !errors.Is(err, errDown) {
+		t.Fatalf("probe err = %v, want %v", err, errDown)
+	}
+	clock.Advance(time.Minute)
+	if b.State() != BreakerOpen {
+		t.Fatalf("state after failed probe = %s, want open for the doubled cooldown", b.State())
+	}
+	clock.Advance(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state = %s, want half-open after 2m", b.State())
+	}
+
+	// 探测成功后恢复
+	if err := b.Publish(ctx, info); !errors.Is(err, errDown) {
+		t.Fatalf("probe err = %v, want %v", err, errDown)
+	}
+	clock.Advance(4 * time.Minute)
+	if err := b.Publish(ctx, info); err != nil {
+		t.Fatalf("probe err = %v, want success", err)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("state = %s, want closed", b.State())
+	}
+}
+
+func TestCircuitBreakerZeroCooldown(t *testing.T) {
+	next := &fakePublisher{errs: []error{errors.New("down")}}
+	clock := &fakeClock{t: time.Unix(0, 0)}
+
+	b := NewCircuitBreaker(next, 1, 0)
+	b.now = clock.Now
+
+	_ = b.Publish(context.Background(), &model.HardwareInfo{})
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s, want open with the default cooldown", b.State())
+	}
+	clock.Advance(defaultCooldown)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state = %s, want half-open after %s", b.State(), defaultCooldown)
+	}
+}
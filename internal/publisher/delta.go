@@ -0,0 +1,130 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// Snapshot 增量推送的消息信封。Full 为 true 时 Sections 包含全部模块,
+// 否则只包含相对上一次推送发生变化的模块,被移除的模块值为 null,由消费端合并
+type Snapshot struct {
+	Hostname  string                     `json:"hostname"`
+	Timestamp time.Time                  `json:"timestamp"`
+	Full      bool                       `json:"full"`
+	Sections  map[string]json.RawMessage `json:"sections"`
+	Labels    map[string]string          `json:"labels,omitempty"` // 硬件信息附带的静态标签
+}
+
+// SnapshotPublisher 支持推送增量信封的推送器
+type SnapshotPublisher interface {
+	Publisher
+	PublishSnapshot(ctx context.Context, snapshot *Snapshot) error
+}
+
+type fullSnapshotKey struct{}
+
+// WithFullSnapshot 返回要求本次推送为全量快照的 ctx,DeltaPublisher 收到后推送全量快照
+func WithFullSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fullSnapshotKey{}, true)
+}
+
+// fullSnapshotRequested 判断 ctx 是否要求推送全量快照
+func fullSnapshotRequested(ctx context.Context) bool {
+	v, _ := ctx.Value(fullSnapshotKey{}).(bool)
+	return v
+}
+
+// DeltaPublisher 只推送相对上一次成功推送发生变化的模块,以减少消息量,只有 model.VolatileFields
+// 变化的模块不推送;每 fullEvery 次推送一次全量快照,供消费端重新同步
+type DeltaPublisher struct {
+	next      SnapshotPublisher
+	fullEvery int
+
+	mu    sync.Mutex
+	last  *model.HardwareInfo // 上一次成功推送后消费端应持有的数据
+	count int                 // 成功推送的次数
+}
+
+var _ Publisher = (*DeltaPublisher)(nil)
+
+// NewDeltaPublisher 创建增量推送器,fullEvery <= 0 时只有首次推送为全量快照
+func NewDeltaPublisher(next SnapshotPublisher, fullEvery int) *DeltaPublisher {
+	return &DeltaPublisher{next: next, fullEvery: fullEvery}
+}
+
+func (p *DeltaPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	sections, err := splitSections(info)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := &Snapshot{
+		Hostname:  info.Hostname,
+		Timestamp: info.Timestamp,
+		Full:      p.last == nil || (p.fullEvery > 0 && p.count%p.fullEvery == 0) || fullSnapshotRequested(ctx),
+		Sections:  sections,
+		Labels:    info.Labels,
+	}
+	if !snapshot.Full {
+		snapshot.Sections = deltaSections(p.last.Diff(info, model.VolatileFields...), sections)
+	}
+
+	// 推送失败时保留上一次的状态,下一次增量会重新带上这些变化
+	if err := p.next.PublishSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+
+	p.last = info.Clone()
+	p.count++
+	return nil
+}
+
+func (p *DeltaPublisher) Close() error {
+	return p.next.Close()
+}
+
+// envelopeFields 放在信封中而不作为模块推送的顶层字段
+var envelopeFields = []string{"hostname", "timestamp", "labels"}
+
+// splitSections 将硬件信息按顶层 JSON 字段拆分为各模块,hostname、timestamp 和 labels 放在信封中
+func splitSections(info *model.HardwareInfo) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("marshal hardware info failed: %w", err)
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("split hardware info failed: %w", err)
+	}
+	for _, name := range envelopeFields {
+		delete(sections, name)
+	}
+
+	return sections, nil
+}
+
+// deltaSections 返回 diff 中新增或变化的模块在 cur 中的数据,被移除的模块置为 null
+func deltaSections(diff *model.HardwareDiff, cur map[string]json.RawMessage) map[string]json.RawMessage {
+	delta := make(map[string]json.RawMessage)
+	for _, section := range diff.Sections {
+		if slices.Contains(envelopeFields, section.Section) {
+			continue
+		}
+		if data, ok := cur[section.Section]; ok {
+			delta[section.Section] = data
+		} else if section.Change == model.ChangeRemoved {
+			delta[section.Section] = json.RawMessage("null")
+		}
+	}
+	return delta
+}
@@ -0,0 +1,171 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func sectionNames(s *Snapshot) []string {
+	return slices.Sorted(maps.Keys(s.Sections))
+}
+
+func TestDeltaPublisher(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewDeltaPublisher(next, 3)
+	ctx := context.Background()
+
+	info := &model.HardwareInfo{
+		Hostname: "node-1",
+		Labels:   map[string]string{"rack": "a1"},
+		System:   &model.System{BootID: "boot-1"},
+		Memory:   &model.Memory{Total: 1 << 30},
+		GPU:      []model.GPU{{Index: "0"}},
+	}
+	publish := func() *Snapshot {
+		t.Helper()
+		if err := p.Publish(ctx, info); err != nil {
+			t.Fatal(err)
+		}
+		return next.snapshots[len(next.snapshots)-1]
+	}
+
+	// 首次推送全量快照
+	s := publish()
+	if !s.Full || !slices.Equal(sectionNames(s), []string{"gpu", "memory", "system"}) {
+		t.Fatalf("first snapshot: full=%v sections=%v", s.Full, sectionNames(s))
+	}
+	if s.Hostname != "node-1" || s.Labels["rack"] != "a1" {
+		t.Errorf("envelope = %+v", s)
+	}
+
+	// 增量只包含变化的模块，被移除的模块为 null
+	info.Memory = &model.Memory{Total: 2 << 30}
+	info.GPU = nil
+	s = publish()
+	if s.Full || !slices.Equal(sectionNames(s), []string{"gpu", "memory"}) {
+		t.Fatalf("delta: full=%v sections=%v", s.Full, sectionNames(s))
+	}
+	if string(s.Sections["gpu"]) != "null" {
+		t.Errorf("removed gpu section = %s, want null", s.Sections["gpu"])
+	}
+
+	// 没有变化时推送空增量
+	s = publish()
+	if s.Full || len(s.Sections) != 0 {
+		t.Errorf("unchanged delta: full=%v sections=%v", s.Full, sectionNames(s))
+	}
+
+	// 每3次推送一次全量快照
+	s = publish()
+	if !s.Full || !slices.Equal(sectionNames(s), []string{"memory", "system"}) {
+		t.Errorf("periodic snapshot: full=%v sections=%v", s.Full, sectionNames(s))
+	}
+}
+
+func TestDeltaPublisherForcedFullSnapshot(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewDeltaPublisher(next, 0)
+	info := &model.HardwareInfo{System: &model.System{BootID: "boot-1"}}
+
+	for range 3 {
+		if err := p.Publish(context.Background(), info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Publish(WithFullSnapshot(context.Background()), info); err != nil {
+		t.Fatal(err)
+	}
+
+	var full []bool
+	for _, s := range next.snapshots {
+		full = append(full, s.Full)
+	}
+	if !slices.Equal(full, []bool{true, false, false, true}) {
+		t.Errorf("full flags = %v, want only the first and the forced snapshot", full)
+	}
+}
+
+func TestDeltaPublisherRetriesChangesAfterFailure(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewDeltaPublisher(next, 0)
+	ctx := context.Background()
+	info := &model.HardwareInfo{System: &model.System{BootID: "boot-1"}}
+
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+
+	info.Memory = &model.Memory{Total: 1}
+	next.errs = []error{errors.New("broker down")}
+	if err := p.Publish(ctx, info); err == nil {
+		t.Fatal("Publish() succeeded with a failing downstream")
+	}
+
+	// 失败的增量在下一次推送中重新带上
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	last := next.snapshots[len(next.snapshots)-1]
+	if last.Full || !slices.Equal(sectionNames(last), []string{"memory"}) {
+		t.Errorf("delta after failure: full=%v sections=%v", last.Full, sectionNames(last))
+	}
+}
+
+func TestDeltaPublisherDetectsInPlaceChanges(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewDeltaPublisher(next, 0)
+	ctx := context.Background()
+	info := &model.HardwareInfo{
+		System: &model.System{BootID: "boot-1"},
+		Memory: &model.Memory{Total: 1 << 30},
+	}
+
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	// 调用方在原对象上修改模块数据,增量仍能发现变化
+	info.Memory.Total = 2 << 30
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	last := next.snapshots[len(next.snapshots)-1]
+	if last.Full || !slices.Equal(sectionNames(last), []string{"memory"}) {
+		t.Errorf("delta: full=%v sections=%v, want only memory", last.Full, sectionNames(last))
+	}
+
+	// 只有标签变化时不推送任何模块
+	info.Labels = map[string]string{"rack": "a1"}
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	if last := next.snapshots[len(next.snapshots)-1]; len(last.Sections) != 0 {
+		t.Errorf("labels-only delta sections = %v, want none", sectionNames(last))
+	}
+}
+
+func TestDeltaPublisherSkipsVolatileChanges(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewDeltaPublisher(next, 0)
+	ctx := context.Background()
+	info := &model.HardwareInfo{
+		System: &model.System{BootID: "boot-1", Uptime: model.Uptime{Seconds: "100"}},
+		Memory: &model.Memory{Total: 1 << 30, Used: 1 << 20},
+	}
+
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	info.System.Uptime.Seconds = "160"
+	info.Memory.Used = 2 << 20
+	if err := p.Publish(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	if last := next.snapshots[len(next.snapshots)-1]; last.Full || len(last.Sections) != 0 {
+		t.Errorf("volatile-only delta: full=%v sections=%v, want none", last.Full, sectionNames(last))
+	}
+}
@@ -0,0 +1,192 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+const (
+	defaultInfluxBatchSize = 5000
+	defaultInfluxTimeout   = 10 * time.Second
+)
+
+// InfluxPublisher 将硬件信息转换为 InfluxDB line protocol,通过 HTTP /write 接口写入
+type InfluxPublisher struct {
+	writeURL  string
+	client    *http.Client
+	BatchSize int // 单次请求最多写入的行数
+}
+
+var _ Publisher = (*InfluxPublisher)(nil)
+
+// NewInfluxPublisher 创建 InfluxDB 推送器,addr 为 InfluxDB 地址(如 http://localhost:8086)
+func NewInfluxPublisher(addr, database string) (*InfluxPublisher, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("parse influxdb address %s failed: %w", addr, err)
+	}
+	u = u.JoinPath("write")
+
+	q := u.Query()
+	q.Set("db", database)
+	q.Set("precision", "ns")
+	u.RawQuery = q.Encode()
+
+	return &InfluxPublisher{
+		writeURL:  u.String(),
+		client:    &http.Client{Timeout: defaultInfluxTimeout},
+		BatchSize: defaultInfluxBatchSize,
+	}, nil
+}
+
+func (p *InfluxPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	lines := influxLines(info)
+
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultInfluxBatchSize
+	}
+
+	for start := 0; start < len(lines); start += batchSize {
+		end := min(start+batchSize, len(lines))
+		if err := p.write(ctx, lines[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *InfluxPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+func (p *InfluxPublisher) write(ctx context.Context, lines []string) error {
+	body := strings.Join(lines, "\n") + "\n"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.writeURL, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create influxdb request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("write to influxdb failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("influxdb returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// influxLines 按模块生成 line protocol,每个模块一个 measurement,主机名作为 tag
+func influxLines(info *model.HardwareInfo) []string {
+	ts := info.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	tags := "host=" + escapeInfluxTag(info.Hostname)
+
+	var lines []string
+	addLine := func(measurement string, fields []string) {
+		if len(fields) == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%s,%s %s %d", measurement, tags, strings.Join(fields, ","), ts.UnixNano()))
+	}
+
+	if info.System != nil {
+		addLine("system", influxNumbers(
+			"load1", info.System.LoadAverage.Load1,
+			"load5", info.System.LoadAverage.Load5,
+			"load15", info.System.LoadAverage.Load15,
+			"uptime_seconds", info.System.Uptime.Seconds,
+			"pid_count", info.System.Limits.PIDCount,
+			"file_handles", info.System.Limits.FileHandles,
+		))
+	}
+
+	if info.Memory != nil {
+		addLine("memory", []string{
+			influxUint("total", info.Memory.Total),
+			influxUint("used", info.Memory.Used),
+			influxUint("available", info.Memory.Available),
+			influxUint("swap_used", info.Memory.SwapUsed),
+			influxFloat("used_percent", info.Memory.UsedPercent),
+		})
+	}
+
+	if info.Disk != nil {
+		addLine("disk", []string{
+			influxInt("block_devices", int64(len(info.Disk.BlockDevices))),
+			influxInt("md_raids", int64(len(info.Disk.MDRaids))),
+			influxInt("nvmes", int64(len(info.Disk.NVMes))),
+		})
+	}
+
+	if info.Network != nil {
+		addLine("network", []string{
+			influxInt("interfaces", int64(len(info.Network.NetInterfaces))),
+			influxInt("bonds", int64(len(info.Network.BondInterfaces))),
+		})
+	}
+
+	if info.GPU != nil {
+		addLine("gpu", []string{influxInt("count", int64(len(info.GPU)))})
+	}
+
+	return lines
+}
+
+func influxInt(key string, v int64) string {
+	return key + "=" + strconv.FormatInt(v, 10) + "i"
+}
+
+// influxUint 以整数字段写入无符号值,超出 int64 的值截断为 math.MaxInt64;
+// InfluxDB 1.x 默认不支持 u 后缀的无符号字段
+func influxUint(key string, v uint64) string {
+	return influxInt(key, int64(min(v, math.MaxInt64)))
+}
+
+// influxNumbers 将成对的字段名和数值字符串转换为浮点字段,空值和无法解析的值跳过
+func influxNumbers(pairs ...string) []string {
+	var fields []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		v, err := strconv.ParseFloat(pairs[i+1], 64)
+		if err != nil {
+			continue
+		}
+		fields = append(fields, influxFloat(pairs[i], v))
+	}
+	return fields
+}
+
+func influxFloat(key string, v float64) string {
+	return key + "=" + strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// escapeInfluxTag 转义 tag 值中的逗号、等号和空格
+func escapeInfluxTag(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return influxTagEscaper.Replace(v)
+}
@@ -0,0 +1,98 @@
+package publisher
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestInfluxLines(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	info := &model.HardwareInfo{
+		Timestamp: ts,
+		Hostname:  "rack 1,node=2",
+		System: &model.System{
+			LoadAverage: model.LoadAverage{Load1: "0.5", Load5: "", Load15: "bad"},
+		},
+		Memory: &model.Memory{Total: 1 << 34, Used: math.MaxUint64, UsedPercent: 12.5},
+		GPU:    []model.GPU{{}, {}},
+	}
+
+	lines := influxLines(info)
+	want := []string{
+		`system,host=rack\ 1\,node\=2 load1=0.5 1700000000000000000`,
+		`memory,host=rack\ 1\,node\=2 total=17179869184i,used=9223372036854775807i,available=0i,swap_used=0i,used_percent=12.5 1700000000000000000`,
+		`gpu,host=rack\ 1\,node\=2 count=2i 1700000000000000000`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("influxLines() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestInfluxPublisherBatches(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+		query  string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		query = r.URL.Path + "?" + r.URL.RawQuery
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	p, err := NewInfluxPublisher(srv.URL, "hw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	p.BatchSize = 2
+
+	info := &model.HardwareInfo{
+		Hostname: "node-1",
+		System:   &model.System{LoadAverage: model.LoadAverage{Load1: "1"}},
+		Memory:   &model.Memory{Total: 1},
+		Disk:     &model.Storage{},
+	}
+	if err := p.Publish(context.Background(), info); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("got %d requests, want 2: %q", len(bodies), bodies)
+	}
+	if strings.Count(bodies[0], "\n") != 2 || strings.Count(bodies[1], "\n") != 1 {
+		t.Errorf("unexpected batches: %q", bodies)
+	}
+	if query != "/write?db=hw&precision=ns" {
+		t.Errorf("request = %s, want /write?db=hw&precision=ns", query)
+	}
+}
+
+func TestInfluxPublisherError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	p, err := NewInfluxPublisher(srv.URL, "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Publish(context.Background(), &model.HardwareInfo{GPU: []model.GPU{{}}})
+	if err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Fatalf("Publish() error = %v, want the server message", err)
+	}
+}
@@ -0,0 +1,45 @@
+package publisher
+
+import (
+	"context"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/metrics"
+)
+
+// InstrumentedPublisher 包装一个 Publisher,记录推送耗时和成功/失败次数
+type InstrumentedPublisher struct {
+	next    Publisher
+	name    string
+	metrics metrics.Metrics
+}
+
+var _ Publisher = (*InstrumentedPublisher)(nil)
+
+// NewInstrumentedPublisher 创建带指标上报的推送器,name 作为指标的 publisher 标签
+func NewInstrumentedPublisher(next Publisher, name string, m metrics.Metrics) *InstrumentedPublisher {
+	return &InstrumentedPublisher{
+		next:    next,
+		name:    name,
+		metrics: metrics.OrNop(m),
+	}
+}
+
+func (p *InstrumentedPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	start := time.Now()
+	err := p.next.Publish(ctx, info)
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	p.metrics.ObserveDuration("publisher_publish_duration_seconds", time.Since(start), map[string]string{"publisher": p.name})
+	p.metrics.IncCounter("publisher_publish_total", map[string]string{"publisher": p.name, "result": result})
+
+	return err
+}
+
+func (p *InstrumentedPublisher) Close() error {
+	return p.next.Close()
+}
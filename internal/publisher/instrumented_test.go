@@ -0,0 +1,72 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// recordingMetrics 记录计数器的累计值和耗时的样本数,键为指标名和排序后的标签
+type recordingMetrics struct {
+	mu        sync.Mutex
+	counters  map[string]int
+	durations map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{counters: map[string]int{}, durations: map[string]int{}}
+}
+
+func metricKey(name string, labels map[string]string) string {
+	key := name
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		key += fmt.Sprintf(",%s=%s", k, labels[k])
+	}
+	return key
+}
+
+func (m *recordingMetrics) IncCounter(name string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey(name, labels)]++
+}
+
+func (m *recordingMetrics) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations[metricKey(name, labels)]++
+}
+
+func TestInstrumentedPublisher(t *testing.T) {
+	m := newRecordingMetrics()
+	next := &fakePublisher{errs: []error{nil, errors.New("down")}}
+	p := NewInstrumentedPublisher(next, "kafka", m)
+
+	_ = p.Publish(context.Background(), &model.HardwareInfo{})
+	_ = p.Publish(context.Background(), &model.HardwareInfo{})
+
+	want := map[string]int{
+		"publisher_publish_total,publisher=kafka,result=success": 1,
+		"publisher_publish_total,publisher=kafka,result=failure": 1,
+	}
+	if !maps.Equal(m.counters, want) {
+		t.Errorf("counters = %v, want %v", m.counters, want)
+	}
+	if got := m.durations["publisher_publish_duration_seconds,publisher=kafka"]; got != 2 {
+		t.Errorf("duration samples = %d, want 2", got)
+	}
+}
+
+func TestInstrumentedPublisherNilMetrics(t *testing.T) {
+	p := NewInstrumentedPublisher(&fakePublisher{}, "stdout", nil)
+	if err := p.Publish(context.Background(), &model.HardwareInfo{}); err != nil {
+		t.Fatal(err)
+	}
+}
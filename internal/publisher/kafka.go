@@ -0,0 +1,81 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// contentTypeHeader 消息头中标明编码方式的键,消费端按其选择解码方式
+const contentTypeHeader = "content-type"
+
+// messageWriter 是 kafka.Writer 中推送用到的方法
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaPublisher 将每次采集结果作为一条消息推送到 Kafka,消息键为主机名,
+// 同一主机的消息落在同一分区,消费端按顺序处理
+type KafkaPublisher struct {
+	writer messageWriter
+
+	// Serializer 硬件信息的编码方式,为 nil 时使用 JSON,编码方式写入消息头 content-type
+	Serializer Serializer
+}
+
+var _ SnapshotPublisher = (*KafkaPublisher)(nil)
+
+// NewKafkaPublisher 创建推送到 topic 的 Kafka 推送器
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	serializer := p.Serializer
+	if serializer == nil {
+		serializer = JSONSerializer{}
+	}
+
+	data, err := serializer.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal hardware info failed: %w", err)
+	}
+	return p.write(ctx, info.Hostname, data, serializer.ContentType())
+}
+
+// PublishSnapshot 推送增量信封,信封固定以 JSON 编码
+func (p *KafkaPublisher) PublishSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal hardware info failed: %w", err)
+	}
+	return p.write(ctx, snapshot.Hostname, data, JSONSerializer{}.ContentType())
+}
+
+func (p *KafkaPublisher) write(ctx context.Context, key string, data []byte, contentType string) error {
+	msg := kafka.Message{
+		Key:     []byte(key),
+		Value:   data,
+		Headers: []kafka.Header{{Key: contentTypeHeader, Value: []byte(contentType)}},
+	}
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("write to kafka failed: %w", err)
+	}
+	return nil
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
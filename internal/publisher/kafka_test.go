@@ -0,0 +1,101 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// fakeWriter 记录写入的 Kafka 消息
+type fakeWriter struct {
+	msgs   []kafka.Message
+	err    error
+	closed bool
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	w := &fakeWriter{}
+	p := &KafkaPublisher{writer: w, Serializer: MsgPackSerializer{}}
+	ctx := context.Background()
+
+	if err := p.Publish(ctx, testHardwareInfo()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PublishSnapshot(ctx, &Snapshot{Hostname: "node-1", Full: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(w.msgs))
+	}
+
+	// 消息键为主机名，编码方式写入消息头
+	full, snapshot := w.msgs[0], w.msgs[1]
+	if string(full.Key) != "node-1" || header(full, contentTypeHeader) != "application/msgpack" {
+		t.Errorf("message key %q, content-type %q", full.Key, header(full, contentTypeHeader))
+	}
+	want, _ := MsgPackSerializer{}.Marshal(testHardwareInfo())
+	if string(full.Value) != string(want) {
+		t.Error("message value is not the MessagePack encoding")
+	}
+
+	// 增量信封固定为 JSON
+	var decoded Snapshot
+	if err := json.Unmarshal(snapshot.Value, &decoded); err != nil || !decoded.Full {
+		t.Errorf("snapshot value %s: %v", snapshot.Value, err)
+	}
+	if string(snapshot.Key) != "node-1" || header(snapshot, contentTypeHeader) != "application/json" {
+		t.Errorf("snapshot key %q, content-type %q", snapshot.Key, header(snapshot, contentTypeHeader))
+	}
+
+	if err := p.Close(); err != nil || !w.closed {
+		t.Errorf("Close() = %v, writer closed = %v", err, w.closed)
+	}
+}
+
+func TestKafkaPublisherDefaultsToJSON(t *testing.T) {
+	w := &fakeWriter{}
+	p := &KafkaPublisher{writer: w}
+
+	if err := p.Publish(context.Background(), &model.HardwareInfo{Hostname: "node-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if header(w.msgs[0], contentTypeHeader) != "application/json" {
+		t.Errorf("content-type = %q, want application/json", header(w.msgs[0], contentTypeHeader))
+	}
+}
+
+func TestKafkaPublisherWriteError(t *testing.T) {
+	errDown := errors.New("leader not available")
+	p := &KafkaPublisher{writer: &fakeWriter{err: errDown}}
+
+	if err := p.Publish(context.Background(), &model.HardwareInfo{}); !errors.Is(err, errDown) {
+		t.Errorf("Publish() error = %v, want it to wrap %v", err, errDown)
+	}
+}
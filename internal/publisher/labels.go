@@ -0,0 +1,40 @@
+package publisher
+
+import (
+	"context"
+	"maps"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// LabelingPublisher 在推送前将静态标签(如机房、机架、环境)写入硬件信息的 labels 字段,
+// 与具体的推送方式无关;硬件信息已有同名标签时以配置的标签为准,不修改原始数据
+type LabelingPublisher struct {
+	next   Publisher
+	labels map[string]string
+}
+
+var _ Publisher = (*LabelingPublisher)(nil)
+
+// NewLabelingPublisher 创建附加静态标签的推送器
+func NewLabelingPublisher(next Publisher, labels map[string]string) *LabelingPublisher {
+	return &LabelingPublisher{next: next, labels: labels}
+}
+
+func (p *LabelingPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	if info == nil || len(p.labels) == 0 {
+		return p.next.Publish(ctx, info)
+	}
+
+	labeled := *info
+	labeled.Labels = maps.Clone(info.Labels)
+	if labeled.Labels == nil {
+		labeled.Labels = make(map[string]string, len(p.labels))
+	}
+	maps.Copy(labeled.Labels, p.labels)
+	return p.next.Publish(ctx, &labeled)
+}
+
+func (p *LabelingPublisher) Close() error {
+	return p.next.Close()
+}
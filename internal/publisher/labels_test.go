@@ -0,0 +1,64 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestLabelingPublisherPayload(t *testing.T) {
+	var buf bytes.Buffer
+	labels := map[string]string{"datacenter": "sh1", "rack": "r12", "environment": "prod"}
+	p := NewLabelingPublisher(NewStdoutPublisher(&buf), labels)
+
+	if err := p.Publish(context.Background(), &model.HardwareInfo{Hostname: "node-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		Hostname string            `json:"hostname"`
+		Labels   map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v\n%s", err, buf.String())
+	}
+	if payload.Hostname != "node-1" || !maps.Equal(payload.Labels, labels) {
+		t.Errorf("payload = %s, want the configured labels", buf.String())
+	}
+}
+
+func TestLabelingPublisherMerge(t *testing.T) {
+	next := &fakePublisher{}
+	p := NewLabelingPublisher(next, map[string]string{"rack": "r12"})
+
+	info := &model.HardwareInfo{Labels: map[string]string{"rack": "old", "team": "infra"}}
+	if err := p.Publish(context.Background(), info); err != nil {
+		t.Fatal(err)
+	}
+
+	// 同名标签以配置为准,不修改原始数据
+	want := map[string]string{"rack": "r12", "team": "infra"}
+	if got := next.published[0].Labels; !maps.Equal(got, want) {
+		t.Errorf("published labels = %v, want %v", got, want)
+	}
+	if info.Labels["rack"] != "old" {
+		t.Errorf("original labels modified: %v", info.Labels)
+	}
+
+	// 没有配置标签时原样推送
+	next = &fakePublisher{}
+	p = NewLabelingPublisher(next, nil)
+	if err := p.Publish(context.Background(), info); err != nil {
+		t.Fatal(err)
+	}
+	if next.published[0] != info {
+		t.Error("info without configured labels was copied")
+	}
+	if err := p.Close(); err != nil || !next.closed {
+		t.Errorf("Close() = %v, closed = %v", err, next.closed)
+	}
+}
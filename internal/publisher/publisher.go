@@ -0,0 +1,13 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// Publisher 将采集到的硬件信息推送到下游系统
+type Publisher interface {
+	Publish(ctx context.Context, info *model.HardwareInfo) error
+	Close() error
+}
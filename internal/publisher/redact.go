@@ -0,0 +1,34 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/redact"
+)
+
+// RedactingPublisher 在推送前对敏感字段(序列号、MAC地址、管理IP等)脱敏,不修改原始数据
+type RedactingPublisher struct {
+	next     Publisher
+	redactor *redact.Redactor
+}
+
+var _ Publisher = (*RedactingPublisher)(nil)
+
+// NewRedactingPublisher 创建脱敏推送器
+func NewRedactingPublisher(next Publisher, r *redact.Redactor) *RedactingPublisher {
+	return &RedactingPublisher{next: next, redactor: r}
+}
+
+func (p *RedactingPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	redacted, err := redact.Apply(p.redactor, info)
+	if err != nil {
+		return fmt.Errorf("redact hardware info failed: %w", err)
+	}
+	return p.next.Publish(ctx, redacted)
+}
+
+func (p *RedactingPublisher) Close() error {
+	return p.next.Close()
+}
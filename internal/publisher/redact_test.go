@@ -0,0 +1,41 @@
+package publisher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/redact"
+)
+
+func TestRedactingPublisher(t *testing.T) {
+	r, err := redact.New([]string{"mac_address", "product.product_serial"}, redact.ModeToken, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := &fakePublisher{}
+	p := NewRedactingPublisher(next, r)
+
+	info := &model.HardwareInfo{
+		Hostname: "node-1",
+		Product:  &model.Product{ProductSerial: "SN123", ProductName: "R750"},
+		Network: &model.Network{NetInterfaces: []model.NetInterface{
+			{DeviceName: "eth0", MACAddress: "52:54:00:12:34:56"},
+		}},
+	}
+	if err := p.Publish(context.Background(), info); err != nil {
+		t.Fatal(err)
+	}
+
+	got := next.published[0]
+	if got.Product.ProductSerial != redact.Token || got.Network.NetInterfaces[0].MACAddress != redact.Token {
+		t.Errorf("sensitive fields not redacted: %+v, %+v", got.Product, got.Network.NetInterfaces[0])
+	}
+	if got.Hostname != "node-1" || got.Product.ProductName != "R750" || got.Network.NetInterfaces[0].DeviceName != "eth0" {
+		t.Errorf("other fields changed: %+v", got)
+	}
+	// 原始数据不被修改
+	if info.Product.ProductSerial != "SN123" || info.Network.NetInterfaces[0].MACAddress != "52:54:00:12:34:56" {
+		t.Errorf("input modified: %+v", info)
+	}
+}
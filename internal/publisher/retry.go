@@ -0,0 +1,31 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/retry"
+)
+
+// RetryingPublisher 按重试策略重试失败的推送,应放在熔断器内层,使熔断器每个周期只计一次失败
+type RetryingPublisher struct {
+	next   Publisher
+	policy retry.Policy
+}
+
+var _ Publisher = (*RetryingPublisher)(nil)
+
+// NewRetryingPublisher 创建重试推送器
+func NewRetryingPublisher(next Publisher, policy retry.Policy) *RetryingPublisher {
+	return &RetryingPublisher{next: next, policy: policy}
+}
+
+func (p *RetryingPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	return p.policy.Do(ctx, func(ctx context.Context) error {
+		return p.next.Publish(ctx, info)
+	})
+}
+
+func (p *RetryingPublisher) Close() error {
+	return p.next.Close()
+}
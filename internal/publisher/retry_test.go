@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zenithax-cc/diting/internal/model"
+	"github.com/zenithax-cc/diting/pkg/retry"
+)
+
+func TestRetryingPublisher(t *testing.T) {
+	errDown := errors.New("broker down")
+	policy := retry.Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+	info := &model.HardwareInfo{Hostname: "node-1"}
+
+	// 重试后成功
+	next := &fakePublisher{errs: []error{errDown, errDown}}
+	p := NewRetryingPublisher(next, policy)
+	if err := p.Publish(context.Background(), info); err != nil {
+		t.Fatalf("Publish() = %v, want success after retries", err)
+	}
+	if next.calls() != 3 || next.published[2] != info {
+		t.Errorf("downstream called %d times, want 3 with the same data", next.calls())
+	}
+
+	// 用尽重试次数后返回最后一次错误
+	next = &fakePublisher{errs: []error{errDown, errDown, errDown, errDown}}
+	p = NewRetryingPublisher(next, policy)
+	if err := p.Publish(context.Background(), info); !errors.Is(err, errDown) {
+		t.Fatalf("Publish() = %v, want %v", err, errDown)
+	}
+	if next.calls() != 3 {
+		t.Errorf("downstream called %d times, want 3", next.calls())
+	}
+
+	if err := p.Close(); err != nil || !next.closed {
+		t.Errorf("Close() = %v, closed = %v", err, next.closed)
+	}
+}
+
+func TestRetryingPublisherInsideBreaker(t *testing.T) {
+	errDown := errors.New("broker down")
+	next := &fakePublisher{errs: []error{errDown, errDown, errDown, errDown}}
+	b := NewCircuitBreaker(NewRetryingPublisher(next, retry.Policy{MaxAttempts: 2}), 2, time.Minute)
+
+	// 每次推送的多次重试只计为熔断器的一次失败
+	_ = b.Publish(context.Background(), &model.HardwareInfo{})
+	if b.State() != BreakerClosed {
+		t.Fatalf("state = %s after one failed publish, want closed", b.State())
+	}
+	_ = b.Publish(context.Background(), &model.HardwareInfo{})
+	if b.State() != BreakerOpen || next.calls() != 4 {
+		t.Errorf("state = %s after %d attempts, want open after two publishes", b.State(), next.calls())
+	}
+}
@@ -0,0 +1,99 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// RoutingPublisher 按模块(顶层JSON字段,如 system、disk、network)将硬件信息拆分到不同的 topic,
+// 未配置路由的模块推送到默认 topic。每条消息只包含路由到该 topic 的模块,以及 hostname 和 timestamp
+type RoutingPublisher struct {
+	defaultTopic string
+	routes       map[string]string    // 模块 -> topic
+	topics       map[string]Publisher // topic -> 推送器
+}
+
+var _ Publisher = (*RoutingPublisher)(nil)
+
+// NewRoutingPublisher 创建按模块路由的推送器,newPublisher 为每个 topic(含默认 topic)创建一个推送器
+func NewRoutingPublisher(defaultTopic string, routes map[string]string, newPublisher func(topic string) Publisher) *RoutingPublisher {
+	p := &RoutingPublisher{
+		defaultTopic: defaultTopic,
+		routes:       routes,
+		topics:       map[string]Publisher{defaultTopic: newPublisher(defaultTopic)},
+	}
+	for _, topic := range routes {
+		if _, ok := p.topics[topic]; !ok {
+			p.topics[topic] = newPublisher(topic)
+		}
+	}
+	return p
+}
+
+// topicOf 返回模块路由到的 topic
+func (p *RoutingPublisher) topicOf(section string) string {
+	if topic, ok := p.routes[section]; ok {
+		return topic
+	}
+	return p.defaultTopic
+}
+
+// Publish 按 topic 拆分后依次推送,某个 topic 推送失败不影响其他 topic,返回全部错误
+func (p *RoutingPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	sections, err := splitSections(info)
+	if err != nil {
+		return err
+	}
+
+	grouped := make(map[string]map[string]json.RawMessage)
+	for name, data := range sections {
+		topic := p.topicOf(name)
+		if grouped[topic] == nil {
+			grouped[topic] = make(map[string]json.RawMessage)
+		}
+		grouped[topic][name] = data
+	}
+
+	var errs []error
+	for _, topic := range slices.Sorted(maps.Keys(grouped)) {
+		part, err := partialInfo(info, grouped[topic])
+		if err != nil {
+			return err
+		}
+		if err := p.topics[topic].Publish(ctx, part); err != nil {
+			errs = append(errs, fmt.Errorf("publish to topic %s failed: %w", topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *RoutingPublisher) Close() error {
+	var errs []error
+	for _, pub := range p.topics {
+		errs = append(errs, pub.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// partialInfo 返回只包含 sections 中模块的 HardwareInfo,hostname 和 timestamp 取自 info
+func partialInfo(info *model.HardwareInfo, sections map[string]json.RawMessage) (*model.HardwareInfo, error) {
+	data, err := json.Marshal(sections)
+	if err != nil {
+		return nil, fmt.Errorf("marshal hardware info sections failed: %w", err)
+	}
+
+	part := &model.HardwareInfo{}
+	if err := json.Unmarshal(data, part); err != nil {
+		return nil, fmt.Errorf("build hardware info sections failed: %w", err)
+	}
+	part.Hostname = info.Hostname
+	part.Timestamp = info.Timestamp
+	part.Labels = info.Labels
+	return part, nil
+}
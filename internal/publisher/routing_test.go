@@ -0,0 +1,80 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestRoutingPublisher(t *testing.T) {
+	errDown := errors.New("broker down")
+	topics := make(map[string]*fakePublisher)
+	p := NewRoutingPublisher("hw", map[string]string{"memory": "hw-metrics", "gpu": "hw-metrics", "disk": "hw-disk"}, func(topic string) Publisher {
+		topics[topic] = &fakePublisher{}
+		return topics[topic]
+	})
+	topics["hw-disk"].errs = []error{errDown}
+
+	info := testHardwareInfo()
+	info.Labels = map[string]string{"rack": "r12"}
+	info.Disk = &model.Storage{}
+	err := p.Publish(context.Background(), info)
+
+	// 某个 topic 失败不影响其他 topic
+	if !errors.Is(err, errDown) || !strings.Contains(err.Error(), "topic hw-disk") {
+		t.Fatalf("Publish() = %v, want the hw-disk failure", err)
+	}
+	if len(topics) != 3 {
+		t.Fatalf("created publishers for %d topics, want 3", len(topics))
+	}
+	for topic, pub := range topics {
+		if pub.calls() != 1 {
+			t.Fatalf("topic %s received %d messages, want 1", topic, pub.calls())
+		}
+		// 每条消息都带 hostname、timestamp 和标签
+		got := pub.published[0]
+		if got.Hostname != "node-1" || !got.Timestamp.Equal(info.Timestamp) || got.Labels["rack"] != "r12" {
+			t.Errorf("topic %s message = %+v, want the identity fields", topic, got)
+		}
+	}
+
+	// 每个模块只出现在路由到的 topic,未配置路由的模块进入默认 topic
+	def, metrics, disk := topics["hw"].published[0], topics["hw-metrics"].published[0], topics["hw-disk"].published[0]
+	if def.System == nil || def.Memory != nil || def.GPU != nil || def.Disk != nil {
+		t.Errorf("default topic message = %+v, want only system", def)
+	}
+	if metrics.Memory == nil || metrics.Memory.Total != info.Memory.Total || len(metrics.GPU) != 1 || metrics.System != nil {
+		t.Errorf("hw-metrics message = %+v, want memory and gpu", metrics)
+	}
+	if disk.Disk == nil || disk.System != nil || disk.Memory != nil {
+		t.Errorf("hw-disk message = %+v, want only disk", disk)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for topic, pub := range topics {
+		if !pub.closed {
+			t.Errorf("topic %s publisher not closed", topic)
+		}
+	}
+}
+
+func TestRoutingPublisherSkipsEmptyTopics(t *testing.T) {
+	topics := make(map[string]*fakePublisher)
+	p := NewRoutingPublisher("hw", map[string]string{"gpu": "hw-gpu"}, func(topic string) Publisher {
+		topics[topic] = &fakePublisher{}
+		return topics[topic]
+	})
+
+	// 没有路由到 hw-gpu 的模块时不推送空消息
+	if err := p.Publish(context.Background(), &model.HardwareInfo{Hostname: "node-1", Memory: &model.Memory{Total: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if topics["hw"].calls() != 1 || topics["hw-gpu"].calls() != 0 {
+		t.Errorf("hw received %d, hw-gpu received %d messages, want 1 and 0", topics["hw"].calls(), topics["hw-gpu"].calls())
+	}
+}
@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Serializer 推送时消息的编码方式
+type Serializer interface {
+	Marshal(v any) ([]byte, error)
+	ContentType() string
+}
+
+// NewSerializer 按名称创建编码器,支持 json(默认)和 msgpack
+func NewSerializer(name string) (Serializer, error) {
+	switch name {
+	case "", "json":
+		return JSONSerializer{}, nil
+	case "msgpack":
+		return MsgPackSerializer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported serializer: %s", name)
+	}
+}
+
+// JSONSerializer 以紧凑 JSON 编码
+type JSONSerializer struct{}
+
+func (JSONSerializer) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONSerializer) ContentType() string {
+	return "application/json"
+}
+
+// MsgPackSerializer 以 MessagePack 编码,字段名沿用 json 标签,与 JSON 输出保持一致;
+// msgpack 不识别 omitzero,零值字段统一省略,否则编码结果比 JSON 还大
+type MsgPackSerializer struct{}
+
+func (MsgPackSerializer) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.SetOmitEmpty(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgPackSerializer) ContentType() string {
+	return "application/msgpack"
+}
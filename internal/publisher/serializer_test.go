@@ -0,0 +1,100 @@
+package publisher
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func testHardwareInfo() *model.HardwareInfo {
+	return &model.HardwareInfo{
+		Timestamp: time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC),
+		Hostname:  "node-1",
+		System:    &model.System{BootID: "boot-1", Kernel: model.Kernel{Release: "6.8.0"}},
+		Memory:    &model.Memory{Total: 64 << 30, Used: 16 << 30, UsedPercent: 25},
+		GPU:       []model.GPU{{Index: "0", Name: "NVIDIA H100", NVLink: true}},
+	}
+}
+
+func TestSerializersRoundTrip(t *testing.T) {
+	decoders := map[string]func([]byte, any) error{
+		"json": json.Unmarshal,
+		"msgpack": func(data []byte, v any) error {
+			dec := msgpack.NewDecoder(bytes.NewReader(data))
+			dec.SetCustomStructTag("json")
+			return dec.Decode(v)
+		},
+	}
+	contentTypes := map[string]string{"json": "application/json", "msgpack": "application/msgpack"}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewSerializer(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.ContentType() != contentTypes[name] {
+				t.Errorf("ContentType() = %q, want %q", s.ContentType(), contentTypes[name])
+			}
+
+			in := testHardwareInfo()
+			data, err := s.Marshal(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out model.HardwareInfo
+			if err := decode(data, &out); err != nil {
+				t.Fatal(err)
+			}
+			if !out.Timestamp.Equal(in.Timestamp) {
+				t.Errorf("timestamp = %v, want %v", out.Timestamp, in.Timestamp)
+			}
+			out.Timestamp = in.Timestamp
+			if !reflect.DeepEqual(&out, in) {
+				t.Errorf("round trip =\n%+v\nwant\n%+v", out, *in)
+			}
+		})
+	}
+}
+
+func TestMsgPackUsesJSONFieldNames(t *testing.T) {
+	data, err := MsgPackSerializer{}.Marshal(testHardwareInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var generic map[string]any
+	if err := msgpack.Unmarshal(data, &generic); err != nil {
+		t.Fatal(err)
+	}
+	if generic["hostname"] != "node-1" {
+		t.Errorf("msgpack keys = %v, want the JSON field names", generic)
+	}
+	if len(data) >= len(mustJSON(t, testHardwareInfo())) {
+		t.Errorf("msgpack encoding (%d bytes) is not smaller than JSON", len(data))
+	}
+}
+
+func TestNewSerializer(t *testing.T) {
+	if s, err := NewSerializer(""); err != nil || s.ContentType() != "application/json" {
+		t.Errorf("NewSerializer(\"\") = %v, %v, want JSON", s, err)
+	}
+	if _, err := NewSerializer("protobuf"); err == nil {
+		t.Error("NewSerializer(protobuf) succeeded")
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,100 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+// StdoutPublisher 将每次采集结果以一行 JSON(NDJSON)写入标准输出或指定的 io.Writer,
+// 便于 sidecar 直接读取容器标准输出,无需部署消息队列
+type StdoutPublisher struct {
+	mu  sync.Mutex
+	out io.Writer
+
+	// Serializer 硬件信息的编码方式,为 nil 时每次输出一行 JSON。
+	// 非 JSON 编码时不追加换行,输出为连续的编码数据流
+	Serializer Serializer
+}
+
+var _ SnapshotPublisher = (*StdoutPublisher)(nil)
+
+// NewStdoutPublisher 创建标准输出推送器,out 为 nil 时写入 os.Stdout
+func NewStdoutPublisher(out io.Writer) *StdoutPublisher {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &StdoutPublisher{out: out}
+}
+
+func (p *StdoutPublisher) Publish(ctx context.Context, info *model.HardwareInfo) error {
+	if p.Serializer == nil {
+		return p.writeLine(ctx, info)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := p.Serializer.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal hardware info failed: %w", err)
+	}
+	if _, ok := p.Serializer.(JSONSerializer); ok {
+		data = append(data, '\n')
+	}
+
+	return p.write(data)
+}
+
+// PublishSnapshot 将增量信封以一行 JSON 写入
+func (p *StdoutPublisher) PublishSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	return p.writeLine(ctx, snapshot)
+}
+
+func (p *StdoutPublisher) writeLine(ctx context.Context, v any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal hardware info failed: %w", err)
+	}
+	data = append(data, '\n')
+
+	return p.write(data)
+}
+
+func (p *StdoutPublisher) write(data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 整行一次写入,避免多个 goroutine 的输出交错
+	if _, err := p.out.Write(data); err != nil {
+		return fmt.Errorf("write to stdout failed: %w", err)
+	}
+
+	if f, ok := p.out.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return fmt.Errorf("flush stdout failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *StdoutPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if f, ok := p.out.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package publisher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/zenithax-cc/diting/internal/model"
+)
+
+func TestStdoutPublisherWritesNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewStdoutPublisher(&buf)
+
+	const n = 20
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info := &model.HardwareInfo{Hostname: "node-1", GPU: make([]model.GPU, 8)}
+			if err := p.Publish(context.Background(), info); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var info model.HardwareInfo
+		if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v", lines, err)
+		}
+		if info.Hostname != "node-1" || len(info.GPU) != 8 {
+			t.Fatalf("line %d = %+v", lines, info)
+		}
+		lines++
+	}
+	if lines != n {
+		t.Errorf("got %d lines, want %d", lines, n)
+	}
+}
+
+func TestStdoutPublisherCanceled(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewStdoutPublisher(&buf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Publish(ctx, &model.HardwareInfo{}); err == nil {
+		t.Fatal("Publish() with a canceled context succeeded")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q after cancellation", buf.String())
+	}
+}
@@ -0,0 +1,63 @@
+// Package testutil 提供采集模块测试共用的sysfs和外部命令桩
+package testutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// FakeRoot 在临时目录中创建 files 中的文件并将其设为主机根目录，键为主机上的绝对路径，测试结束后恢复
+func FakeRoot(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		WriteFile(t, root, path, content)
+	}
+	utils.SetRoot(root)
+	t.Cleanup(func() { utils.SetRoot("") })
+	return root
+}
+
+// WriteFile 在 root 下创建 path 文件，必要时创建上级目录
+func WriteFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Symlink 在 root 下创建指向 target 的符号链接 path
+func Symlink(t *testing.T, root, target, path string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, full); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// FakeCommands 在测试期间用 outputs 代替外部命令，键为以空格连接的命令行，未列出的命令返回错误
+func FakeCommands(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		cmdline := strings.Join(append([]string{name}, args...), " ")
+		if out, ok := outputs[cmdline]; ok {
+			return []byte(out), nil
+		}
+		return nil, errors.New("unexpected command: " + cmdline)
+	})
+	t.Cleanup(func() { executor.SetRunner(nil) })
+}
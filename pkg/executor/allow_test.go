@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAllowedCommands(t *testing.T) {
+	var spawned []string
+	SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		spawned = append(spawned, name)
+		return nil, nil
+	})
+	SetAllowedCommands([]string{"dmidecode", " /usr/sbin/ethtool ", ""})
+	t.Cleanup(func() {
+		SetRunner(nil)
+		SetAllowedCommands(nil)
+	})
+
+	for _, name := range []string{"dmidecode", "/usr/sbin/ethtool", "/usr/sbin/../sbin/ethtool"} {
+		if _, err := Execute(name); err != nil {
+			t.Errorf("Execute(%s) err = %v, want it allowed", name, err)
+		}
+	}
+
+	// A bare name does not permit other paths and a path does not permit the bare name.
+	for _, name := range []string{"lspci", "ethtool", "/tmp/dmidecode"} {
+		if _, err := Execute(name); !errors.Is(err, ErrCommandNotAllowed) {
+			t.Errorf("Execute(%s) err = %v, want ErrCommandNotAllowed", name, err)
+		}
+	}
+	if _, err := ExecuteShell("dmidecode"); !errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("ExecuteShell() err = %v, want ErrCommandNotAllowed without bash listed", err)
+	}
+	if res, err := Run(context.Background(), "lspci"); res != nil || !errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("Run(lspci) = %+v, %v, want no result", res, err)
+	}
+
+	if len(spawned) != 3 {
+		t.Errorf("spawned %q, want only the allowed commands", spawned)
+	}
+
+	// nil allows everything again.
+	SetAllowedCommands(nil)
+	if _, err := Execute("lspci"); err != nil {
+		t.Errorf("Execute(lspci) err = %v after clearing the allowlist", err)
+	}
+}
+
+func TestEmptyAllowlistDeniesAll(t *testing.T) {
+	SetAllowedCommands([]string{})
+	t.Cleanup(func() { SetAllowedCommands(nil) })
+
+	if _, err := Execute("true"); !errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("Execute(true) err = %v, want ErrCommandNotAllowed", err)
+	}
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOnExec(t *testing.T) {
+	type call struct {
+		name string
+		args []string
+		dur  time.Duration
+		err  error
+	}
+	var calls []call
+	OnExec = func(name string, args []string, dur time.Duration, err error) {
+		calls = append(calls, call{name, args, dur, err})
+	}
+	SetRedactedFlags("-P", "--password")
+	t.Cleanup(func() {
+		OnExec = nil
+		SetRedactedFlags()
+	})
+
+	if _, err := Execute("true"); err != nil {
+		t.Fatal(err)
+	}
+	_, failErr := Execute("sh", "-c", "exit 1", "--password=hunter2", "-P", "hunter2")
+
+	if len(calls) != 2 {
+		t.Fatalf("OnExec fired %d times, want 2", len(calls))
+	}
+	if calls[0].name != "true" || len(calls[0].args) != 0 || calls[0].dur <= 0 || calls[0].err != nil {
+		t.Errorf("success call = %+v", calls[0])
+	}
+	wantArgs := []string{"-c", "exit 1", "--password=***", "-P", "***"}
+	if calls[1].name != "sh" || !slices.Equal(calls[1].args, wantArgs) || calls[1].dur <= 0 {
+		t.Errorf("failure call = %+v, want args %q", calls[1], wantArgs)
+	}
+	if !errors.Is(calls[1].err, ErrExit) || calls[1].err != failErr {
+		t.Errorf("failure call err = %v, want the error returned by Execute", calls[1].err)
+	}
+
+	// Commands that are not attempted are not reported.
+	if _, err := Run(context.Background(), ""); err == nil {
+		t.Fatal("Run(\"\") succeeded")
+	}
+	if len(calls) != 2 {
+		t.Errorf("OnExec fired for an empty command")
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	SetRedactedFlags("-P", "--password")
+	t.Cleanup(func() { SetRedactedFlags() })
+
+	tests := []struct {
+		args, want []string
+	}{
+		{nil, nil},
+		{[]string{"-t", "17"}, []string{"-t", "17"}},
+		{[]string{"-U", "admin", "-P", "secret", "sel", "list"}, []string{"-U", "admin", "-P", "***", "sel", "list"}},
+		{[]string{"--password=secret", "-p=1"}, []string{"--password=***", "-p=1"}},
+		// A trailing flag without a value is left as is.
+		{[]string{"-P"}, []string{"-P"}},
+	}
+	for _, tt := range tests {
+		args := slices.Clone(tt.args)
+		if got := redactArgs(args); !slices.Equal(got, tt.want) {
+			t.Errorf("redactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+		if !slices.Equal(args, tt.args) {
+			t.Errorf("redactArgs(%q) modified its input", tt.args)
+		}
+	}
+}
@@ -5,20 +5,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/zenithax-cc/diting/pkg/utils"
 )
 
-const DefaultTimeout = 20 * time.Minute
+// DefaultTimeout is the timeout used by [Execute] and [ExecuteShell].
+// Applications may lower it at startup; it is not safe to change once collection has begun.
+var DefaultTimeout = 20 * time.Minute
 
 var (
 	ErrEmptyCommand = errors.New("empty command")
 	ErrTimeOut      = errors.New("command timed out")
 	ErrCanceled     = errors.New("command canceled")
 	ErrExit         = errors.New("command exited with error")
+
+	ErrCommandNotAllowed = errors.New("command not allowed")
 )
 
-// Execute execute the named program with the given arguments,default timeout 20 minutes
+// Execute execute the named program with the given arguments, using [DefaultTimeout]
 func Execute(name string, args ...string) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
 	defer cancel()
@@ -50,6 +61,25 @@ func ExecuteShellWithTimeout(timeout time.Duration, cmd string) ([]byte, error)
 // ExecutorWithContext is like [Exeute] but includes a context.
 // if the context is nil, it will be replaced with [context.WithTimeout] with a default timeout of 20 minutes.
 func ExecuteWithContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	res, err := Run(ctx, name, args...)
+	if res == nil {
+		return nil, err
+	}
+	return res.Output, err
+}
+
+// Result describes a finished command.
+type Result struct {
+	Output   []byte        // combined stdout and stderr, as returned by the Execute* functions
+	Stderr   []byte        // stderr only; nil when a custom [CommandRunner] is installed
+	ExitCode int           // exit code of the process, -1 if it did not start or was killed
+	Duration time.Duration // wall time spent running the command
+	Cmd      string        // command line, the name and arguments joined by spaces
+}
+
+// Run runs the named program like [ExecuteWithContext] and returns the details of the run.
+// The returned Result is non-nil whenever the command was attempted, including when it failed.
+func Run(ctx context.Context, name string, args ...string) (*Result, error) {
 	if name == "" {
 		return nil, ErrEmptyCommand
 	}
@@ -58,11 +88,194 @@ func ExecuteWithContext(ctx context.Context, name string, args ...string) ([]byt
 		return nil, fmt.Errorf("context cannot be nil")
 	}
 
+	if !commandAllowed(name) {
+		return nil, fmt.Errorf("%w: %s", ErrCommandNotAllowed, name)
+	}
+
+	res := &Result{Cmd: strings.Join(append([]string{name}, args...), " ")}
+
+	start := time.Now()
+	var err error
+	if customRunner {
+		res.Output, err = runner(ctx, name, args...)
+	} else {
+		res.Output, res.Stderr, err = runCommandStderr(ctx, name, args...)
+	}
+	res.Duration = time.Since(start)
+	res.ExitCode = exitCode(err)
+
+	if utils.IsPermissionError(err, res.Output) {
+		utils.NotePermissionDenied(res.Cmd)
+	}
+
+	if OnExec != nil {
+		OnExec(name, redactArgs(args), res.Duration, err)
+	}
+
+	return res, err
+}
+
+// OnExec, if set, is called after every command run by the Execute* functions, whether it
+// succeeded or not, e.g. to audit-log external invocations. Values of flags registered with
+// [SetRedactedFlags] are masked in args. It must be set before collection begins.
+var OnExec func(name string, args []string, dur time.Duration, err error)
+
+// redactedValue replaces the values of redacted flags passed to [OnExec].
+const redactedValue = "***"
+
+var redactedFlags map[string]struct{}
+
+// SetRedactedFlags registers flags, such as "-P" or "--password", whose values are masked
+// in the args passed to [OnExec], both as "--password secret" and "--password=secret".
+// It is not safe to call concurrently with Execute*.
+func SetRedactedFlags(flags ...string) {
+	redactedFlags = make(map[string]struct{}, len(flags))
+	for _, f := range flags {
+		redactedFlags[f] = struct{}{}
+	}
+}
+
+// redactArgs returns a copy of args with the values of redacted flags masked.
+func redactArgs(args []string) []string {
+	masked := slices.Clone(args)
+	if len(redactedFlags) == 0 {
+		return masked
+	}
+
+	for i := 0; i < len(masked); i++ {
+		if flag, _, ok := strings.Cut(masked[i], "="); ok {
+			if _, redacted := redactedFlags[flag]; redacted {
+				masked[i] = flag + "=" + redactedValue
+			}
+			continue
+		}
+
+		if _, redacted := redactedFlags[masked[i]]; redacted && i+1 < len(masked) {
+			i++
+			masked[i] = redactedValue
+		}
+	}
+
+	return masked
+}
+
+// exitCode extracts the process exit code from an error returned by a [CommandRunner].
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// allowedCommands is the set of commands the Execute* functions may run, nil allows all.
+var allowedCommands map[string]struct{}
+
+// SetAllowedCommands restricts the Execute* functions to the given commands; any other command
+// fails with [ErrCommandNotAllowed] without being spawned. An entry without a slash permits that
+// bare name (resolved via $PATH), an entry with a slash permits exactly that path.
+// The Execute*Shell functions run bash, which must be listed for them to work.
+// Passing nil allows all commands. It is not safe to call concurrently with Execute*.
+func SetAllowedCommands(commands []string) {
+	if commands == nil {
+		allowedCommands = nil
+		return
+	}
+
+	allowedCommands = make(map[string]struct{}, len(commands))
+	for _, c := range commands {
+		if c = strings.TrimSpace(c); c == "" {
+			continue
+		}
+		if strings.Contains(c, "/") {
+			c = filepath.Clean(c)
+		}
+		allowedCommands[c] = struct{}{}
+	}
+}
+
+func commandAllowed(name string) bool {
+	if allowedCommands == nil {
+		return true
+	}
+	if strings.Contains(name, "/") {
+		name = filepath.Clean(name)
+	}
+	_, ok := allowedCommands[name]
+	return ok
+}
+
+// sbinDirs are searched by [LookPath] after $PATH, which often lacks them for non-root users.
+var sbinDirs = []string{"/usr/local/sbin", "/usr/sbin", "/sbin"}
+
+// LookPath resolves the named program like [exec.LookPath], falling back to the sbin
+// directories where system tools such as dmidecode and ethtool are usually installed.
+func LookPath(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err == nil || strings.Contains(name, "/") {
+		return path, err
+	}
+
+	for _, dir := range sbinDirs {
+		if p, e := exec.LookPath(filepath.Join(dir, name)); e == nil {
+			return p, nil
+		}
+	}
+	return "", err
+}
+
+// CommandRunner runs the named program with the given arguments and returns its combined output.
+type CommandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+var (
+	runner       CommandRunner = runCommand
+	customRunner bool          // whether runner was replaced via SetRunner
+)
+
+// SetRunner replaces the function used by all Execute* functions to run commands,
+// e.g. to replay captured tool output instead of spawning processes.
+// Passing nil restores the default runner. It is not safe to call concurrently with Execute*.
+func SetRunner(r CommandRunner) {
+	customRunner = r != nil
+	if r == nil {
+		r = runCommand
+	}
+	runner = r
+}
+
+// Runner returns the [CommandRunner] currently used by the Execute* functions.
+func Runner() CommandRunner {
+	return runner
+}
+
+// runCommand is the default [CommandRunner], it resolves the program with [LookPath] and
+// spawns it via [exec.CommandContext].
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, _, err := runCommandStderr(ctx, name, args...)
+	return out, err
+}
+
+// runCommandStderr is like [runCommand] but additionally returns stderr on its own.
+func runCommandStderr(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	// Resolve the same way [LookPath] reports tools as available. If it fails, exec reports
+	// the not-found error itself.
+	if path, err := LookPath(name); err == nil {
+		name = path
+	}
 	cmd := exec.CommandContext(ctx, name, args...)
 
-	var buf bytes.Buffer
+	// stdout and stderr are copied by separate goroutines once they are different writers,
+	// so the combined buffer must be locked
+	var (
+		buf    lockedBuffer
+		stderr bytes.Buffer
+	)
 	cmd.Stdout = &buf
-	cmd.Stderr = &buf
+	cmd.Stderr = io.MultiWriter(&buf, &stderr)
 
 	err := cmd.Run()
 
@@ -70,13 +283,25 @@ func ExecuteWithContext(ctx context.Context, name string, args ...string) ([]byt
 	if err != nil {
 		switch {
 		case errors.Is(ctx.Err(), context.DeadlineExceeded):
-			exitErr = fmt.Errorf("%s: %w", ErrTimeOut, err)
+			exitErr = fmt.Errorf("%w: %w", ErrTimeOut, err)
 		case errors.Is(ctx.Err(), context.Canceled):
-			exitErr = fmt.Errorf("%s: %w", ErrCanceled, err)
+			exitErr = fmt.Errorf("%w: %w", ErrCanceled, err)
 		default:
-			exitErr = fmt.Errorf("%s: %w", ErrExit, err)
+			exitErr = fmt.Errorf("%w: %w", ErrExit, err)
 		}
 	}
 
-	return buf.Bytes(), exitErr
+	return buf.buf.Bytes(), stderr.Bytes(), exitErr
+}
+
+// lockedBuffer is a [bytes.Buffer] safe for concurrent writes.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
 }
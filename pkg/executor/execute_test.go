@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookPathFallsBackToSbin(t *testing.T) {
+	sbin := t.TempDir()
+	tool := filepath.Join(sbin, "dmidecode")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	saved := sbinDirs
+	sbinDirs = []string{t.TempDir(), sbin}
+	t.Cleanup(func() { sbinDirs = saved })
+	t.Setenv("PATH", t.TempDir())
+
+	if got, err := LookPath("dmidecode"); err != nil || got != tool {
+		t.Errorf("LookPath(dmidecode) = %q, %v, want %q", got, err, tool)
+	}
+	if _, err := LookPath("smartctl"); err == nil {
+		t.Error("LookPath(smartctl) found a tool that is not installed")
+	}
+	// Names containing a slash are not searched for in the sbin directories.
+	if _, err := LookPath("bin/dmidecode"); err == nil {
+		t.Error("LookPath(bin/dmidecode) searched the sbin directories")
+	}
+}
+
+func TestRunFindsSbinTools(t *testing.T) {
+	sbin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sbin, "dmidecode"), []byte("#!/bin/sh\necho \"dmi $1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	saved := sbinDirs
+	sbinDirs = []string{sbin}
+	t.Cleanup(func() { sbinDirs = saved })
+	t.Setenv("PATH", t.TempDir())
+
+	// A tool LookPath reports as available must also run, even when $PATH lacks its directory.
+	res, err := Run(context.Background(), "dmidecode", "-t")
+	if err != nil {
+		t.Fatalf("Run(dmidecode) = %v", err)
+	}
+	if string(res.Output) != "dmi -t\n" || res.Cmd != "dmidecode -t" {
+		t.Errorf("Output = %q, Cmd = %q", res.Output, res.Cmd)
+	}
+
+	if _, err := Run(context.Background(), "smartctl"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Run(smartctl) = %v, want exec.ErrNotFound", err)
+	}
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunSuccess(t *testing.T) {
+	res, err := Run(context.Background(), "sh", "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Cmd != "sh -c echo out; echo err >&2" {
+		t.Errorf("Cmd = %q", res.Cmd)
+	}
+	// stdout and stderr are copied concurrently, so their order in Output is not fixed
+	if out := string(res.Output); len(out) != 8 || !strings.Contains(out, "out\n") || !strings.Contains(out, "err\n") {
+		t.Errorf("Output = %q, want stdout and stderr combined", res.Output)
+	}
+	if string(res.Stderr) != "err\n" {
+		t.Errorf("Stderr = %q, want stderr only", res.Stderr)
+	}
+	if res.ExitCode != 0 {
+		t.Errorf("ExitCode = %d, want 0", res.ExitCode)
+	}
+	if res.Duration <= 0 {
+		t.Errorf("Duration = %s, want it measured", res.Duration)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	res, err := Run(context.Background(), "sh", "-c", "echo broken >&2; exit 3")
+	if err == nil {
+		t.Fatal("Run() of a failing command succeeded")
+	}
+	if res == nil {
+		t.Fatal("Run() returned no result for a command that ran")
+	}
+	if res.Cmd != "sh -c echo broken >&2; exit 3" || res.ExitCode != 3 || res.Duration <= 0 {
+		t.Errorf("result = %+v, want the command line, exit code 3 and a duration", res)
+	}
+	if string(res.Output) != "broken\n" || string(res.Stderr) != "broken\n" {
+		t.Errorf("Output = %q, Stderr = %q", res.Output, res.Stderr)
+	}
+
+	// The program could not be started.
+	res, err = Run(context.Background(), "diting-no-such-tool")
+	if err == nil || res == nil || res.ExitCode != -1 || res.Cmd != "diting-no-such-tool" {
+		t.Errorf("Run(missing) = %+v, %v, want exit code -1 and an error", res, err)
+	}
+
+	// Nothing was attempted.
+	if res, err := Run(context.Background(), ""); !errors.Is(err, ErrEmptyCommand) || res != nil {
+		t.Errorf("Run(\"\") = %+v, %v, want ErrEmptyCommand", res, err)
+	}
+}
+
+func TestRunCustomRunner(t *testing.T) {
+	errFake := errors.New("fake failure")
+	SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("captured"), errFake
+	})
+	t.Cleanup(func() { SetRunner(nil) })
+
+	res, err := Run(context.Background(), "dmidecode", "-t", "17")
+	if !errors.Is(err, errFake) {
+		t.Fatalf("err = %v, want the runner error", err)
+	}
+	if string(res.Output) != "captured" || res.Stderr != nil || res.Cmd != "dmidecode -t 17" || res.ExitCode != -1 {
+		t.Errorf("result = %+v", res)
+	}
+
+	// The Execute* wrappers return the same output.
+	if out, err := ExecuteWithContext(context.Background(), "dmidecode"); string(out) != "captured" || !errors.Is(err, errFake) {
+		t.Errorf("ExecuteWithContext() = %q, %v", out, err)
+	}
+}
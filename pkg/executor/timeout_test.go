@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteUsesDefaultTimeout(t *testing.T) {
+	saved := DefaultTimeout
+	DefaultTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { DefaultTimeout = saved })
+
+	start := time.Now()
+	_, err := Execute("sleep", "10")
+	if !errors.Is(err, ErrTimeOut) {
+		t.Fatalf("err = %v, want ErrTimeOut", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Execute() returned after %s, want it killed after %s", elapsed, DefaultTimeout)
+	}
+
+	if _, err := ExecuteShell("sleep 10"); !errors.Is(err, ErrTimeOut) {
+		t.Errorf("ExecuteShell() err = %v, want ErrTimeOut", err)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	if _, err := Execute("false"); !errors.Is(err, ErrExit) || errors.Is(err, ErrTimeOut) {
+		t.Errorf("Execute(false) err = %v, want ErrExit", err)
+	}
+	if _, err := ExecuteShell(""); !errors.Is(err, ErrEmptyCommand) {
+		t.Errorf("ExecuteShell(\"\") err = %v, want ErrEmptyCommand", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Package identity resolves the host identifier attached to published hardware data.
+// In containers os.Hostname returns the pod name, so deployments can pin the identity
+// to a static value, an environment variable or a file such as /etc/machine-id.
+package identity
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Source selects where the host identity is read from.
+type Source string
+
+const (
+	SourceHostname Source = "hostname" // os.Hostname, the default
+	SourceStatic   Source = "static"   // the configured value itself
+	SourceEnv      Source = "env"      // the environment variable named by the value
+	SourceFile     Source = "file"     // the first line of the file at the value path
+)
+
+// Resolver returns the host identity.
+type Resolver func() (string, error)
+
+// New returns a Resolver for source. value is the static identity, the environment variable
+// name or the file path, depending on source, and is ignored for [SourceHostname].
+func New(source Source, value string) (Resolver, error) {
+	switch source {
+	case "", SourceHostname:
+		return os.Hostname, nil
+	case SourceStatic:
+		if value == "" {
+			return nil, fmt.Errorf("static identity requires a value")
+		}
+		return func() (string, error) { return value, nil }, nil
+	case SourceEnv:
+		if value == "" {
+			return nil, fmt.Errorf("env identity requires a variable name")
+		}
+		return func() (string, error) {
+			id := strings.TrimSpace(os.Getenv(value))
+			if id == "" {
+				return "", fmt.Errorf("environment variable %s is empty", value)
+			}
+			return id, nil
+		}, nil
+	case SourceFile:
+		if value == "" {
+			return nil, fmt.Errorf("file identity requires a path")
+		}
+		return func() (string, error) { return readFirstLine(value) }, nil
+	default:
+		return nil, fmt.Errorf("unsupported identity source: %s", source)
+	}
+}
+
+func readFirstLine(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read identity file failed: %w", err)
+	}
+
+	line, _, _ := strings.Cut(string(data), "\n")
+	if line = strings.TrimSpace(line); line == "" {
+		return "", fmt.Errorf("identity file %s is empty", path)
+	}
+	return line, nil
+}
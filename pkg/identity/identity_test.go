@@ -0,0 +1,93 @@
+package identity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewHostname(t *testing.T) {
+	want, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, source := range []Source{"", SourceHostname} {
+		r, err := New(source, "ignored")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := r(); err != nil || got != want {
+			t.Errorf("New(%q)() = %q, %v, want %q", source, got, err, want)
+		}
+	}
+}
+
+func TestNewStatic(t *testing.T) {
+	r, err := New(SourceStatic, "rack1-node7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r(); err != nil || got != "rack1-node7" {
+		t.Errorf("resolved %q, %v, want rack1-node7", got, err)
+	}
+}
+
+func TestNewEnv(t *testing.T) {
+	t.Setenv("DITING_NODE_NAME", " node-7\n")
+	r, err := New(SourceEnv, "DITING_NODE_NAME")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r(); err != nil || got != "node-7" {
+		t.Errorf("resolved %q, %v, want node-7", got, err)
+	}
+
+	// The variable is read on every resolution.
+	t.Setenv("DITING_NODE_NAME", "")
+	if _, err := r(); err == nil {
+		t.Error("resolving an empty variable succeeded")
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "machine-id")
+	if err := os.WriteFile(path, []byte("  4c4c4544004d3510  \nsecond line\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(SourceFile, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r(); err != nil || got != "4c4c4544004d3510" {
+		t.Errorf("resolved %q, %v, want the first line", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r(); err == nil {
+		t.Error("resolving an empty file succeeded")
+	}
+
+	r, _ = New(SourceFile, filepath.Join(t.TempDir(), "missing"))
+	if _, err := r(); err == nil {
+		t.Error("resolving a missing file succeeded")
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		source Source
+		value  string
+	}{
+		{SourceStatic, ""},
+		{SourceEnv, ""},
+		{SourceFile, ""},
+		{"dns", "node-7"},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.source, tt.value); err == nil {
+			t.Errorf("New(%q, %q) succeeded", tt.source, tt.value)
+		}
+	}
+}
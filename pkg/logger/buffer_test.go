@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock 手动设置时间的时钟
+type manualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *manualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// readLog 读取 dir 下 prefix-date.log 的内容
+func readLog(t *testing.T, dir, date string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "app-"+date+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func newBufferedHandler(t *testing.T, clock *manualClock) (*DailyFileHandler, string) {
+	t.Helper()
+	dir := t.TempDir()
+	h, err := NewFileHandler(&LogConfig{
+		Dir:            dir,
+		FilenamePrefix: "app",
+		Level:          slog.LevelInfo,
+		BufferSize:     4096,
+		FlushInterval:  time.Hour,
+		Clock:          clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	return h, dir
+}
+
+func TestBufferedFileFlushedOnClose(t *testing.T) {
+	clock := &manualClock{t: time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)}
+	h, dir := newBufferedHandler(t, clock)
+	log := slog.New(h)
+
+	log.Info("first")
+	log.With("module", "disk").Info("second")
+	if got := readLog(t, dir, "2026-10-14"); got != "" {
+		t.Fatalf("log written before a flush: %q", got)
+	}
+
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := readLog(t, dir, "2026-10-14")
+	if !strings.Contains(got, "msg=first") || !strings.Contains(got, "msg=second module=disk") {
+		t.Errorf("log after Close() = %q", got)
+	}
+}
+
+func TestBufferedFileFlushedOnRotation(t *testing.T) {
+	clock := &manualClock{t: time.Date(2026, 10, 14, 23, 59, 0, 0, time.Local)}
+	h, dir := newBufferedHandler(t, clock)
+	log := slog.New(h)
+
+	log.Info("before midnight")
+	clock.Set(time.Date(2026, 10, 15, 0, 1, 0, 0, time.Local))
+	log.Info("after midnight")
+
+	if got := readLog(t, dir, "2026-10-14"); !strings.Contains(got, "before midnight") {
+		t.Errorf("old log after rotation = %q, want the buffered record flushed", got)
+	}
+	if got := readLog(t, dir, "2026-10-15"); got != "" {
+		t.Errorf("new log = %q, want it still buffered", got)
+	}
+}
+
+func TestBufferedFileFlushesErrors(t *testing.T) {
+	clock := &manualClock{t: time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)}
+	h, dir := newBufferedHandler(t, clock)
+
+	if err := h.Handle(context.Background(), slog.NewRecord(clock.Now(), slog.LevelError, "disk failed", 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := readLog(t, dir, "2026-10-14"); !strings.Contains(got, "disk failed") {
+		t.Errorf("log = %q, want error records written immediately", got)
+	}
+}
+
+func TestBufferedFileFlushInterval(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewFileHandler(&LogConfig{Dir: dir, FilenamePrefix: "app", BufferSize: 4096, FlushInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	slog.New(h).Info("periodic")
+	date := time.Now().Format("2006-01-02")
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(readLog(t, dir, date), "periodic") {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered record not flushed by the flush loop")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
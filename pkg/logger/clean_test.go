@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stuckReadDir 在测试期间让日志目录的读取阻塞，直到测试结束，返回每次开始读取时触发的 channel
+func stuckReadDir(t *testing.T) <-chan struct{} {
+	t.Helper()
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	saved := readDir
+	readDir = func(name string) ([]os.DirEntry, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		close(release)
+		readDir = saved
+	})
+	return started
+}
+
+func TestCloseDoesNotWaitForStuckCleanup(t *testing.T) {
+	started := stuckReadDir(t)
+
+	h, err := NewFileHandler(&LogConfig{Dir: t.TempDir(), FilenamePrefix: "app", RetainDays: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	done := make(chan error)
+	go func() { done <- h.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() blocked on a stuck log cleanup")
+	}
+}
+
+func TestRunCleanTimeout(t *testing.T) {
+	started := stuckReadDir(t)
+	h := &DailyFileHandler{
+		cfg:       &LogConfig{Dir: t.TempDir(), FilenamePrefix: "app", RetainDays: 7},
+		cleanDone: make(chan struct{}),
+		cleanCtx:  context.Background(),
+		cleanWait: 10 * time.Millisecond,
+	}
+
+	// 超时后放弃等待
+	returned := make(chan struct{})
+	go func() {
+		h.runClean()
+		close(returned)
+	}()
+	<-started
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("runClean() did not give up after the timeout")
+	}
+
+	// 上一次清理仍卡住时跳过，不再启动新的清理
+	h.runClean()
+	select {
+	case <-started:
+		t.Error("a second cleanup started while the first one is stuck")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestCleanOldLogs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app-2026-09-01.log", "app-2026-10-10.log", "other-2026-09-01.log", "app-notadate.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := &DailyFileHandler{cfg: &LogConfig{
+		Dir: dir, FilenamePrefix: "app", RetainDays: 7,
+		Clock: fixedClock(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)),
+	}}
+	h.cleanOldLogs()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{"app-2026-10-10.log", "app-notadate.log", "other-2026-09-01.log"}
+	if len(names) != len(want) {
+		t.Fatalf("remaining files = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("remaining files = %v, want %v", names, want)
+			break
+		}
+	}
+}
+
+// fixedClock 总是返回同一时间的时钟
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestFileHandlerFollowsClock(t *testing.T) {
+	dir := t.TempDir()
+	clock := &manualClock{t: time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)}
+	h, err := NewFileHandler(&LogConfig{Dir: dir, FilenamePrefix: "app", RetainDays: 3, Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	log := slog.New(h)
+
+	// 每推进一天切换到新文件
+	for day := 1; day <= 5; day++ {
+		clock.Set(time.Date(2026, 10, day, 12, 0, 0, 0, time.Local))
+		log.Info("tick", "day", day)
+	}
+	for day := 1; day <= 5; day++ {
+		date := fmt.Sprintf("2026-10-%02d", day)
+		if got := readLog(t, dir, date); !strings.Contains(got, fmt.Sprintf("day=%d", day)) {
+			t.Errorf("log of %s = %q, want the record of day %d", date, got, day)
+		}
+	}
+
+	// 按时钟判断过期:10-05 保留 3 天时 10-01、10-02 已过期
+	h.cleanOldLogs()
+	if got, want := logFiles(t, dir), []string{"app-2026-10-03.log", "app-2026-10-04.log", "app-2026-10-05.log"}; !slices.Equal(got, want) {
+		t.Errorf("remaining files = %v, want %v", got, want)
+	}
+
+	// 时钟前进后更多文件过期
+	clock.Set(time.Date(2026, 10, 7, 12, 0, 0, 0, time.Local))
+	h.cleanOldLogs()
+	if got, want := logFiles(t, dir), []string{"app-2026-10-05.log"}; !slices.Equal(got, want) {
+		t.Errorf("remaining files = %v, want %v", got, want)
+	}
+}
+
+// logFiles 返回 dir 下的文件名
+func logFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// returnsWithin 在 fn 于 d 内返回时返回 true
+func returnsWithin(d time.Duration, fn func()) bool {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func TestCleanLoopStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &DailyFileHandler{
+		cfg:         &LogConfig{Dir: t.TempDir(), FilenamePrefix: "app"},
+		cleanTicker: time.NewTicker(time.Hour),
+		cleanDone:   make(chan struct{}),
+		cleanCtx:    ctx,
+		cleanWait:   time.Hour,
+	}
+
+	go cancel()
+	if !returnsWithin(time.Second, h.cleanOldLogsLoop) {
+		t.Fatal("cleanup goroutine still running after the context was canceled")
+	}
+}
+
+func TestRunCleanStopsWaitingOnContextCancel(t *testing.T) {
+	started := stuckReadDir(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &DailyFileHandler{
+		cfg:       &LogConfig{Dir: t.TempDir(), FilenamePrefix: "app", RetainDays: 7},
+		cleanDone: make(chan struct{}),
+		cleanCtx:  ctx,
+		cleanWait: time.Hour,
+	}
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	if !returnsWithin(time.Second, h.runClean) {
+		t.Fatal("runClean() kept waiting for a stuck cleanup after the context was canceled")
+	}
+}
+
+func TestFileHandlerContextCancel(t *testing.T) {
+	started := stuckReadDir(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	h, err := NewFileHandlerContext(ctx, &LogConfig{Dir: t.TempDir(), FilenamePrefix: "app", RetainDays: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	cancel()
+
+	// 取消后 handler 仍可写入和关闭
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "after cancel", 0)); err != nil {
+		t.Errorf("Handle() after cancel = %v", err)
+	}
+	if !returnsWithin(time.Second, func() { _ = h.Close() }) {
+		t.Fatal("Close() blocked after the context was canceled")
+	}
+}
+
+func TestSweepLoopStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &rateLimitState{window: time.Hour, next: &recordingHandler{}}
+
+	go cancel()
+	if !returnsWithin(time.Second, func() { s.sweepLoop(ctx) }) {
+		t.Fatal("rate limit sweep goroutine still running after the context was canceled")
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -10,7 +11,10 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/zenithax-cc/diting/pkg/utils"
 )
 
 type LogFormat string
@@ -18,6 +22,8 @@ type LogFormat string
 const (
 	LogFormatText LogFormat = "text"
 	LogFormatJSON LogFormat = "json"
+	// LogFormatLogfmt key=value 格式，含空格等特殊字符的值加引号
+	LogFormatLogfmt LogFormat = "logfmt"
 )
 
 // LogOutput 定义日志输出目标
@@ -40,9 +46,24 @@ type LogConfig struct {
 	RetainDays     int    // 保留天数
 
 	// 通用配置
-	Format    LogFormat  // 日志格式：text, json
+	Format    LogFormat  // 日志格式：text, json, logfmt
 	Level     slog.Level // 日志级别
 	AddSource bool       // 是否添加源码位置
+	NoColor   bool       // 是否禁用终端彩色输出，设置 NO_COLOR 环境变量效果相同
+
+	// 限流配置
+	RateLimitWindow time.Duration // 相同级别和消息的日志在该窗口内只输出一次，0表示不限流
+
+	// 采样配置，只作用于 Warn 以下级别的日志，两者都为0时不采样
+	SampleRate  int // 每秒超过 SampleBurst 条后每 SampleRate 条输出一条
+	SampleBurst int // 每秒前 SampleBurst 条全部输出
+
+	// 文件写缓冲配置
+	BufferSize    int           // 日志文件写缓冲大小(字节)，缓冲满时写入文件，0表示不缓冲、每条日志直接写入
+	FlushInterval time.Duration // 缓冲的定时刷新间隔，默认1秒；Error 及以上级别的日志立即刷新
+
+	// Clock 日志文件切分和过期清理使用的时间来源，nil 时使用系统时间
+	Clock utils.Clock
 }
 
 var (
@@ -54,6 +75,12 @@ var (
 
 // InitLogger 初始化日志系统
 func InitLogger(cfg *LogConfig) (*slog.Logger, error) {
+	return InitLoggerContext(context.Background(), cfg)
+}
+
+// InitLoggerContext 初始化日志系统，ctx 取消时停止后台的日志清理和限流汇总任务，
+// 日志文件仍可继续写入，直到调用 Close
+func InitLoggerContext(ctx context.Context, cfg *LogConfig) (*slog.Logger, error) {
 	loggerOnce.Do(func() {
 		if err := cfg.validate(); err != nil {
 			initErr = fmt.Errorf("invalid config: %w", err)
@@ -64,7 +91,7 @@ func InitLogger(cfg *LogConfig) (*slog.Logger, error) {
 
 		// 创建文件 handler
 		if cfg.Output&OutputFile != 0 {
-			fileHandler, err := NewFileHandler(cfg)
+			fileHandler, err := NewFileHandlerContext(ctx, cfg)
 			if err != nil {
 				initErr = fmt.Errorf("create file handler failed: %w", err)
 				return
@@ -92,6 +119,14 @@ func InitLogger(cfg *LogConfig) (*slog.Logger, error) {
 			finalHandler = NewMultiHandler(handlers...)
 		}
 
+		if cfg.RateLimitWindow > 0 {
+			finalHandler = NewRateLimitHandlerContext(ctx, finalHandler, cfg.RateLimitWindow)
+		}
+
+		if cfg.SampleRate > 1 || cfg.SampleBurst > 0 {
+			finalHandler = NewSamplingHandler(finalHandler, slog.LevelWarn, cfg.SampleRate, cfg.SampleBurst)
+		}
+
 		onceLogger = slog.New(finalHandler)
 		slog.SetDefault(onceLogger)
 	})
@@ -147,13 +182,18 @@ func (cfg *LogConfig) validate() error {
 		if cfg.RetainDays <= 0 {
 			cfg.RetainDays = 30
 		}
+		if cfg.BufferSize > 0 && cfg.FlushInterval <= 0 {
+			cfg.FlushInterval = time.Second
+		}
 	}
 
 	if cfg.Format == "" {
 		cfg.Format = LogFormatText
 	}
 
-	if cfg.Format != LogFormatText && cfg.Format != LogFormatJSON {
+	switch cfg.Format {
+	case LogFormatText, LogFormatJSON, LogFormatLogfmt:
+	default:
 		return fmt.Errorf("unsupported log format: %s", cfg.Format)
 	}
 
@@ -218,13 +258,22 @@ type DailyFileHandler struct {
 	cfg         *LogConfig
 	curDate     string
 	curFile     *os.File
+	curBuf      *bufio.Writer // 当前文件的写缓冲，未启用缓冲时为 nil
 	curInner    slog.Handler
 	cleanTicker *time.Ticker
 	cleanDone   chan struct{}
+	cleanCtx    context.Context // 取消时停止清理任务
+	cleaning    atomic.Bool     // 是否有清理正在执行
+	cleanWait   time.Duration   // 单次清理的最长等待时间
 	closeOnce   sync.Once
 }
 
 func NewFileHandler(cfg *LogConfig) (*DailyFileHandler, error) {
+	return NewFileHandlerContext(context.Background(), cfg)
+}
+
+// NewFileHandlerContext 创建按天切分的文件 handler，ctx 取消时停止后台清理任务
+func NewFileHandlerContext(ctx context.Context, cfg *LogConfig) (*DailyFileHandler, error) {
 	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
 		return nil, fmt.Errorf("create log directory failed: %w", err)
 	}
@@ -232,9 +281,11 @@ func NewFileHandler(cfg *LogConfig) (*DailyFileHandler, error) {
 	handler := &DailyFileHandler{
 		cfg:       cfg,
 		cleanDone: make(chan struct{}),
+		cleanCtx:  ctx,
+		cleanWait: defaultCleanTimeout,
 	}
 
-	if err := handler.rotateIfNeeded(time.Now()); err != nil {
+	if err := handler.rotateIfNeeded(handler.now()); err != nil {
 		return nil, err
 	}
 
@@ -242,6 +293,11 @@ func NewFileHandler(cfg *LogConfig) (*DailyFileHandler, error) {
 	handler.cleanTicker = time.NewTicker(24 * time.Hour)
 	go handler.cleanOldLogsLoop()
 
+	// FlushInterval 的默认值由 validate 设置
+	if cfg.BufferSize > 0 && cfg.FlushInterval > 0 {
+		go handler.flushLoop(cfg.FlushInterval)
+	}
+
 	return handler, nil
 }
 
@@ -253,7 +309,7 @@ func (h *DailyFileHandler) Handle(ctx context.Context, r slog.Record) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if err := h.rotateIfNeeded(r.Time); err != nil {
+	if err := h.rotateIfNeeded(h.now()); err != nil {
 		return err
 	}
 
@@ -261,7 +317,84 @@ func (h *DailyFileHandler) Handle(ctx context.Context, r slog.Record) error {
 		return fmt.Errorf("file handler not initialized")
 	}
 
-	return h.curInner.Handle(ctx, r)
+	if err := h.curInner.Handle(ctx, r); err != nil {
+		return err
+	}
+	return h.flushIfError(r.Level)
+}
+
+// now 返回配置的时钟的当前时间，日志按该时间而不是记录自身的时间切分
+func (h *DailyFileHandler) now() time.Time {
+	return utils.ClockOrReal(h.cfg.Clock).Now()
+}
+
+// flushIfError 在 Error 及以上级别时立即刷新缓冲，避免进程随后崩溃时丢失关键日志，调用方需持有 h.mu
+func (h *DailyFileHandler) flushIfError(level slog.Level) error {
+	if h.curBuf == nil || level < slog.LevelError {
+		return nil
+	}
+	return h.curBuf.Flush()
+}
+
+// flushLoop 定时刷新写缓冲，直到 handler 关闭
+func (h *DailyFileHandler) flushLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var err error
+			h.mu.Lock()
+			if h.curBuf != nil {
+				err = h.curBuf.Flush()
+			}
+			h.mu.Unlock()
+			if err != nil {
+				reportError("failed to flush log file", err)
+			}
+		case <-h.cleanDone:
+			return
+		}
+	}
+}
+
+// reportError 将文件 handler 自身的写入错误输出到标准错误。这些错误不能经 slog 记录：
+// 默认 logger 通常就是该 handler，调用方持有 h.mu 时会死锁，且写入的正是出错的文件
+func reportError(msg string, err error) {
+	fmt.Fprintf(os.Stderr, "logger: %s: %v\n", msg, err)
+}
+
+// closeFile 刷新缓冲并关闭当前文件，调用方需持有 h.mu
+func (h *DailyFileHandler) closeFile() error {
+	var err error
+	if h.curBuf != nil {
+		err = h.curBuf.Flush()
+	}
+	if closeErr := h.curFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	h.curFile = nil
+	h.curBuf = nil
+	h.curInner = nil
+	return err
+}
+
+// fileWriter 将写入转发到 handler 当前的文件(或其缓冲)，
+// 使 WithAttrs/WithGroup 派生的 handler 在切换到新文件后仍写入新文件。调用方需持有 h.mu
+type fileWriter struct {
+	h *DailyFileHandler
+}
+
+func (w fileWriter) Write(p []byte) (int, error) {
+	if w.h.curBuf != nil {
+		return w.h.curBuf.Write(p)
+	}
+	if w.h.curFile == nil {
+		return 0, fmt.Errorf("log file closed")
+	}
+	return w.h.curFile.Write(p)
 }
 
 func (h *DailyFileHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
@@ -306,9 +439,7 @@ func (h *DailyFileHandler) Close() error {
 		defer h.mu.Unlock()
 
 		if h.curFile != nil {
-			err = h.curFile.Close()
-			h.curFile = nil
-			h.curInner = nil
+			err = h.closeFile()
 		}
 	})
 	return err
@@ -320,13 +451,11 @@ func (h *DailyFileHandler) rotateIfNeeded(t time.Time) error {
 		return nil
 	}
 
-	// 关闭旧文件
+	// 刷新缓冲并关闭旧文件
 	if h.curFile != nil {
-		if err := h.curFile.Close(); err != nil {
-			slog.Warn("failed to close old log file", "error", err)
+		if err := h.closeFile(); err != nil {
+			reportError("failed to close old log file", err)
 		}
-		h.curFile = nil
-		h.curInner = nil
 	}
 
 	// 创建新文件
@@ -343,47 +472,91 @@ func (h *DailyFileHandler) rotateIfNeeded(t time.Time) error {
 		AddSource: h.cfg.AddSource,
 	}
 
+	w := fileWriter{h: h}
 	var inner slog.Handler
 	switch h.cfg.Format {
 	case LogFormatJSON:
-		inner = slog.NewJSONHandler(file, opts)
+		inner = slog.NewJSONHandler(w, opts)
+	case LogFormatLogfmt:
+		inner = NewLogfmtHandler(w, opts)
 	default:
-		inner = slog.NewTextHandler(file, opts)
+		inner = slog.NewTextHandler(w, opts)
 	}
 
 	h.curDate = date
 	h.curFile = file
 	h.curInner = inner
+	if h.cfg.BufferSize > 0 {
+		h.curBuf = bufio.NewWriterSize(file, h.cfg.BufferSize)
+	}
 
 	return nil
 }
 
+// defaultCleanTimeout 单次清理的最长等待时间，超时后放弃等待，避免文件系统卡住时阻塞清理循环
+const defaultCleanTimeout = time.Minute
+
+// readDir 读取日志目录
+var readDir = os.ReadDir
+
 func (h *DailyFileHandler) cleanOldLogsLoop() {
+	defer h.cleanTicker.Stop()
+
 	// 立即执行一次清理
-	h.cleanOldLogs()
+	h.runClean()
 
 	for {
 		select {
 		case <-h.cleanTicker.C:
-			h.cleanOldLogs()
+			h.runClean()
 		case <-h.cleanDone:
 			return
+		case <-h.cleanCtx.Done():
+			return
 		}
 	}
 }
 
+// runClean 在独立的 goroutine 中执行清理，并等待其完成、超时、handler 关闭或 ctx 取消。
+// os.ReadDir/os.Remove 阻塞在系统调用中时无法取消，只能放弃等待；
+// 上一次清理仍未结束时跳过本次，避免卡住的清理 goroutine 不断累积。
+func (h *DailyFileHandler) runClean() {
+	if !h.cleaning.CompareAndSwap(false, true) {
+		slog.Warn("previous log cleanup still running, skipped", "dir", h.cfg.Dir)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer h.cleaning.Store(false)
+		defer close(done)
+		h.cleanOldLogs()
+	}()
+
+	timer := time.NewTimer(h.cleanWait)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		slog.Warn("log cleanup timed out", "dir", h.cfg.Dir, "timeout", h.cleanWait)
+	case <-h.cleanDone:
+	case <-h.cleanCtx.Done():
+	}
+}
+
 func (h *DailyFileHandler) cleanOldLogs() {
 	if h.cfg.RetainDays <= 0 {
 		return
 	}
 
-	entries, err := os.ReadDir(h.cfg.Dir)
+	entries, err := readDir(h.cfg.Dir)
 	if err != nil {
 		slog.Warn("failed to read log directory", "error", err, "dir", h.cfg.Dir)
 		return
 	}
 
-	now := time.Now()
+	now := h.now()
 	prefix := h.cfg.FilenamePrefix + "-"
 	suffix := ".log"
 	cutoff := now.AddDate(0, 0, -h.cfg.RetainDays)
@@ -431,11 +604,14 @@ func (w *fileHandlerWrapper) Handle(ctx context.Context, r slog.Record) error {
 	w.original.mu.Lock()
 	defer w.original.mu.Unlock()
 
-	if err := w.original.rotateIfNeeded(r.Time); err != nil {
+	if err := w.original.rotateIfNeeded(w.original.now()); err != nil {
 		return err
 	}
 
-	return w.inner.Handle(ctx, r)
+	if err := w.inner.Handle(ctx, r); err != nil {
+		return err
+	}
+	return w.original.flushIfError(r.Level)
 }
 
 func (w *fileHandlerWrapper) WithAttrs(attrs []slog.Attr) slog.Handler {
@@ -469,7 +645,7 @@ func NewTerminalHandler(out *os.File, cfg *LogConfig) slog.Handler {
 	return &TerminalHandler{
 		out:      out,
 		opts:     opts,
-		colorize: true,
+		colorize: !cfg.NoColor && !noColorEnv(),
 		bufPool: &sync.Pool{
 			New: func() interface{} {
 				return new(bytes.Buffer)
@@ -478,6 +654,11 @@ func NewTerminalHandler(out *os.File, cfg *LogConfig) slog.Handler {
 	}
 }
 
+// noColorEnv 判断是否设置了 NO_COLOR 环境变量，参见 https://no-color.org
+func noColorEnv() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
 const (
 	colorReset  = "\033[0m"
 	colorRed    = "\033[31m"
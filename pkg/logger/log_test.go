@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// terminalOutput 通过终端 handler 输出一条日志，返回写入的内容
+func terminalOutput(t *testing.T, cfg *LogConfig) string {
+	t.Helper()
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	h := NewTerminalHandler(f, cfg)
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "disk degraded", 0)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestTerminalHandlerColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		noColor bool
+		env     string
+		want    bool
+	}{
+		{name: "default", want: true},
+		{name: "config", noColor: true, want: false},
+		{name: "env", env: "1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.env)
+
+			out := terminalOutput(t, &LogConfig{NoColor: tt.noColor})
+			if !strings.Contains(out, "disk degraded") {
+				t.Fatalf("output %q does not contain the message", out)
+			}
+			if got := strings.Contains(out, "\033["); got != tt.want {
+				t.Errorf("colored = %v, want %v (output %q)", got, tt.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// LogfmtHandler 以 logfmt 格式输出日志，每条记录一行：
+//
+//	time=2024-01-02T15:04:05.000+08:00 level=INFO msg="collect done" module=disk
+//
+// 值中包含空格、引号、等号或控制字符时加双引号并转义，分组属性的键以 "." 连接
+type LogfmtHandler struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	opts   slog.HandlerOptions
+	prefix string // 当前分组前缀，如 "req."
+	attrs  []byte // WithAttrs 预先格式化的属性
+}
+
+// NewLogfmtHandler 创建 logfmt handler，opts 为 nil 时使用默认选项
+func NewLogfmtHandler(out io.Writer, opts *slog.HandlerOptions) *LogfmtHandler {
+	h := &LogfmtHandler{mu: &sync.Mutex{}, out: out}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *LogfmtHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *LogfmtHandler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+
+	if !r.Time.IsZero() {
+		appendPair(&buf, slog.TimeKey, r.Time.Format("2006-01-02T15:04:05.000Z07:00"))
+	}
+	appendPair(&buf, slog.LevelKey, r.Level.String())
+	if h.opts.AddSource && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		appendPair(&buf, slog.SourceKey, fmt.Sprintf("%s:%d", f.File, f.Line))
+	}
+	appendPair(&buf, slog.MessageKey, r.Message)
+
+	buf.Write(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&buf, h.prefix, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// 去掉第一个键值对前的空格
+	_, err := h.out.Write(buf.Bytes()[1:])
+	return err
+}
+
+func (h *LogfmtHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	buf := bytes.NewBuffer(append([]byte(nil), h.attrs...))
+	for _, a := range attrs {
+		h.appendAttr(buf, h.prefix, a)
+	}
+	h2.attrs = buf.Bytes()
+	return &h2
+}
+
+func (h *LogfmtHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// appendAttr 写入一个属性，分组展开为带前缀的多个键值对，空属性和空分组被忽略
+func (h *LogfmtHandler) appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(buf, prefix, ga)
+		}
+		return
+	}
+
+	appendPair(buf, prefix+a.Key, formatLogfmtValue(a.Value))
+}
+
+func formatLogfmtValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return fmt.Sprint(v.Any())
+	default:
+		return v.String()
+	}
+}
+
+// appendPair 写入 " key=value"，必要时对值加引号，键中的非法字符替换为下划线
+func appendPair(buf *bytes.Buffer, key, value string) {
+	buf.WriteByte(' ')
+	buf.WriteString(strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key))
+	buf.WriteByte('=')
+
+	if needsQuote(value) {
+		buf.WriteString(strconv.Quote(value))
+	} else {
+		buf.WriteString(value)
+	}
+}
+
+// needsQuote 判断 logfmt 值是否需要加引号：空串，或包含空格、等号、引号、反斜杠和不可打印字符
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
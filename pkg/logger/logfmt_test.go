@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogfmtQuoting(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewLogfmtHandler(&buf, nil))
+
+	log.Info("collect done",
+		"module", "disk",
+		"path", `C:\logs`,
+		"error", errors.New(`open "/dev/sdb": no such device`),
+		"query", "a=b",
+		"empty", "",
+		"lines", "one\ntwo",
+		"count", 3,
+		"ok", true,
+		"bad key", "x",
+	)
+
+	line := buf.String()
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Fatalf("output is not a single line: %q", line)
+	}
+	want := []string{
+		`level=INFO`,
+		`msg="collect done"`,
+		`module=disk`,
+		`path="C:\\logs"`,
+		`error="open \"/dev/sdb\": no such device"`,
+		`query="a=b"`,
+		`empty=""`,
+		`lines="one\ntwo"`,
+		`count=3`,
+		`ok=true`,
+		`bad_key=x`,
+	}
+	for _, w := range want {
+		if !strings.Contains(line, " "+w) {
+			t.Errorf("output missing %s:\n%s", w, line)
+		}
+	}
+	if !strings.HasPrefix(line, "time=") {
+		t.Errorf("output does not start with the time: %q", line)
+	}
+}
+
+func TestLogfmtGroupsAndAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewLogfmtHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	log.Info("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("record below the level written: %q", buf.String())
+	}
+
+	log.With("host", "node-1").WithGroup("req").Warn("slow",
+		"id", 7,
+		slog.Group("timing", "total", time.Second),
+		slog.Group("empty"),
+	)
+	line := buf.String()
+	for _, w := range []string{" host=node-1", " req.id=7", " req.timing.total=1s"} {
+		if !strings.Contains(line, w) {
+			t.Errorf("output missing %q:\n%s", w, line)
+		}
+	}
+	if strings.Contains(line, "empty") {
+		t.Errorf("empty group written:\n%s", line)
+	}
+}
+
+func TestValidateLogfmt(t *testing.T) {
+	cfg := &LogConfig{Output: OutputTerminal, Format: LogFormatLogfmt}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() with logfmt = %v", err)
+	}
+	cfg.Format = "xml"
+	if err := cfg.validate(); err == nil {
+		t.Error("validate() accepted an unsupported format")
+	}
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxRateLimitKeys 限流状态表的最大条目数，超过后清理已过期的条目
+const maxRateLimitKeys = 1024
+
+// RateLimitHandler 对相同级别和消息的日志限流：每个窗口内只输出第一条，
+// 其余被抑制并计数，窗口过后再次出现时输出并附带 suppressed 属性说明被抑制的条数。
+// 后台每个窗口检查一次，窗口已过期且有被抑制日志的条目输出一条 "N messages suppressed" 汇总，
+// 过期的条目从状态表中清除
+type RateLimitHandler struct {
+	next  slog.Handler
+	state *rateLimitState
+}
+
+type rateLimitState struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[rateLimitKey]*rateLimitEntry
+	next    slog.Handler // 输出汇总日志的 handler，不带 WithAttrs/WithGroup 添加的属性
+}
+
+type rateLimitKey struct {
+	level slog.Level
+	msg   string
+}
+
+type rateLimitEntry struct {
+	windowStart time.Time
+	suppressed  int
+}
+
+// NewRateLimitHandler 创建限流 handler，window 为相同日志的去重窗口
+func NewRateLimitHandler(next slog.Handler, window time.Duration) slog.Handler {
+	return NewRateLimitHandlerContext(context.Background(), next, window)
+}
+
+// NewRateLimitHandlerContext 创建限流 handler，ctx 取消时停止后台的汇总和清理任务
+func NewRateLimitHandlerContext(ctx context.Context, next slog.Handler, window time.Duration) slog.Handler {
+	state := &rateLimitState{
+		window:  window,
+		entries: make(map[rateLimitKey]*rateLimitEntry),
+		next:    next,
+	}
+	if window > 0 {
+		go state.sweepLoop(ctx)
+	}
+	return &RateLimitHandler{next: next, state: state}
+}
+
+func (h *RateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RateLimitHandler) Handle(ctx context.Context, r slog.Record) error {
+	suppressed, ok := h.state.allow(rateLimitKey{level: r.Level, msg: r.Message}, r.Time)
+	if !ok {
+		return nil
+	}
+
+	if suppressed > 0 {
+		r = r.Clone()
+		r.AddAttrs(slog.Int("suppressed", suppressed))
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *RateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &RateLimitHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+func (h *RateLimitHandler) WithGroup(name string) slog.Handler {
+	return &RateLimitHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// allow 判断日志是否输出，输出时返回上一个窗口内被抑制的条数
+func (s *rateLimitState) allow(key rateLimitKey, now time.Time) (int, bool) {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if ok && now.Sub(entry.windowStart) < s.window {
+		entry.suppressed++
+		return 0, false
+	}
+
+	var suppressed int
+	if ok {
+		suppressed = entry.suppressed
+		entry.windowStart = now
+		entry.suppressed = 0
+		return suppressed, true
+	}
+
+	if len(s.entries) >= maxRateLimitKeys {
+		s.pruneLocked(now)
+	}
+	s.entries[key] = &rateLimitEntry{windowStart: now}
+
+	return 0, true
+}
+
+// sweepLoop 每个窗口执行一次 sweep，直到 ctx 取消
+func (s *rateLimitState) sweepLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case t := <-ticker.C:
+			s.sweep(ctx, t)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep 清除窗口已过期的条目，其中有被抑制日志的条目输出一条汇总
+func (s *rateLimitState) sweep(ctx context.Context, now time.Time) {
+	var summaries []slog.Record
+
+	s.mu.Lock()
+	for key, entry := range s.entries {
+		if now.Sub(entry.windowStart) < s.window {
+			continue
+		}
+		if entry.suppressed > 0 {
+			r := slog.NewRecord(now, key.level, fmt.Sprintf("%d messages suppressed", entry.suppressed), 0)
+			r.AddAttrs(slog.String("message", key.msg), slog.Int("suppressed", entry.suppressed))
+			summaries = append(summaries, r)
+		}
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+
+	// 在锁外输出，下游 handler 可能较慢
+	for _, r := range summaries {
+		if s.next.Enabled(ctx, r.Level) {
+			_ = s.next.Handle(ctx, r)
+		}
+	}
+}
+
+// pruneLocked 清理窗口已过期且没有被抑制日志的条目，有被抑制日志的条目由 sweep 汇总后清除
+func (s *rateLimitState) pruneLocked(now time.Time) {
+	for key, entry := range s.entries {
+		if now.Sub(entry.windowStart) >= s.window && entry.suppressed == 0 {
+			delete(s.entries, key)
+		}
+	}
+}
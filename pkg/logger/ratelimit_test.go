@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler 记录收到的日志
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *recordingHandler) list() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.records
+}
+
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
+
+func newTestRateLimiter(t *testing.T, window time.Duration) (*RateLimitHandler, *recordingHandler) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	rec := &recordingHandler{}
+	return NewRateLimitHandlerContext(ctx, rec, window).(*RateLimitHandler), rec
+}
+
+func TestRateLimitHandlerSuppressesDuplicates(t *testing.T) {
+	h, rec := newTestRateLimiter(t, time.Hour)
+	ctx := context.Background()
+	start := time.Unix(1700000000, 0)
+
+	for i := range 100 {
+		r := slog.NewRecord(start.Add(time.Duration(i)*time.Second), slog.LevelError, "probe sdb failed", 0)
+		if err := h.Handle(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 不同消息或级别不受影响
+	_ = h.Handle(ctx, slog.NewRecord(start, slog.LevelError, "probe sdc failed", 0))
+	_ = h.Handle(ctx, slog.NewRecord(start, slog.LevelWarn, "probe sdb failed", 0))
+
+	if got := len(rec.list()); got != 3 {
+		t.Fatalf("got %d records, want 3", got)
+	}
+
+	// 窗口过后再次出现时输出，并附带被抑制的条数
+	_ = h.Handle(ctx, slog.NewRecord(start.Add(time.Hour), slog.LevelError, "probe sdb failed", 0))
+	records := rec.list()
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+	if got := recordAttrs(records[3])["suppressed"]; got.Int64() != 99 {
+		t.Errorf("suppressed = %v, want 99", got)
+	}
+}
+
+func TestRateLimitHandlerSweepSummary(t *testing.T) {
+	h, rec := newTestRateLimiter(t, time.Minute)
+	ctx := context.Background()
+	start := time.Unix(1700000000, 0)
+
+	for i := range 5 {
+		_ = h.Handle(ctx, slog.NewRecord(start.Add(time.Duration(i)*time.Second), slog.LevelError, "probe sdb failed", 0))
+	}
+	_ = h.Handle(ctx, slog.NewRecord(start, slog.LevelInfo, "collected", 0))
+
+	// 窗口未过期时不汇总
+	h.state.sweep(ctx, start.Add(30*time.Second))
+	if got := len(rec.list()); got != 2 {
+		t.Fatalf("got %d records before the window expired, want 2", got)
+	}
+
+	h.state.sweep(ctx, start.Add(time.Minute))
+	records := rec.list()
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want the summary", len(records))
+	}
+	summary := records[2]
+	attrs := recordAttrs(summary)
+	if summary.Message != "4 messages suppressed" || summary.Level != slog.LevelError ||
+		attrs["message"].String() != "probe sdb failed" || attrs["suppressed"].Int64() != 4 {
+		t.Errorf("summary = %q %v %v", summary.Message, summary.Level, attrs)
+	}
+
+	h.state.mu.Lock()
+	entries := len(h.state.entries)
+	h.state.mu.Unlock()
+	if entries != 0 {
+		t.Errorf("%d expired entries kept", entries)
+	}
+
+	// 汇总后同一条消息重新计算窗口，不再重复附带已汇总的条数
+	_ = h.Handle(ctx, slog.NewRecord(start.Add(2*time.Minute), slog.LevelError, "probe sdb failed", 0))
+	if last := rec.list()[3]; len(recordAttrs(last)) != 0 {
+		t.Errorf("record after the summary has attrs %v", recordAttrs(last))
+	}
+}
+
+func TestRateLimitHandlerSharesStateWithAttrs(t *testing.T) {
+	h, rec := newTestRateLimiter(t, time.Hour)
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	_ = h.WithAttrs([]slog.Attr{slog.String("module", "disk")}).Handle(ctx, slog.NewRecord(now, slog.LevelError, "failed", 0))
+	_ = h.WithGroup("g").Handle(ctx, slog.NewRecord(now, slog.LevelError, "failed", 0))
+
+	if got := len(rec.list()); got != 1 {
+		t.Errorf("got %d records, want derived handlers to share the limiter", got)
+	}
+}
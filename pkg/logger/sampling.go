@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// sampleInterval 采样的计数周期
+const sampleInterval = time.Second
+
+// SamplingHandler 对低于 level 的日志采样：每个周期(1秒)内前 burst 条全部输出，
+// 之后每 rate 条输出一条；level 及以上级别的日志始终输出
+type SamplingHandler struct {
+	next  slog.Handler
+	level slog.Level
+	state *samplingState
+}
+
+type samplingState struct {
+	mu          sync.Mutex
+	rate        int
+	burst       int
+	windowStart time.Time
+	count       int // 本周期内被采样的日志条数
+}
+
+// NewSamplingHandler 创建采样 handler，rate <= 1 时每个周期超过 burst 的日志全部丢弃，
+// burst <= 0 时只按 rate 采样
+func NewSamplingHandler(next slog.Handler, level slog.Level, rate, burst int) slog.Handler {
+	return &SamplingHandler{
+		next:  next,
+		level: level,
+		state: &samplingState{rate: rate, burst: burst},
+	}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level && !h.state.sample(r.Time) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), level: h.level, state: h.state}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), level: h.level, state: h.state}
+}
+
+// sample 判断本条日志是否输出
+func (s *samplingState) sample(now time.Time) bool {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.windowStart) >= sampleInterval {
+		s.windowStart = now
+		s.count = 0
+	}
+	s.count++
+
+	if s.count <= s.burst {
+		return true
+	}
+	if s.rate <= 1 {
+		return s.burst <= 0
+	}
+	return (s.count-max(s.burst, 0)-1)%s.rate == 0
+}
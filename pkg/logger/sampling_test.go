@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		rate, burst int
+		want        int // 一个周期内 100 条 DEBUG 日志的输出条数
+	}{
+		{"rate only", 10, 0, 10},
+		{"burst only", 0, 5, 5},
+		{"burst then rate", 10, 5, 5 + 10},
+		{"pass through", 1, 0, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingHandler{}
+			h := NewSamplingHandler(rec, slog.LevelWarn, tt.rate, tt.burst)
+			for i := range 100 {
+				r := slog.NewRecord(start.Add(time.Duration(i)*time.Millisecond), slog.LevelDebug, "probe", 0)
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := len(rec.list()); got != tt.want {
+				t.Errorf("emitted %d of 100 records, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplingHandlerKeepsWarnings(t *testing.T) {
+	rec := &recordingHandler{}
+	h := NewSamplingHandler(rec, slog.LevelWarn, 0, 1)
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	for range 10 {
+		_ = h.Handle(context.Background(), slog.NewRecord(now, slog.LevelDebug, "debug", 0))
+		_ = h.Handle(context.Background(), slog.NewRecord(now, slog.LevelWarn, "warn", 0))
+		_ = h.Handle(context.Background(), slog.NewRecord(now, slog.LevelError, "error", 0))
+	}
+
+	levels := make(map[slog.Level]int)
+	for _, r := range rec.list() {
+		levels[r.Level]++
+	}
+	if levels[slog.LevelDebug] != 1 || levels[slog.LevelWarn] != 10 || levels[slog.LevelError] != 10 {
+		t.Errorf("emitted per level = %v, want 1 debug and every warn/error", levels)
+	}
+}
+
+func TestSamplingHandlerWindowReset(t *testing.T) {
+	rec := &recordingHandler{}
+	h := NewSamplingHandler(rec, slog.LevelWarn, 0, 2)
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	// 派生的 handler 共享同一采样计数
+	derived := h.WithAttrs([]slog.Attr{slog.String("module", "disk")}).WithGroup("g")
+	for i, handler := range []slog.Handler{h, derived, h, derived} {
+		_ = handler.Handle(context.Background(), slog.NewRecord(start.Add(time.Duration(i)*time.Millisecond), slog.LevelInfo, "info", 0))
+	}
+	if got := len(rec.list()); got != 2 {
+		t.Fatalf("emitted %d records in the first window, want the burst of 2", got)
+	}
+
+	next := start.Add(sampleInterval)
+	for i := range 3 {
+		_ = h.Handle(context.Background(), slog.NewRecord(next.Add(time.Duration(i)), slog.LevelInfo, "info", 0))
+	}
+	if got := len(rec.list()); got != 4 {
+		t.Errorf("emitted %d records after the window reset, want 4", got)
+	}
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import "time"
+
+// Metrics is a sink for collection and publishing instrumentation.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// IncCounter increments the counter identified by name and labels.
+	IncCounter(name string, labels map[string]string)
+	// ObserveDuration records a duration sample for the histogram identified by name and labels.
+	ObserveDuration(name string, d time.Duration, labels map[string]string)
+}
+
+// Nop is a Metrics that discards all observations.
+var Nop Metrics = nopMetrics{}
+
+type nopMetrics struct{}
+
+func (nopMetrics) IncCounter(string, map[string]string) {}
+
+func (nopMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
+
+// OrNop returns m, or Nop if m is nil.
+func OrNop(m Metrics) Metrics {
+	if m == nil {
+		return Nop
+	}
+	return m
+}
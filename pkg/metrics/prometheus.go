@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Prometheus is a Metrics backed by a prometheus.Registerer.
+// Counters and histograms are registered lazily on first use; the label
+// names of a metric are fixed by its first observation.
+type Prometheus struct {
+	namespace  string
+	registerer prometheus.Registerer
+
+	mu         sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// NewPrometheus returns a Metrics registering its collectors on reg under the given namespace.
+// If reg is nil, prometheus.DefaultRegisterer is used.
+func NewPrometheus(namespace string, reg prometheus.Registerer) *Prometheus {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	return &Prometheus{
+		namespace:  namespace,
+		registerer: reg,
+		counters:   make(map[string]*prometheus.CounterVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+	}
+}
+
+// IncCounter implements Metrics.
+func (p *Prometheus) IncCounter(name string, labels map[string]string) {
+	p.mu.Lock()
+	vec, ok := p.counters[name]
+	if !ok {
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+		}, labelNames(labels))
+		vec = registerOrExisting(p.registerer, vec)
+		p.counters[name] = vec
+	}
+	p.mu.Unlock()
+
+	if c, err := vec.GetMetricWith(labels); err == nil {
+		c.Inc()
+	}
+}
+
+// ObserveDuration implements Metrics. Durations are recorded in seconds.
+func (p *Prometheus) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	p.mu.Lock()
+	vec, ok := p.histograms[name]
+	if !ok {
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+			Buckets:   prometheus.DefBuckets,
+		}, labelNames(labels))
+		vec = registerOrExisting(p.registerer, vec)
+		p.histograms[name] = vec
+	}
+	p.mu.Unlock()
+
+	if h, err := vec.GetMetricWith(labels); err == nil {
+		h.Observe(d.Seconds())
+	}
+}
+
+func labelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// registerOrExisting registers c, returning the already registered collector if an identical one exists.
+func registerOrExisting[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewPrometheus("hw", reg)
+
+	m.IncCounter("publish_total", map[string]string{"result": "success"})
+	m.IncCounter("publish_total", map[string]string{"result": "success"})
+	m.IncCounter("publish_total", map[string]string{"result": "failure"})
+	m.ObserveDuration("publish_duration_seconds", 1500*time.Millisecond, map[string]string{"publisher": "kafka"})
+
+	want := `
+# HELP hw_publish_total publish_total
+# TYPE hw_publish_total counter
+hw_publish_total{result="failure"} 1
+hw_publish_total{result="success"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "hw_publish_total"); err != nil {
+		t.Error(err)
+	}
+
+	if n, err := testutil.GatherAndCount(reg, "hw_publish_duration_seconds"); err != nil || n != 1 {
+		t.Errorf("histogram series = %d, %v, want 1", n, err)
+	}
+}
+
+// A second Prometheus on the same registry reuses the collectors registered by the first.
+func TestPrometheusSharedRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	NewPrometheus("hw", reg).IncCounter("cycles_total", nil)
+	NewPrometheus("hw", reg).IncCounter("cycles_total", nil)
+
+	if got := testutil.ToFloat64(mustCounter(t, reg)); got != 2 {
+		t.Errorf("cycles_total = %v, want 2", got)
+	}
+}
+
+func mustCounter(t *testing.T, reg *prometheus.Registry) prometheus.Collector {
+	t.Helper()
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: "hw", Name: "cycles_total", Help: "cycles_total"}, nil)
+	return registerOrExisting(reg, vec)
+}
+
+func TestOrNop(t *testing.T) {
+	if OrNop(nil) != Nop {
+		t.Error("OrNop(nil) is not Nop")
+	}
+	p := NewPrometheus("hw", prometheus.NewRegistry())
+	if OrNop(p) != Metrics(p) {
+		t.Error("OrNop(m) does not return m")
+	}
+}
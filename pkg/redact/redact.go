@@ -0,0 +1,187 @@
+// Package redact masks sensitive fields of a value before it leaves the host.
+//
+// Fields are selected by their JSON names. A path containing dots, such as
+// "network.net_interfaces.mac_address", is matched from the root of the value;
+// arrays are traversed transparently and "*" matches any single field. A path
+// without dots, such as "serial", matches that field at any depth.
+//
+// [ModeHash] output is pseudonymous, not anonymous: the same value always maps to the
+// same digest, so reports remain linkable. The digest is keyed, so without the key it
+// cannot be reversed by hashing a list of candidate serials or MAC addresses, but anyone
+// holding the key can. Use a secret key per deployment and keep it off the consumers.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Mode controls how a redacted value is replaced.
+type Mode string
+
+const (
+	ModeHash  Mode = "hash"  // replace with a stable HMAC-SHA256 of the value, keyed per deployment
+	ModeToken Mode = "token" // replace with a fixed token
+)
+
+// Token is the replacement used by [ModeToken].
+const Token = "[REDACTED]"
+
+// Redactor replaces the configured fields of a value.
+type Redactor struct {
+	mode     Mode
+	key      []byte
+	anchored [][]string
+	anywhere map[string]bool
+}
+
+// New returns a Redactor for the given field paths. An empty mode defaults to [ModeHash],
+// which requires a non-empty key; the key is ignored by [ModeToken].
+func New(paths []string, mode Mode, key string) (*Redactor, error) {
+	if mode == "" {
+		mode = ModeHash
+	}
+	if mode != ModeHash && mode != ModeToken {
+		return nil, fmt.Errorf("unsupported redact mode: %s", mode)
+	}
+	if mode == ModeHash && key == "" {
+		return nil, fmt.Errorf("redact mode %s requires a key", mode)
+	}
+
+	r := &Redactor{mode: mode, key: []byte(key), anywhere: make(map[string]bool)}
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, ".") {
+			r.anywhere[p] = true
+			continue
+		}
+		r.anchored = append(r.anchored, strings.Split(p, "."))
+	}
+
+	return r, nil
+}
+
+// Empty reports whether r redacts nothing.
+func (r *Redactor) Empty() bool {
+	return r == nil || (len(r.anchored) == 0 && len(r.anywhere) == 0)
+}
+
+// Apply returns a redacted copy of v; v itself is left untouched.
+func Apply[T any](r *Redactor, v *T) (*T, error) {
+	if r.Empty() || v == nil {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	tree = r.walk(tree, nil)
+
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, err
+	}
+
+	out := new(T)
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Redactor) walk(node any, path []string) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			child := append(path[:len(path):len(path)], k)
+			if r.match(child) {
+				n[k] = r.replace(v)
+				continue
+			}
+			n[k] = r.walk(v, child)
+		}
+	case []any:
+		for i, v := range n {
+			n[i] = r.walk(v, path)
+		}
+	}
+	return node
+}
+
+func (r *Redactor) match(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	if r.anywhere[path[len(path)-1]] {
+		return true
+	}
+
+	for _, pattern := range r.anchored {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, seg := range pattern {
+			if seg != "*" && seg != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// replace masks string values, including strings inside arrays; other values are kept.
+func (r *Redactor) replace(v any) any {
+	switch val := v.(type) {
+	case string:
+		if val == "" {
+			return val
+		}
+		if r.mode == ModeToken {
+			return Token
+		}
+		mac := hmac.New(sha256.New, r.key)
+		mac.Write([]byte(val))
+		return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)[:16])
+	case []any:
+		for i := range val {
+			val[i] = r.replace(val[i])
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// HostnameSaltEnv is the environment variable the CLI reads the hostname salt from.
+const HostnameSaltEnv = "DITING_HOSTNAME_SALT"
+
+// KeyEnv is the environment variable the CLI reads the [ModeHash] key from.
+const KeyEnv = "DITING_REDACT_KEY"
+
+// HashHostname returns a pseudonym for hostname: a truncated HMAC-SHA256 keyed with salt.
+// The result is stable for a given hostname and salt, so a host keeps the same identity
+// across reports, but it cannot be reversed or matched against a list of known names
+// without the salt.
+func HashHostname(hostname, salt string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(hostname))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,150 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type nic struct {
+	Name string   `json:"name"`
+	MAC  string   `json:"mac_address"`
+	IPs  []string `json:"ips,omitempty"`
+}
+
+type host struct {
+	Hostname string `json:"hostname"`
+	Serial   string `json:"serial"`
+	Board    struct {
+		Serial string `json:"serial"`
+		Name   string `json:"name"`
+	} `json:"board"`
+	NICs []nic `json:"nics"`
+	MTU  int   `json:"mtu"`
+}
+
+func newHost() *host {
+	h := &host{Hostname: "node-1", Serial: "SN123", MTU: 1500}
+	h.Board.Serial = "BSN456"
+	h.Board.Name = "X12DPi"
+	h.NICs = []nic{
+		{Name: "eth0", MAC: "52:54:00:12:34:56", IPs: []string{"10.0.0.5", "10.0.0.6"}},
+		{Name: "eth1", MAC: ""},
+	}
+	return h
+}
+
+func TestApplyToken(t *testing.T) {
+	r, err := New([]string{"serial", "nics.mac_address", "nics.ips"}, ModeToken, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := newHost()
+
+	out, err := Apply(r, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := newHost()
+	want.Serial = Token
+	want.Board.Serial = Token
+	want.NICs[0].MAC = Token
+	want.NICs[0].IPs = []string{Token, Token}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("Apply() = %+v, want %+v", out, want)
+	}
+	if !reflect.DeepEqual(in, newHost()) {
+		t.Errorf("Apply() modified its input: %+v", in)
+	}
+}
+
+func TestApplyHash(t *testing.T) {
+	r, err := New([]string{"board.*"}, "", "key-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Apply(r, newHost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Apply(r, newHost())
+
+	const prefix = "hmac-sha256:"
+	if !strings.HasPrefix(a.Board.Serial, prefix) || len(a.Board.Serial) != len(prefix)+32 {
+		t.Errorf("board.serial = %q, want a prefixed 128-bit HMAC", a.Board.Serial)
+	}
+	if a.Board.Serial != b.Board.Serial {
+		t.Errorf("hash is not stable: %q != %q", a.Board.Serial, b.Board.Serial)
+	}
+	if a.Board.Name == a.Board.Serial {
+		t.Error("different values hash to the same result")
+	}
+	// An anchored path only matches from the root.
+	if a.Serial != "SN123" {
+		t.Errorf("serial = %q, want it untouched", a.Serial)
+	}
+
+	// Another deployment's key yields unrelated digests, and a plain SHA-256 of a guessed
+	// serial does not match.
+	other, err := New([]string{"board.*"}, ModeHash, "key-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := Apply(other, newHost())
+	if c.Board.Serial == a.Board.Serial {
+		t.Error("different keys hash to the same result")
+	}
+	plain := sha256.Sum256([]byte("BSN456"))
+	if strings.Contains(a.Board.Serial, hex.EncodeToString(plain[:8])) {
+		t.Errorf("board.serial = %q is an unkeyed SHA-256", a.Board.Serial)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, "rot13", "key"); err == nil {
+		t.Error("New() with an unknown mode succeeded")
+	}
+	for _, mode := range []Mode{"", ModeHash} {
+		if _, err := New([]string{"serial"}, mode, ""); err == nil {
+			t.Errorf("New() in mode %q without a key succeeded", mode)
+		}
+	}
+
+	r, err := New([]string{" ", ""}, ModeToken, "")
+	if err != nil || !r.Empty() {
+		t.Errorf("New() with blank paths = %v, %v, want an empty redactor", r, err)
+	}
+
+	in := newHost()
+	if out, _ := Apply(r, in); out != in {
+		t.Error("an empty redactor copied its input")
+	}
+}
+
+func TestHashHostname(t *testing.T) {
+	a := HashHostname("node-1.example.com", "salt-1")
+	if a != HashHostname("node-1.example.com", "salt-1") {
+		t.Error("HashHostname() is not stable for the same host and salt")
+	}
+	if !strings.HasPrefix(a, "hmac-sha256:") || len(a) != len("hmac-sha256:")+32 {
+		t.Errorf("HashHostname() = %q, want a prefixed 128-bit hex digest", a)
+	}
+	if strings.Contains(a, "node-1") {
+		t.Errorf("HashHostname() = %q leaks the hostname", a)
+	}
+
+	// Different hosts, or the same host under a different salt, get different pseudonyms.
+	for _, other := range []string{
+		HashHostname("node-2.example.com", "salt-1"),
+		HashHostname("node-1.example.com", "salt-2"),
+		HashHostname("", "salt-1"),
+	} {
+		if other == a {
+			t.Errorf("HashHostname() collision: %q", other)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package replay
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractedDir is the temporary directory a bundle was unpacked into by [Enable],
+// removed again by [Disable].
+var extractedDir string
+
+// isBundle reports whether path names a capture packaged as a tarball.
+func isBundle(path string) bool {
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractBundle unpacks the tarball at path, gzip-compressed or not, into a new temporary
+// directory and returns the capture directory inside it. A bundle may hold the capture at
+// its top level or inside a single directory, as produced by "tar czf bundle.tar.gz <dir>".
+func extractBundle(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open replay bundle failed: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(path, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return "", fmt.Errorf("decompress replay bundle failed: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	dir, err := os.MkdirTemp("", "diting-replay-")
+	if err != nil {
+		return "", fmt.Errorf("create replay directory failed: %w", err)
+	}
+	if err := untar(r, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("extract replay bundle failed: %w", err)
+	}
+
+	return dir, nil
+}
+
+// untar writes the regular files and directories of the tar stream r below dir.
+// Entries that would escape dir are rejected and other entry types are skipped.
+func untar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("entry %q escapes the bundle", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.Create(target)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// captureRoot returns the directory holding the capture layout below dir:
+// dir itself, or its only subdirectory when the bundle wrapped the capture in one.
+func captureRoot(dir string) string {
+	if hasCaptureLayout(dir) {
+		return dir
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	if sub := filepath.Join(dir, entries[0].Name()); hasCaptureLayout(sub) {
+		return sub
+	}
+	return dir
+}
+
+func hasCaptureLayout(dir string) bool {
+	for _, name := range []string{CommandsDir, RootfsDir} {
+		if fi, err := os.Stat(filepath.Join(dir, name)); err == nil && fi.IsDir() {
+			return true
+		}
+	}
+	return false
+}
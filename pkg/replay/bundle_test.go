@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// writeBundle writes files to a tarball at path, gzip-compressed unless path ends in .tar.
+func writeBundle(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if filepath.Ext(path) != ".tar" {
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		if _, err := zw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data = gz.Bytes()
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnableBundle(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+	}{
+		{"capture.tar.gz", ""},
+		{"capture.tgz", "node-1/"},
+		{"capture.tar", "node-1/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.name)
+			writeBundle(t, path, map[string]string{
+				tt.prefix + "commands/uname-r.txt":            "6.8.0\n",
+				tt.prefix + "rootfs/proc/sys/kernel/hostname": "replayed\n",
+			})
+
+			if err := Enable(path); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(Disable)
+			extracted := extractedDir
+
+			out, err := executor.ExecuteWithContext(context.Background(), "uname", "-r")
+			if err != nil || string(out) != "6.8.0\n" {
+				t.Errorf("ExecuteWithContext() = %q, %v, want the bundled output", out, err)
+			}
+			if got, err := utils.ReadSysfsFile(utils.HostPath("/proc/sys/kernel/hostname")); err != nil || got != "replayed" {
+				t.Errorf("ReadSysfsFile() = %q, %v, want the bundled file", got, err)
+			}
+
+			// Disable removes the unpacked files.
+			Disable()
+			if _, err := os.Stat(extracted); !os.IsNotExist(err) {
+				t.Errorf("extracted bundle %s still exists after Disable: %v", extracted, err)
+			}
+		})
+	}
+}
+
+func TestEnableBundleInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	escaping := filepath.Join(dir, "escape.tar.gz")
+	writeBundle(t, escaping, map[string]string{"../outside.txt": "x"})
+	if err := Enable(escaping); err == nil {
+		Disable()
+		t.Error("Enable() of a bundle escaping its directory succeeded")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.tgz")
+	if err := os.WriteFile(corrupt, []byte("not gzip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Enable(corrupt); err == nil {
+		Disable()
+		t.Error("Enable() of a corrupt bundle succeeded")
+	}
+
+	if err := Enable(filepath.Join(dir, "missing.tar.gz")); err == nil {
+		Disable()
+		t.Error("Enable() of a missing bundle succeeded")
+	}
+}
@@ -0,0 +1,59 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+// CaptureEnv names the environment variable that enables capture mode when set to a directory.
+const CaptureEnv = "DITING_CAPTURE_DIR"
+
+// EnableCapture records the output of every executed command and the contents of every
+// sysfs/procfs file read through utils into dir, using the layout described in the package
+// documentation, so that the capture can later be replayed with [Enable].
+// It must be called before collection begins.
+func EnableCapture(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, CommandsDir), 0755); err != nil {
+		return fmt.Errorf("create capture directory failed: %w", err)
+	}
+
+	executor.SetRunner(captureRunner(filepath.Join(dir, CommandsDir), executor.Runner()))
+	utils.SetReadHook(func(path string, data []byte) {
+		_ = writeCaptureFile(filepath.Join(dir, RootfsDir, path), data)
+	})
+	return nil
+}
+
+// DisableCapture stops recording and restores live command execution.
+func DisableCapture() {
+	executor.SetRunner(nil)
+	utils.SetReadHook(nil)
+}
+
+func captureRunner(dir string, next executor.CommandRunner) executor.CommandRunner {
+	return func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		out, err := next(ctx, name, args...)
+
+		path := filepath.Join(dir, CommandFileName(name, args...))
+		_ = writeCaptureFile(path, out)
+		if err != nil {
+			_ = writeCaptureFile(path+".err", []byte(err.Error()+"\n"))
+		} else {
+			_ = os.Remove(path + ".err")
+		}
+
+		return out, err
+	}
+}
+
+func writeCaptureFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
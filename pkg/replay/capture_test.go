@@ -0,0 +1,65 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCapture(t *testing.T) {
+	executor.SetRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if name == "smartctl" {
+			return []byte("partial output\n"), errors.New("exit status 4")
+		}
+		return []byte("driver: ixgbe\n"), nil
+	})
+	t.Cleanup(func() { executor.SetRunner(nil) })
+
+	host := t.TempDir()
+	sysfsFile := filepath.Join(host, "address")
+	if err := os.WriteFile(sysfsFile, []byte("52:54:00:12:34:56\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := EnableCapture(dir); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := executor.ExecuteWithContext(ctx, "ethtool", "-i", "eth0"); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = executor.ExecuteWithContext(ctx, "smartctl", "-a", "/dev/sda")
+	if _, err := utils.ReadSysfsFile(sysfsFile); err != nil {
+		t.Fatal(err)
+	}
+	DisableCapture()
+
+	want := map[string]string{
+		"commands/ethtool-i-eth0.txt":          "driver: ixgbe\n",
+		"commands/smartctl-a-_dev_sda.txt":     "partial output\n",
+		"commands/smartctl-a-_dev_sda.txt.err": "exit status 4\n",
+		filepath.Join(RootfsDir, sysfsFile):    "52:54:00:12:34:56\n",
+	}
+	for path, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Errorf("capture file %s: %v", path, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("capture file %s = %q, want %q", path, data, content)
+		}
+	}
+
+	// The capture replays exactly as recorded.
+	run := Runner(filepath.Join(dir, CommandsDir))
+	if out, err := run(ctx, "smartctl", "-a", "/dev/sda"); string(out) != "partial output\n" || err == nil || err.Error() != "exit status 4" {
+		t.Errorf("replayed smartctl = %q, %v", out, err)
+	}
+}
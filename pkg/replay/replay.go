@@ -0,0 +1,120 @@
+// Package replay lets collectors run against a captured host instead of the live system.
+//
+// A capture directory has the following layout:
+//
+//	<dir>/commands/<file>   output of each executed tool
+//	<dir>/rootfs/           sysfs/procfs files read during collection, laid out
+//	                        as on the host, e.g. <dir>/rootfs/sys/class/net/eth0/address
+//
+// The command file name is derived from the command line by [CommandFileName]:
+// the program name followed by each argument with leading dashes stripped,
+// joined by "-", with path separators and whitespace replaced by "_", plus ".txt".
+// For example "ethtool -i eth0" is read from "ethtool-i-eth0.txt" and
+// "lspci -vmmnnk" from "lspci-vmmnnk.txt". A command that failed when it was
+// captured has an additional "<file>.err" holding the error message.
+//
+// A capture can also be shared as a single .tar, .tar.gz or .tgz bundle of that
+// directory, which [Enable] unpacks to a temporary directory before replaying.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+const (
+	CommandsDir = "commands"
+	RootfsDir   = "rootfs"
+)
+
+// Enable redirects command execution and sysfs/procfs reads to the capture in dir,
+// which is either a capture directory or a bundle of one (see the package documentation).
+// It must be called before collection begins.
+func Enable(dir string) error {
+	if isBundle(dir) {
+		extracted, err := extractBundle(dir)
+		if err != nil {
+			return err
+		}
+		Disable()
+		extractedDir = extracted
+		dir = captureRoot(extracted)
+	}
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("stat replay directory failed: %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("replay path %s is not a directory", dir)
+	}
+
+	executor.SetRunner(Runner(filepath.Join(dir, CommandsDir)))
+	utils.SetRoot(filepath.Join(dir, RootfsDir))
+	return nil
+}
+
+// Disable restores live command execution and sysfs/procfs reads, and removes
+// the files unpacked from a bundle.
+func Disable() {
+	executor.SetRunner(nil)
+	utils.SetRoot("")
+
+	if extractedDir != "" {
+		_ = os.RemoveAll(extractedDir)
+		extractedDir = ""
+	}
+}
+
+// Runner returns an [executor.CommandRunner] that serves command output from files in dir.
+// Commands without a captured file fail with an error wrapping [exec.ErrNotFound],
+// so collectors degrade the same way as when the tool is not installed.
+func Runner(dir string) executor.CommandRunner {
+	return func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, CommandFileName(name, args...))
+		out, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("%w: %s not captured", exec.ErrNotFound, filepath.Base(path))
+			}
+			return nil, err
+		}
+
+		// Reproduce the error exactly as it was observed during capture.
+		if msg, err := os.ReadFile(path + ".err"); err == nil {
+			return out, errors.New(strings.TrimSpace(string(msg)))
+		}
+
+		return out, nil
+	}
+}
+
+var fileNameReplacer = strings.NewReplacer("/", "_", " ", "_", "\t", "_", "\n", "_")
+
+// CommandFileName returns the capture file name for the given command line.
+func CommandFileName(name string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, filepath.Base(name))
+	for _, arg := range args {
+		arg = strings.TrimLeft(arg, "-")
+		if arg == "" {
+			continue
+		}
+		parts = append(parts, arg)
+	}
+
+	return fileNameReplacer.Replace(strings.Join(parts, "-")) + ".txt"
+}
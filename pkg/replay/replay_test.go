@@ -0,0 +1,113 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/zenithax-cc/diting/pkg/executor"
+	"github.com/zenithax-cc/diting/pkg/utils"
+)
+
+func TestCommandFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"ethtool", []string{"-i", "eth0"}, "ethtool-i-eth0.txt"},
+		{"lspci", []string{"-vmmnnk"}, "lspci-vmmnnk.txt"},
+		{"/usr/sbin/smartctl", []string{"-a", "/dev/sda"}, "smartctl-a-_dev_sda.txt"},
+		{"ip", []string{"--", "neigh", "show dev"}, "ip-neigh-show_dev.txt"},
+	}
+	for _, tt := range tests {
+		if got := CommandFileName(tt.name, tt.args...); got != tt.want {
+			t.Errorf("CommandFileName(%q, %q) = %q, want %q", tt.name, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunner(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("ethtool-i-eth0.txt", "driver: ixgbe\n")
+	write("smartctl-a-_dev_sda.txt", "partial output\n")
+	write("smartctl-a-_dev_sda.txt.err", "exit status 4\n")
+
+	run := Runner(dir)
+	ctx := context.Background()
+
+	out, err := run(ctx, "ethtool", "-i", "eth0")
+	if err != nil || string(out) != "driver: ixgbe\n" {
+		t.Errorf("ethtool = %q, %v", out, err)
+	}
+
+	// A captured failure is reproduced together with its output.
+	out, err = run(ctx, "smartctl", "-a", "/dev/sda")
+	if err == nil || err.Error() != "exit status 4" || string(out) != "partial output\n" {
+		t.Errorf("smartctl = %q, %v, want the output and the captured error", out, err)
+	}
+
+	// Missing commands look like tools that are not installed.
+	if _, err := run(ctx, "nvidia-smi"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("missing command err = %v, want exec.ErrNotFound", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := run(canceled, "ethtool", "-i", "eth0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled err = %v, want context.Canceled", err)
+	}
+}
+
+func TestEnable(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"commands/uname-r.txt":            "6.8.0\n",
+		"rootfs/proc/sys/kernel/hostname": "replayed\n",
+	} {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Enable(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Disable)
+
+	out, err := executor.ExecuteWithContext(context.Background(), "uname", "-r")
+	if err != nil || string(out) != "6.8.0\n" {
+		t.Errorf("ExecuteWithContext() = %q, %v, want the captured output", out, err)
+	}
+	if got, err := utils.ReadSysfsFile(utils.HostPath("/proc/sys/kernel/hostname")); err != nil || got != "replayed" {
+		t.Errorf("ReadSysfsFile() = %q, %v, want the captured file", got, err)
+	}
+
+	Disable()
+	if got := utils.HostPath("/proc/sys/kernel/hostname"); got != "/proc/sys/kernel/hostname" {
+		t.Errorf("HostPath() after Disable = %q, want the live path", got)
+	}
+}
+
+func TestEnableRejectsFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "capture")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Enable(file); err == nil {
+		Disable()
+		t.Fatal("Enable() on a regular file succeeded")
+	}
+}
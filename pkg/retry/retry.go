@@ -0,0 +1,90 @@
+// Package retry runs an operation again after transient failures, waiting an
+// exponentially growing, optionally jittered delay between attempts. It is shared
+// by the publishers so that each transport does not reimplement the same loop.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy describes how an operation is retried. The zero value runs it once.
+type Policy struct {
+	MaxAttempts int           // total attempts including the first; values below 1 mean 1
+	BaseBackoff time.Duration // wait after the first failure, doubled after each further failure
+	MaxBackoff  time.Duration // upper bound of a single wait; 0 means unbounded
+	Jitter      float64       // fraction in [0, 1] of each wait that is randomized, so many hosts do not retry in step
+
+	// Retryable reports whether err is worth another attempt. A nil Retryable retries
+	// every error except context cancellation and deadline expiry.
+	Retryable func(err error) bool
+}
+
+// Backoff returns the wait after the given number of failed attempts (starting at 1),
+// before jitter is applied.
+func (p Policy) Backoff(failures int) time.Duration {
+	if failures < 1 || p.BaseBackoff <= 0 {
+		return 0
+	}
+
+	d := p.BaseBackoff
+	for i := 1; i < failures; i++ {
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+		// stop doubling before the duration overflows
+		if d > math.MaxInt64/2 {
+			break
+		}
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// Do calls fn until it succeeds, returns an error that is not retryable, or
+// MaxAttempts is reached, and returns the last error. If ctx is done while
+// waiting, Do stops and returns an error wrapping both ctx.Err() and the last error.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := max(p.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt >= attempts || !p.retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.jitter(p.Backoff(attempt)))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry stopped after %d attempts: %w", attempt, errors.Join(ctx.Err(), err))
+		}
+	}
+}
+
+func (p Policy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// jitter subtracts a random part of up to Jitter*d from d.
+func (p Policy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	j := min(p.Jitter, 1)
+	return d - time.Duration(rand.Float64()*j*float64(d))
+}
@@ -0,0 +1,133 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	p := Policy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.Backoff(tt.failures); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	// Without a maximum the wait keeps doubling but never overflows.
+	unbounded := Policy{BaseBackoff: time.Second}
+	if got := unbounded.Backoff(200); got <= 0 {
+		t.Errorf("unbounded Backoff(200) = %v, want a positive duration", got)
+	}
+	if got := (Policy{}).Backoff(3); got != 0 {
+		t.Errorf("zero policy Backoff(3) = %v, want 0", got)
+	}
+}
+
+// failing returns an operation that fails with err the first n times and then succeeds,
+// and a pointer to the number of calls made.
+func failing(n int, err error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}, &calls
+}
+
+func TestDoSucceedsAfterRetries(t *testing.T) {
+	fn, calls := failing(2, errors.New("broker unavailable"))
+	p := Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+
+	if err := p.Do(context.Background(), fn); err != nil {
+		t.Fatalf("Do() = %v, want success on the third attempt", err)
+	}
+	if *calls != 3 {
+		t.Errorf("made %d attempts, want 3", *calls)
+	}
+}
+
+func TestDoExhaustsAttempts(t *testing.T) {
+	errDown := errors.New("broker unavailable")
+	fn, calls := failing(10, errDown)
+	p := Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+
+	if err := p.Do(context.Background(), fn); !errors.Is(err, errDown) {
+		t.Fatalf("Do() = %v, want the last error", err)
+	}
+	if *calls != 3 {
+		t.Errorf("made %d attempts, want 3", *calls)
+	}
+
+	// The zero policy runs the operation exactly once.
+	fn, calls = failing(10, errDown)
+	if err := (Policy{}).Do(context.Background(), fn); !errors.Is(err, errDown) || *calls != 1 {
+		t.Errorf("zero policy: err = %v after %d attempts, want one attempt", err, *calls)
+	}
+}
+
+func TestDoStopsOnNonRetryable(t *testing.T) {
+	errAuth := errors.New("authentication failed")
+	p := Policy{
+		MaxAttempts: 5,
+		BaseBackoff: time.Millisecond,
+		Retryable:   func(err error) bool { return !errors.Is(err, errAuth) },
+	}
+	fn, calls := failing(10, errAuth)
+	if err := p.Do(context.Background(), fn); !errors.Is(err, errAuth) || *calls != 1 {
+		t.Errorf("err = %v after %d attempts, want one attempt", err, *calls)
+	}
+
+	// By default context errors are not retried.
+	fn, calls = failing(10, context.DeadlineExceeded)
+	if err := (Policy{MaxAttempts: 5}).Do(context.Background(), fn); !errors.Is(err, context.DeadlineExceeded) || *calls != 1 {
+		t.Errorf("err = %v after %d attempts, want one attempt", err, *calls)
+	}
+}
+
+func TestDoStopsWhenContextDone(t *testing.T) {
+	errDown := errors.New("broker unavailable")
+	fn, calls := failing(10, errDown)
+	p := Policy{MaxAttempts: 5, BaseBackoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := p.Do(ctx, fn)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errDown) {
+		t.Errorf("Do() = %v, want both the context error and the last error", err)
+	}
+	if *calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("made %d attempts in %v, want Do to stop during the first wait", *calls, time.Since(start))
+	}
+}
+
+func TestJitter(t *testing.T) {
+	p := Policy{Jitter: 0.5}
+	const d = time.Second
+	for range 100 {
+		if got := p.jitter(d); got < d/2 || got > d {
+			t.Fatalf("jitter(%v) = %v, want within [%v, %v]", d, got, d/2, d)
+		}
+	}
+	if got := (Policy{Jitter: 5}).jitter(d); got < 0 || got > d {
+		t.Errorf("jitter above 1 gave %v, want it clamped", got)
+	}
+	if got := (Policy{}).jitter(d); got != d {
+		t.Errorf("no jitter gave %v, want %v", got, d)
+	}
+}
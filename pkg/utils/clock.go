@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// Clock reports the current time. Components that stamp or compare times take a Clock
+// so that tests can substitute one they control.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock backed by time.Now.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// ClockOrReal returns c, or RealClock if c is nil.
+func ClockOrReal(c Clock) Clock {
+	if c == nil {
+		return RealClock
+	}
+	return c
+}
+
+type clockKey struct{}
+
+// WithClock returns a copy of ctx carrying c, collectors read it through [ClockFrom].
+func WithClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// ClockFrom returns the Clock carried by ctx, or RealClock when none is set.
+func ClockFrom(ctx context.Context) Clock {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok && c != nil {
+		return c
+	}
+	return RealClock
+}
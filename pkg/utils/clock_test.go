@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestClockOrReal(t *testing.T) {
+	fixed := fixedClock(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	if got := ClockOrReal(fixed).Now(); !got.Equal(time.Time(fixed)) {
+		t.Errorf("ClockOrReal(fixed).Now() = %v, want %v", got, time.Time(fixed))
+	}
+
+	before := time.Now()
+	got := ClockOrReal(nil).Now()
+	if got.Before(before) || time.Since(got) > time.Minute {
+		t.Errorf("ClockOrReal(nil).Now() = %v, want the current time", got)
+	}
+}
+
+func TestClockFrom(t *testing.T) {
+	if got := ClockFrom(context.Background()); got != RealClock {
+		t.Errorf("ClockFrom() without a clock = %v, want RealClock", got)
+	}
+
+	fixed := fixedClock(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	if got := ClockFrom(WithClock(context.Background(), fixed)).Now(); !got.Equal(time.Time(fixed)) {
+		t.Errorf("ClockFrom(WithClock(fixed)).Now() = %v, want %v", got, time.Time(fixed))
+	}
+	if got := ClockFrom(WithClock(context.Background(), nil)); got != RealClock {
+		t.Errorf("ClockFrom() with a nil clock = %v, want RealClock", got)
+	}
+}
@@ -26,3 +26,23 @@ func ParseKeyValue(text string, sep string) map[string]string {
 	}
 	return result
 }
+
+// FirstLine returns the first line of s without the trailing newline, or "" if s is empty.
+func FirstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSuffix(line, "\r")
+}
+
+// Field returns the nth (1-based) whitespace-separated field of s,
+// or "" if s has fewer than n fields or n is not positive.
+func Field(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	fields := strings.Fields(s)
+	if n > len(fields) {
+		return ""
+	}
+	return fields[n-1]
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestFirstLine(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"\n":                        "",
+		"0.52 0.58 0.59 2/1024 7\n": "0.52 0.58 0.59 2/1024 7",
+		"first\nsecond\n":           "first",
+		"no newline":                "no newline",
+		"windows\r\nline\r\n":       "windows",
+	}
+	for in, want := range tests {
+		if got := FirstLine(in); got != want {
+			t.Errorf("FirstLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestField(t *testing.T) {
+	const loadavg = "0.52 0.58 0.59 2/1024 7\n"
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{loadavg, 1, "0.52"},
+		{loadavg, 4, "2/1024"},
+		{loadavg, 5, "7"},
+		{loadavg, 6, ""},
+		{loadavg, 0, ""},
+		{loadavg, -1, ""},
+		{"", 1, ""},
+		{"  \t spaced \t out  ", 2, "out"},
+	}
+	for _, tt := range tests {
+		if got := Field(tt.s, tt.n); got != tt.want {
+			t.Errorf("Field(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+var excludedDevices []string
+
+// SetExcludedDevices sets the devices collectors must skip without probing, e.g. a disk or NIC
+// known to hang when accessed. Each pattern is a device name ("sdb", "eth2") or PCI address
+// ("0000:3b:00.0") and may contain [filepath.Match] wildcards ("nvme1*").
+// It is not safe to call concurrently with collection.
+func SetExcludedDevices(patterns []string) {
+	excludedDevices = excludedDevices[:0]
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			excludedDevices = append(excludedDevices, p)
+		}
+	}
+}
+
+// DeviceExcluded reports whether any of the given identifiers of a device
+// (name, PCI address, ...) matches a pattern set by [SetExcludedDevices].
+func DeviceExcluded(ids ...string) bool {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		for _, p := range excludedDevices {
+			if p == id {
+				return true
+			}
+			if ok, _ := filepath.Match(p, id); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
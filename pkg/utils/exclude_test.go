@@ -0,0 +1,32 @@
+package utils
+
+import "testing"
+
+func TestDeviceExcluded(t *testing.T) {
+	SetExcludedDevices([]string{"sdb", " eth2 ", "", "nvme1*", "0000:3b:00.0"})
+	t.Cleanup(func() { SetExcludedDevices(nil) })
+
+	tests := []struct {
+		ids  []string
+		want bool
+	}{
+		{[]string{"sdb"}, true},
+		{[]string{"sdb1"}, false},
+		{[]string{"eth2"}, true},
+		{[]string{"nvme1n1"}, true},
+		{[]string{"nvme0n1"}, false},
+		{[]string{"gpu0", "0000:3b:00.0"}, true},
+		{[]string{"", "sda"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := DeviceExcluded(tt.ids...); got != tt.want {
+			t.Errorf("DeviceExcluded(%q) = %v, want %v", tt.ids, got, tt.want)
+		}
+	}
+
+	SetExcludedDevices(nil)
+	if DeviceExcluded("sdb") {
+		t.Error("DeviceExcluded() after clearing the list = true")
+	}
+}
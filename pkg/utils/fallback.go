@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Source is one way of obtaining a value, such as reading sysfs or running a tool.
+type Source[T any] struct {
+	Name string // recorded as the origin of the value, e.g. "sysfs" or "ethtool"
+	Get  func() (T, error)
+}
+
+// FirstOf tries sources in order and returns the first non-empty value produced without
+// error, together with the name of its source. A value is empty if it is the zero value of T
+// or a slice or map of length zero. When no source yields a value it returns the zero value,
+// an empty name and the errors of the sources that failed, or nil if they all succeeded with
+// an empty result.
+func FirstOf[T any](sources ...Source[T]) (T, string, error) {
+	var errs []error
+	for _, s := range sources {
+		v, err := s.Get()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name, err))
+			continue
+		}
+		if !isEmpty(reflect.ValueOf(&v).Elem()) {
+			return v, s.Name, nil
+		}
+	}
+
+	var zero T
+	return zero, "", errors.Join(errs...)
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
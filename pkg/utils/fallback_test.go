@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+// source returns a Source named name that yields v and err and counts its calls.
+func source[T any](name string, v T, err error, calls *int) Source[T] {
+	return Source[T]{Name: name, Get: func() (T, error) {
+		*calls++
+		return v, err
+	}}
+}
+
+func TestFirstOfFallsBack(t *testing.T) {
+	errNotFound := errors.New("ethtool not found")
+	var calls int
+
+	v, name, err := FirstOf(
+		source("ethtool", "", errNotFound, &calls),
+		source("sysfs", "ixgbe", nil, &calls),
+		source("modinfo", "never", nil, &calls),
+	)
+	if err != nil || v != "ixgbe" || name != "sysfs" {
+		t.Errorf("FirstOf() = %q, %q, %v, want ixgbe from sysfs", v, name, err)
+	}
+	// Sources after the first success are not tried.
+	if calls != 2 {
+		t.Errorf("tried %d sources, want 2", calls)
+	}
+}
+
+func TestFirstOfSkipsEmpty(t *testing.T) {
+	var calls int
+	v, name, err := FirstOf(
+		source[[]string]("sysfs", []string{}, nil, &calls),
+		source[[]string]("ethtool", nil, nil, &calls),
+		source("lspci", []string{"0000:3b:00.0"}, nil, &calls),
+	)
+	if err != nil || len(v) != 1 || name != "lspci" {
+		t.Errorf("FirstOf() = %q, %q, %v, want the lspci result", v, name, err)
+	}
+
+	type driver struct{ name, version string }
+	d, name, _ := FirstOf(
+		source("ethtool", driver{}, nil, &calls),
+		source("sysfs", driver{version: "5.1.0-k"}, nil, &calls),
+	)
+	if d.version != "5.1.0-k" || name != "sysfs" {
+		t.Errorf("FirstOf() = %+v from %q, want the partially filled struct from sysfs", d, name)
+	}
+}
+
+func TestFirstOfNoValue(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errDenied := errors.New("permission denied")
+	var calls int
+
+	v, name, err := FirstOf(
+		source("sysfs", 0, errDenied, &calls),
+		source("ethtool", 0, nil, &calls),
+		source("ip", 0, errNotFound, &calls),
+	)
+	if v != 0 || name != "" {
+		t.Errorf("FirstOf() = %d from %q, want no value", v, name)
+	}
+	if !errors.Is(err, errDenied) || !errors.Is(err, errNotFound) {
+		t.Errorf("err = %v, want both failures", err)
+	}
+	if err == nil || err.Error() != "sysfs: permission denied\nip: not found" {
+		t.Errorf("err = %q, want failures prefixed with their source", err)
+	}
+
+	// Every source succeeding with an empty result is not an error.
+	if _, name, err := FirstOf(source("sysfs", "", nil, &calls)); err != nil || name != "" {
+		t.Errorf("FirstOf() = %q, %v, want no source and no error", name, err)
+	}
+	if _, name, err := FirstOf[string](); err != nil || name != "" {
+		t.Errorf("FirstOf() without sources = %q, %v", name, err)
+	}
+}
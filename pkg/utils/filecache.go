@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	content string
+}
+
+var fileCache sync.Map // path -> cachedFile
+
+// CachedReadFile returns the contents of the file at path, reusing the previous read while the
+// file's modification time and size are unchanged. It is meant for regular files read repeatedly,
+// such as pci.ids or os-release; sysfs and procfs attributes do not update their mtime and must be
+// read with [ReadSysfsFile] instead. It is safe for concurrent use.
+func CachedReadFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			NotePermissionDenied(path)
+		}
+		fileCache.Delete(path)
+		return "", err
+	}
+
+	if v, ok := fileCache.Load(path); ok {
+		cached := v.(cachedFile)
+		if cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+			return cached.content, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			NotePermissionDenied(path)
+		}
+		return "", err
+	}
+	if readHook != nil {
+		readHook(path, data)
+	}
+
+	content := string(data)
+	fileCache.Store(path, cachedFile{modTime: info.ModTime(), size: info.Size(), content: content})
+	return content, nil
+}
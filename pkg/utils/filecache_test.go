@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCachedReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "os-release")
+	mtime := time.Unix(1700000000, 0)
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("ID=ubuntu\n", mtime)
+	if got, err := CachedReadFile(path); err != nil || got != "ID=ubuntu\n" {
+		t.Fatalf("CachedReadFile() = %q, %v", got, err)
+	}
+
+	// Same size and mtime: the second read is served from the cache.
+	write("ID=debian\n", mtime)
+	if got, _ := CachedReadFile(path); got != "ID=ubuntu\n" {
+		t.Errorf("CachedReadFile() = %q, want the cached content", got)
+	}
+
+	// A modified mtime forces a re-read.
+	write("ID=debian\n", mtime.Add(time.Second))
+	if got, _ := CachedReadFile(path); got != "ID=debian\n" {
+		t.Errorf("CachedReadFile() = %q after the mtime changed", got)
+	}
+
+	// So does a different size, even with the same mtime.
+	write("ID=rhel\n", mtime.Add(time.Second))
+	if got, _ := CachedReadFile(path); got != "ID=rhel\n" {
+		t.Errorf("CachedReadFile() = %q after the size changed", got)
+	}
+
+	// A removed file is an error and drops the cache entry.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CachedReadFile(path); err == nil {
+		t.Error("CachedReadFile() of a removed file succeeded")
+	}
+	if _, ok := fileCache.Load(path); ok {
+		t.Error("cache entry of a removed file kept")
+	}
+}
+
+func TestCachedReadFileConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pci.ids")
+	if err := os.WriteFile(path, []byte("8086  Intel Corporation\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := CachedReadFile(path); err != nil || got != "8086  Intel Corporation\n" {
+				t.Errorf("CachedReadFile() = %q, %v", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
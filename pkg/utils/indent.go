@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bufio"
+	"strings"
+)
+
+// KVNode is a line of indented "Key: Value" output, with the more deeply indented lines
+// that follow it as children. Lines without the separator, such as list items, have an empty Value.
+type KVNode struct {
+	Key      string
+	Value    string
+	Children []*KVNode
+}
+
+// Child returns the first direct child with the given key, or nil.
+func (n *KVNode) Child(key string) *KVNode {
+	for _, c := range n.Children {
+		if c.Key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+// ChildValue returns the value of the first direct child with the given key, or "".
+func (n *KVNode) ChildValue(key string) string {
+	if c := n.Child(key); c != nil {
+		return c.Value
+	}
+	return ""
+}
+
+// ParseIndentedKeyValue parses output such as dmidecode's, where nesting is expressed by
+// indentation and values may contain the separator or be padded for alignment:
+//
+//	Memory Device
+//		Size: 16 GB
+//		Flags:
+//			Bit 1: set
+//		Configured Memory Speed: 3200 MT/s
+//
+// Only the first occurrence of sep splits key from value, and both are trimmed.
+// A tab counts as eight columns of indentation. Blank lines are skipped.
+func ParseIndentedKeyValue(text string, sep string) []*KVNode {
+	type level struct {
+		indent int
+		node   *KVNode
+	}
+
+	var (
+		roots []*KVNode
+		stack []level
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indent := indentWidth(line)
+		node := &KVNode{Key: strings.TrimSpace(line)}
+		if key, value, ok := strings.Cut(line, sep); ok {
+			node.Key = strings.TrimSpace(key)
+			node.Value = strings.TrimSpace(value)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, level{indent: indent, node: node})
+	}
+
+	return roots
+}
+
+// indentWidth returns the width of the leading whitespace of line.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 8 - width%8
+		default:
+			return width
+		}
+	}
+	return width
+}
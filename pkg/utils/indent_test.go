@@ -0,0 +1,98 @@
+package utils
+
+import "testing"
+
+const dmidecodeMemory = `# dmidecode 3.3
+Getting SMBIOS data from sysfs.
+SMBIOS 3.2.0 present.
+
+Handle 0x1100, DMI type 17, 84 bytes
+Memory Device
+	Array Handle: 0x1000
+	Size: 16 GB
+	Locator: DIMM_A1
+	Speed: 3200 MT/s
+	Manufacturer: Samsung
+	Serial Number: 0x3C1A2B4D
+	Part Number: M393A2K43DB3-CWE    
+	Flags:
+		Bit 1: set
+		Bit 2: not set
+	Configured Memory Speed: 3200 MT/s
+`
+
+func TestParseIndentedKeyValueDmidecode(t *testing.T) {
+	roots := ParseIndentedKeyValue(dmidecodeMemory, ":")
+
+	var device *KVNode
+	for _, r := range roots {
+		if r.Key == "Memory Device" {
+			device = r
+		}
+	}
+	if device == nil {
+		t.Fatalf("no Memory Device section in %d roots", len(roots))
+	}
+	if device.Value != "" {
+		t.Errorf("section value = %q, want none", device.Value)
+	}
+
+	tests := map[string]string{
+		"Array Handle":            "0x1000",
+		"Size":                    "16 GB",
+		"Serial Number":           "0x3C1A2B4D",
+		"Part Number":             "M393A2K43DB3-CWE",
+		"Configured Memory Speed": "3200 MT/s",
+		"Missing":                 "",
+	}
+	for key, want := range tests {
+		if got := device.ChildValue(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	flags := device.Child("Flags")
+	if flags == nil || len(flags.Children) != 2 || flags.ChildValue("Bit 2") != "not set" {
+		t.Errorf("Flags = %+v, want two nested children", flags)
+	}
+	if device.Child("Bit 1") != nil {
+		t.Error("nested child attached to the section")
+	}
+}
+
+func TestParseIndentedKeyValueColonsInValue(t *testing.T) {
+	// ipmitool-style aligned padding, with values containing the separator.
+	out := `Device ID                 : 32
+Firmware Revision         : 2.81
+Manufacturer Name         : Dell Inc.
+Time                      : 01/02/2024 10:20:30
+MAC Address               : 00:1c:73:aa:bb:cc
+`
+	roots := ParseIndentedKeyValue(out, ":")
+	got := map[string]string{}
+	for _, r := range roots {
+		got[r.Key] = r.Value
+	}
+	if got["Time"] != "01/02/2024 10:20:30" || got["MAC Address"] != "00:1c:73:aa:bb:cc" || got["Device ID"] != "32" {
+		t.Errorf("parsed %v", got)
+	}
+	if len(roots) != 5 {
+		t.Errorf("got %d roots, want 5", len(roots))
+	}
+}
+
+func TestParseIndentedKeyValueMixedIndent(t *testing.T) {
+	// A tab is eight columns, the same level as eight spaces.
+	out := "Parent\n\tTab: 1\n        Spaces: 2\n            Deeper: 3\n"
+	roots := ParseIndentedKeyValue(out, ":")
+	if len(roots) != 1 || len(roots[0].Children) != 2 {
+		t.Fatalf("roots = %+v, want one parent with two children", roots)
+	}
+	if roots[0].Children[1].ChildValue("Deeper") != "3" {
+		t.Errorf("Spaces = %+v, want Deeper nested under it", roots[0].Children[1])
+	}
+
+	if got := ParseIndentedKeyValue("\n\n", ":"); got != nil {
+		t.Errorf("ParseIndentedKeyValue(blank) = %+v", got)
+	}
+}
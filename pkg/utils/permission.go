@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"slices"
+	"sync"
+)
+
+var permissionNotes struct {
+	sync.Mutex
+	seen map[string]bool
+	list []string
+}
+
+// permissionHints are substrings tools print when they need root.
+var permissionHints = [][]byte{
+	[]byte("Permission denied"),
+	[]byte("permission denied"),
+	[]byte("Operation not permitted"),
+	[]byte("must be run as root"),
+	[]byte("must be root"),
+	[]byte("requires root"),
+}
+
+// NotePermissionDenied records that what (a file path or command line) could not be read
+// because the process lacks the required privileges. Duplicate notes are recorded once.
+func NotePermissionDenied(what string) {
+	permissionNotes.Lock()
+	defer permissionNotes.Unlock()
+
+	if permissionNotes.seen == nil {
+		permissionNotes.seen = make(map[string]bool)
+	}
+	if permissionNotes.seen[what] {
+		return
+	}
+	permissionNotes.seen[what] = true
+	permissionNotes.list = append(permissionNotes.list, what)
+}
+
+// TakePermissionWarnings returns the notes recorded by [NotePermissionDenied] and resets the recorder.
+func TakePermissionWarnings() []string {
+	permissionNotes.Lock()
+	defer permissionNotes.Unlock()
+
+	list := permissionNotes.list
+	permissionNotes.list = nil
+	permissionNotes.seen = nil
+	slices.Sort(list)
+	return list
+}
+
+// IsPermissionError reports whether err, or the output of the tool that produced it,
+// indicates that the operation failed because of insufficient privileges.
+func IsPermissionError(err error, output []byte) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, fs.ErrPermission) {
+		return true
+	}
+	for _, hint := range permissionHints {
+		if bytes.Contains(output, hint) {
+			return true
+		}
+	}
+	return false
+}
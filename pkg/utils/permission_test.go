@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+func TestIsPermissionError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		err    error
+		output string
+		want   bool
+	}{
+		{"success", nil, "Permission denied", false},
+		{"fs error", fmt.Errorf("open: %w", fs.ErrPermission), "", true},
+		{"dmidecode", exitErr, "/dev/mem: Permission denied\n", true},
+		{"smartctl", exitErr, "Smartctl open device: /dev/sda failed: Operation not permitted", true},
+		{"ipmitool", exitErr, "This program must be run as root", true},
+		{"other failure", exitErr, "No such device", false},
+	}
+	for _, tt := range tests {
+		if got := IsPermissionError(tt.err, []byte(tt.output)); got != tt.want {
+			t.Errorf("%s: IsPermissionError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPermissionWarnings(t *testing.T) {
+	_ = TakePermissionWarnings()
+
+	NotePermissionDenied("/sys/class/dmi/id/product_serial")
+	NotePermissionDenied("dmidecode -t 17")
+	NotePermissionDenied("/sys/class/dmi/id/product_serial")
+
+	want := []string{"/sys/class/dmi/id/product_serial", "dmidecode -t 17"}
+	if got := TakePermissionWarnings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TakePermissionWarnings() = %q, want %q", got, want)
+	}
+	if got := TakePermissionWarnings(); got != nil {
+		t.Errorf("TakePermissionWarnings() after reset = %q, want none", got)
+	}
+}
+
+func TestReadSysfsFilePermissionDenied(t *testing.T) {
+	_ = TakePermissionWarnings()
+	saved := readFile
+	readFile = func(name string) ([]byte, error) {
+		if name == "/sys/class/dmi/id/product_serial" {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+		}
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	t.Cleanup(func() { readFile = saved })
+
+	if _, err := ReadSysfsFile("/sys/class/dmi/id/product_serial"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("ReadSysfsFile() error = %v, want fs.ErrPermission", err)
+	}
+	if _, err := ReadSysfsFile("/sys/class/dmi/id/chassis_serial"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadSysfsFile() error = %v, want fs.ErrNotExist", err)
+	}
+
+	// Only the permission failure is noted; a missing file is not worth a warning.
+	if got, want := TakePermissionWarnings(), []string{"/sys/class/dmi/id/product_serial"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TakePermissionWarnings() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+)
+
+// Profile controls how deeply collectors probe each module.
+type Profile string
+
+const (
+	// ProfileFull runs every probe. It is the default.
+	ProfileFull Profile = "full"
+	// ProfileMinimal keeps only cheap, fast-changing metrics such as memory usage,
+	// load and link status, and skips expensive static probes like ethtool, LLDP,
+	// PCI link reads, dmidecode and SMART. It is meant for high-frequency polling.
+	ProfileMinimal Profile = "minimal"
+)
+
+// ParseProfile returns the profile named by s. An empty string selects ProfileFull.
+func ParseProfile(s string) (Profile, error) {
+	switch Profile(s) {
+	case "", ProfileFull:
+		return ProfileFull, nil
+	case ProfileMinimal:
+		return ProfileMinimal, nil
+	default:
+		return "", fmt.Errorf("unknown profile %q, supported: %s, %s", s, ProfileFull, ProfileMinimal)
+	}
+}
+
+type profileKey struct{}
+
+// WithProfile returns a copy of ctx carrying p, collectors read it through [ProfileFrom].
+func WithProfile(ctx context.Context, p Profile) context.Context {
+	return context.WithValue(ctx, profileKey{}, p)
+}
+
+// ProfileFrom returns the profile carried by ctx, or ProfileFull when none is set.
+func ProfileFrom(ctx context.Context) Profile {
+	if p, ok := ctx.Value(profileKey{}).(Profile); ok && p != "" {
+		return p
+	}
+	return ProfileFull
+}
+
+// IsMinimal reports whether ctx carries ProfileMinimal.
+func IsMinimal(ctx context.Context) bool {
+	return ProfileFrom(ctx) == ProfileMinimal
+}
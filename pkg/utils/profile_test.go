@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseProfile(t *testing.T) {
+	tests := map[string]Profile{"": ProfileFull, "full": ProfileFull, "minimal": ProfileMinimal}
+	for in, want := range tests {
+		if got, err := ParseProfile(in); err != nil || got != want {
+			t.Errorf("ParseProfile(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseProfile("fast"); err == nil {
+		t.Error("ParseProfile(fast) succeeded")
+	}
+}
+
+func TestProfileFrom(t *testing.T) {
+	if got := ProfileFrom(context.Background()); got != ProfileFull {
+		t.Errorf("ProfileFrom() without a profile = %q, want full", got)
+	}
+	if IsMinimal(context.Background()) {
+		t.Error("IsMinimal() without a profile")
+	}
+
+	ctx := WithProfile(context.Background(), ProfileMinimal)
+	if got := ProfileFrom(ctx); got != ProfileMinimal || !IsMinimal(ctx) {
+		t.Errorf("ProfileFrom() = %q, want minimal", got)
+	}
+	if got := ProfileFrom(WithProfile(context.Background(), "")); got != ProfileFull {
+		t.Errorf("ProfileFrom() with an empty profile = %q, want full", got)
+	}
+}
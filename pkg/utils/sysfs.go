@@ -1,17 +1,59 @@
 package utils
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
+var root = "/"
+
+// SetRoot sets the directory that absolute sysfs/procfs paths are resolved against by [HostPath],
+// e.g. a bind-mounted host filesystem or a captured snapshot. An empty dir restores "/".
+// It is not safe to call concurrently with collection.
+func SetRoot(dir string) {
+	if dir == "" {
+		dir = "/"
+	}
+	root = dir
+}
+
+// HostPath resolves path against the root set by [SetRoot].
+func HostPath(path string) string {
+	if root == "/" {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+var readHook func(path string, data []byte)
+
+// SetReadHook registers fn to be called with the raw contents of every file successfully read
+// by [ReadSysfsFile], e.g. to capture them for later replay. Passing nil removes the hook.
+// It is not safe to call concurrently with collection.
+func SetReadHook(fn func(path string, data []byte)) {
+	readHook = fn
+}
+
+// readFile reads the files behind [ReadSysfsFile]; tests replace it to simulate
+// files that only root can read, since the permission check does not apply to root.
+var readFile = os.ReadFile
+
 // ReadSysfsFile reads a file from the sysfs and returns its contents as a string.
 func ReadSysfsFile(path string) (string, error) {
-	data, err := os.ReadFile(path)
+	data, err := readFile(path)
 	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			NotePermissionDenied(path)
+		}
 		return "", err
 	}
+	if readHook != nil {
+		readHook(path, data)
+	}
 	return strings.TrimSpace(string(data)), nil
 }
 
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Warning is a non-fatal problem met during collection, e.g. a missing tool or an unreadable device.
+type Warning struct {
+	Module  string `json:"module"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return w.Module + ": " + w.Message
+}
+
+// Warnings accumulates [Warning]s from concurrently running collectors.
+// A nil *Warnings discards everything added to it.
+type Warnings struct {
+	mu   sync.Mutex
+	list []Warning
+}
+
+// Add records err as a warning of module. A nil err is ignored.
+func (w *Warnings) Add(module string, err error) {
+	if w == nil || err == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.list = append(w.list, Warning{Module: module, Message: err.Error()})
+}
+
+// Addf records a formatted warning of module.
+func (w *Warnings) Addf(module, format string, args ...any) {
+	w.Add(module, fmt.Errorf(format, args...))
+}
+
+// List returns the recorded warnings in the order they were added.
+func (w *Warnings) List() []Warning {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Warning(nil), w.list...)
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns a copy of ctx carrying w, collectors record non-fatal problems to it
+// through [WarningsFrom].
+func WithWarnings(ctx context.Context, w *Warnings) context.Context {
+	return context.WithValue(ctx, warningsKey{}, w)
+}
+
+// WarningsFrom returns the accumulator carried by ctx, or nil, which discards warnings.
+func WarningsFrom(ctx context.Context) *Warnings {
+	w, _ := ctx.Value(warningsKey{}).(*Warnings)
+	return w
+}
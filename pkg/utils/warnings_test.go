@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	w := &Warnings{}
+	w.Add("gpu", errors.New("nvidia-smi not found"))
+	w.Add("disk", nil)
+	w.Addf("disk", "read %s failed", "/dev/sdb")
+
+	want := []Warning{
+		{Module: "gpu", Message: "nvidia-smi not found"},
+		{Module: "disk", Message: "read /dev/sdb failed"},
+	}
+	got := w.List()
+	if !slices.Equal(got, want) {
+		t.Fatalf("List() = %v, want %v", got, want)
+	}
+	if got[0].String() != "gpu: nvidia-smi not found" {
+		t.Errorf("String() = %q", got[0].String())
+	}
+
+	// List returns a copy.
+	got[0].Module = "changed"
+	if w.List()[0].Module != "gpu" {
+		t.Error("modifying the result of List() changed the accumulator")
+	}
+}
+
+func TestWarningsConcurrent(t *testing.T) {
+	w := &Warnings{}
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Addf("network", "eth0 unreadable")
+		}()
+	}
+	wg.Wait()
+
+	if n := len(w.List()); n != 50 {
+		t.Errorf("recorded %d warnings, want 50", n)
+	}
+}
+
+func TestWarningsFromContext(t *testing.T) {
+	// Without an accumulator warnings are discarded.
+	discard := WarningsFrom(context.Background())
+	discard.Add("gpu", errors.New("ignored"))
+	if discard.List() != nil {
+		t.Errorf("nil accumulator List() = %v", discard.List())
+	}
+
+	w := &Warnings{}
+	ctx := WithWarnings(context.Background(), w)
+	WarningsFrom(ctx).Add("gpu", errors.New("nvidia-smi not found"))
+	if len(w.List()) != 1 {
+		t.Errorf("warning added through the context not recorded: %v", w.List())
+	}
+}